@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/Unlites/comparison_center/backend/config"
+	"github.com/Unlites/comparison_center/backend/internal/adapters/backuparchive"
+	"github.com/Unlites/comparison_center/backend/internal/adapters/photostore"
+	cr "github.com/Unlites/comparison_center/backend/internal/adapters/repositories/comparison"
+	cor "github.com/Unlites/comparison_center/backend/internal/adapters/repositories/customoption"
+	fr "github.com/Unlites/comparison_center/backend/internal/adapters/repositories/folder"
+	or "github.com/Unlites/comparison_center/backend/internal/adapters/repositories/object"
+	ocor "github.com/Unlites/comparison_center/backend/internal/adapters/repositories/object_customoption"
+	pr "github.com/Unlites/comparison_center/backend/internal/adapters/repositories/photo"
+	"github.com/Unlites/comparison_center/backend/internal/adapters/repositories/transactor"
+	bu "github.com/Unlites/comparison_center/backend/internal/application/backup"
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	g "github.com/Unlites/comparison_center/backend/pkg/generator"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const usage = `Usage:
+  backup dump [-file backup.tar.gz]
+  backup restore [-file backup.tar.gz] [-remap-ids]
+
+The archive is written to stdout and read from stdin when -file is "-" (default).
+`
+
+func main() {
+	// stdout may be taken by the archive, so logs go to stderr
+	log := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	file := flags.String("file", "-", "path of the archive")
+	remapIds := flags.Bool("remap-ids", false, "give new ids to restored entities")
+	flags.Parse(os.Args[2:])
+
+	if os.Args[1] != "dump" && os.Args[1] != "restore" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Error("failed to init config", "detail", err)
+		os.Exit(1)
+	}
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.DB.URI))
+	if err != nil {
+		log.Error("failed to connect to mongodb", "detail", err)
+		os.Exit(1)
+	}
+	defer client.Disconnect(ctx)
+
+	photoStore, err := photostore.NewPhotoStore(
+		ctx,
+		cfg.PhotoStorage.Type,
+		cfg.PhotoStorage.Dir,
+		photostore.S3Config(cfg.PhotoStorage.S3),
+	)
+	if err != nil {
+		log.Error("failed to init photo storage", "detail", err)
+		os.Exit(1)
+	}
+
+	backupUsecase := bu.NewBackupUsecase(
+		fr.NewFolderRepositoryMongo(client),
+		cr.NewComparisonRepositoryMongo(client),
+		cor.NewCustomOptionRepositoryMongo(client),
+		or.NewObjectRepositoryMongo(client),
+		ocor.NewObjectCustomOptionRepositoryMongo(client),
+		pr.NewPhotoRepositoryMongo(client),
+		photoStore,
+		transactor.NewTransactorMongo(client),
+		g.NewGenerator(),
+	)
+
+	if os.Args[1] == "dump" {
+		err = dump(ctx, log, backupUsecase, *file)
+	} else {
+		err = restore(ctx, log, backupUsecase, *file, *remapIds)
+	}
+
+	if err != nil {
+		client.Disconnect(ctx)
+		os.Exit(1)
+	}
+}
+
+func dump(ctx context.Context, log *slog.Logger, uc *bu.BackupUsecase, file string) error {
+	out := io.WriteCloser(os.Stdout)
+	if file != "-" {
+		f, err := os.Create(file)
+		if err != nil {
+			log.Error("failed to create archive file", "detail", err)
+			return err
+		}
+
+		out = f
+	}
+	defer out.Close()
+
+	archive := backuparchive.NewTarGzWriter(out)
+
+	manifest, photoFiles, err := uc.Dump(ctx, archive)
+	if err != nil {
+		log.Error("failed to dump backup", "detail", err)
+		return err
+	}
+
+	if err := archive.Close(); err != nil {
+		log.Error("failed to finish archive", "detail", err)
+		return err
+	}
+
+	logManifest(log, "backup dumped", manifest, photoFiles)
+
+	return nil
+}
+
+func restore(ctx context.Context, log *slog.Logger, uc *bu.BackupUsecase, file string, remapIds bool) error {
+	in := io.ReadCloser(os.Stdin)
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			log.Error("failed to open archive file", "detail", err)
+			return err
+		}
+
+		in = f
+	}
+	defer in.Close()
+
+	archive, err := backuparchive.NewTarGzReader(in)
+	if err != nil {
+		log.Error("failed to open archive", "detail", err)
+		return err
+	}
+	defer archive.Close()
+
+	manifest, photoFiles, err := uc.Restore(ctx, archive, remapIds)
+	if err != nil {
+		log.Error("failed to restore backup", "detail", err)
+		return err
+	}
+
+	logManifest(log, "backup restored", manifest, photoFiles)
+
+	return nil
+}
+
+func logManifest(log *slog.Logger, msg string, manifest domain.BackupManifest, photoFiles int) {
+	log.Info(
+		msg,
+		"version", manifest.Version,
+		"created_at", manifest.CreatedAt,
+		"folders", manifest.Folders,
+		"comparisons", manifest.Comparisons,
+		"custom_options", manifest.CustomOptions,
+		"objects", manifest.Objects,
+		"object_custom_options", manifest.ObjectCustomOptions,
+		"photos", manifest.Photos,
+		"photo_files", photoFiles,
+	)
+}
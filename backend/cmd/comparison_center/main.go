@@ -11,17 +11,45 @@ import (
 	"syscall"
 
 	"github.com/Unlites/comparison_center/backend/config"
+	"github.com/Unlites/comparison_center/backend/internal/adapters/builtintemplates"
 	ch "github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/comparison"
 	coh "github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/customoption"
+	fh "github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/folder"
 	"github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/middleware"
 	oh "github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/object"
+	sh "github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/search"
+	tgh "github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/tag"
+	tmh "github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/template"
+	th "github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/trash"
+	pgcj "github.com/Unlites/comparison_center/backend/internal/adapters/jobs/photogc"
+	tj "github.com/Unlites/comparison_center/backend/internal/adapters/jobs/trash"
+	"github.com/Unlites/comparison_center/backend/internal/adapters/photoprocessor"
+	"github.com/Unlites/comparison_center/backend/internal/adapters/photostore"
+	ar "github.com/Unlites/comparison_center/backend/internal/adapters/repositories/audit"
 	cr "github.com/Unlites/comparison_center/backend/internal/adapters/repositories/comparison"
 	cor "github.com/Unlites/comparison_center/backend/internal/adapters/repositories/customoption"
+	fr "github.com/Unlites/comparison_center/backend/internal/adapters/repositories/folder"
 	or "github.com/Unlites/comparison_center/backend/internal/adapters/repositories/object"
 	ocor "github.com/Unlites/comparison_center/backend/internal/adapters/repositories/object_customoption"
+	pr "github.com/Unlites/comparison_center/backend/internal/adapters/repositories/photo"
+	tmr "github.com/Unlites/comparison_center/backend/internal/adapters/repositories/template"
+	"github.com/Unlites/comparison_center/backend/internal/adapters/repositories/transactor"
+	tr "github.com/Unlites/comparison_center/backend/internal/adapters/repositories/trash"
+	au "github.com/Unlites/comparison_center/backend/internal/application/audit"
 	cu "github.com/Unlites/comparison_center/backend/internal/application/comparison"
 	cou "github.com/Unlites/comparison_center/backend/internal/application/customoption"
+	eu "github.com/Unlites/comparison_center/backend/internal/application/export"
+	fu "github.com/Unlites/comparison_center/backend/internal/application/folder"
+	mu "github.com/Unlites/comparison_center/backend/internal/application/matrix"
 	ou "github.com/Unlites/comparison_center/backend/internal/application/object"
+	oiu "github.com/Unlites/comparison_center/backend/internal/application/objectimport"
+	pu "github.com/Unlites/comparison_center/backend/internal/application/photo"
+	pgcu "github.com/Unlites/comparison_center/backend/internal/application/photogc"
+	su "github.com/Unlites/comparison_center/backend/internal/application/scoring"
+	seu "github.com/Unlites/comparison_center/backend/internal/application/search"
+	tgu "github.com/Unlites/comparison_center/backend/internal/application/tag"
+	tmu "github.com/Unlites/comparison_center/backend/internal/application/template"
+	tu "github.com/Unlites/comparison_center/backend/internal/application/trash"
 	g "github.com/Unlites/comparison_center/backend/pkg/generator"
 	"github.com/Unlites/comparison_center/backend/pkg/metrics"
 	"github.com/Unlites/comparison_center/backend/pkg/parser"
@@ -61,25 +89,173 @@ func main() {
 
 	generator := g.NewGenerator()
 
-	comparisonRepository := cr.NewComparisonRepositoryMongo(client)
-	comparisonUsecase := cu.NewComparisonUsecase(comparisonRepository, generator)
-	comparisonHandler := ch.NewComparisonHandler(comparisonUsecase)
+	photoStore, err := photostore.NewPhotoStore(
+		ctx,
+		cfg.PhotoStorage.Type,
+		cfg.PhotoStorage.Dir,
+		photostore.S3Config(cfg.PhotoStorage.S3),
+	)
+	if err != nil {
+		log.Error("failed to init photo storage", "detail", err)
+		os.Exit(1)
+	}
 
+	comparisonRepository := cr.NewComparisonRepositoryMongo(client)
 	customOptionRepository := cor.NewCustomOptionRepositoryMongo(client)
-	customOptionUsecase := cou.NewCustomOptionUsecase(customOptionRepository, generator)
-	customOptionHandler := coh.NewCustomOptionHandler(customOptionUsecase)
-
 	objectRepository := or.NewObjectRepositoryMongo(client)
 	objectCustomOptionRepository := ocor.NewObjectCustomOptionRepositoryMongo(client)
-	objectUsecase := ou.NewObjectUsecase(objectRepository, objectCustomOptionRepository, generator)
-	objectHandler := oh.NewObjectHandler(objectUsecase, cfg.PhotosDir, cfg.MaxUploadSizeMB)
+	photoRepository := pr.NewPhotoRepositoryMongo(client)
+	auditRepository := ar.NewAuditRepositoryMongo(client)
+	templateRepository := tmr.NewTemplateRepositoryMongo(client)
+	folderRepository := fr.NewFolderRepositoryMongo(client)
+	trashRepository := tr.NewTrashRepositoryMongo(client)
+	transactor := transactor.NewTransactorMongo(client)
+
+	auditUsecase := au.NewAuditUsecase(auditRepository)
+
+	comparisonUsecase := cu.NewComparisonUsecase(
+		comparisonRepository,
+		objectRepository,
+		objectCustomOptionRepository,
+		customOptionRepository,
+		photoRepository,
+		templateRepository,
+		folderRepository,
+		auditRepository,
+		photoStore,
+		transactor,
+		generator,
+	)
+	scoringUsecase := su.NewScoringUsecase(
+		comparisonRepository,
+		objectRepository,
+		objectCustomOptionRepository,
+		customOptionRepository,
+	)
+	matrixUsecase := mu.NewMatrixUsecase(comparisonRepository, objectRepository, customOptionRepository)
+	importUsecase := oiu.NewImportUsecase(
+		comparisonRepository,
+		objectRepository,
+		objectCustomOptionRepository,
+		customOptionRepository,
+		auditRepository,
+		transactor,
+		generator,
+	)
+	exportUsecase := eu.NewExportUsecase()
+	comparisonHandler := ch.NewComparisonHandler(
+		comparisonUsecase,
+		scoringUsecase,
+		matrixUsecase,
+		importUsecase,
+		exportUsecase,
+		auditUsecase,
+		cfg.MaxUploadSizeMB,
+	)
+
+	customOptionUsecase := cou.NewCustomOptionUsecase(
+		customOptionRepository,
+		comparisonRepository,
+		objectRepository,
+		objectCustomOptionRepository,
+		auditRepository,
+		transactor,
+		generator,
+	)
+	customOptionHandler := coh.NewCustomOptionHandler(customOptionUsecase)
+
+	objectUsecase := ou.NewObjectUsecase(
+		objectRepository,
+		objectCustomOptionRepository,
+		customOptionRepository,
+		comparisonRepository,
+		auditRepository,
+		transactor,
+		generator,
+	)
+	photoUsecase := pu.NewPhotoUsecase(
+		objectRepository,
+		photoRepository,
+		photoStore,
+		photoprocessor.NewImageProcessor(cfg.PhotoStorage.MaxPixels),
+		transactor,
+		generator,
+	)
+	objectHandler := oh.NewObjectHandler(objectUsecase, photoUsecase, auditUsecase, cfg.MaxUploadSizeMB)
+
+	trashUsecase := tu.NewTrashUsecase(
+		trashRepository,
+		comparisonRepository,
+		objectRepository,
+		objectCustomOptionRepository,
+		photoRepository,
+		photoStore,
+		transactor,
+		cfg.Trash.Retention(),
+	)
+	trashHandler := th.NewTrashHandler(trashUsecase)
+
+	templateUsecase := tmu.NewTemplateUsecase(templateRepository, generator)
+	templateHandler := tmh.NewTemplateHandler(templateUsecase)
+
+	folderUsecase := fu.NewFolderUsecase(folderRepository, comparisonRepository, transactor, generator)
+	folderHandler := fh.NewFolderHandler(folderUsecase)
+
+	tagUsecase := tgu.NewTagUsecase(comparisonRepository)
+	tagHandler := tgh.NewTagHandler(tagUsecase)
+
+	searchUsecase := seu.NewSearchUsecase(
+		comparisonRepository,
+		objectRepository,
+		objectCustomOptionRepository,
+		customOptionRepository,
+		cfg.Search.Timeout,
+	)
+	searchHandler := sh.NewSearchHandler(searchUsecase)
+
+	builtInTemplates, err := builtintemplates.Load()
+	if err != nil {
+		log.Error("failed to load built-in templates", "detail", err)
+		os.Exit(1)
+	}
+
+	if err := templateUsecase.SeedBuiltInTemplates(ctx, builtInTemplates); err != nil {
+		log.Error("failed to seed built-in templates", "detail", err)
+	}
+
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+
+	if cfg.PhotoGC.Enabled {
+		photoGCUsecase := pgcu.NewPhotoGCUsecase(photoRepository, photoStore, cfg.PhotoGC.GracePeriod)
+		photoGCJob := pgcj.NewJob(photoGCUsecase, cfg.PhotoGC.Interval, cfg.PhotoGC.DryRun, log)
+
+		go func() {
+			log.Info("starting photo gc job", "interval", cfg.PhotoGC.Interval, "dry_run", cfg.PhotoGC.DryRun)
+			photoGCJob.Run(jobsCtx)
+			log.Info("photo gc job stopped")
+		}()
+	}
+
+	trashJob := tj.NewJob(trashUsecase, cfg.Trash.PurgeInterval, log)
+
+	go func() {
+		log.Info("starting trash purge job", "interval", cfg.Trash.PurgeInterval, "retention_days", cfg.Trash.RetentionDays)
+		trashJob.Run(jobsCtx)
+		log.Info("trash purge job stopped")
+	}()
 
 	router := r.NewDefaultRouter()
 	router.Handler.Use(middleware.Metrics)
 	router.RegisterHandlers("v1", map[string]http.Handler{
 		"comparisons":    comparisonHandler,
 		"custom_options": customOptionHandler,
+		"folders":        folderHandler,
 		"objects":        objectHandler,
+		"search":         searchHandler,
+		"tags":           tagHandler,
+		"templates":      templateHandler,
+		"trash":          trashHandler,
 	})
 
 	srv := &http.Server{
@@ -103,6 +279,8 @@ func main() {
 	<-notifyCtx.Done()
 	log.Info("service gracefully stopping...")
 
+	stopJobs()
+
 	shutDownCtx, cancel := context.WithTimeout(ctx, cfg.HttpServer.ShutdownTimeout)
 	defer cancel()
 
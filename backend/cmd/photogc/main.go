@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+
+	"github.com/Unlites/comparison_center/backend/config"
+	pgcj "github.com/Unlites/comparison_center/backend/internal/adapters/jobs/photogc"
+	"github.com/Unlites/comparison_center/backend/internal/adapters/photostore"
+	pr "github.com/Unlites/comparison_center/backend/internal/adapters/repositories/photo"
+	pgcu "github.com/Unlites/comparison_center/backend/internal/application/photogc"
+	"github.com/Unlites/comparison_center/backend/pkg/parser"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only report orphaned photos without deleting them, photo_gc.dry_run of config enables it as well")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		slog.Error("failed to init config", "detail", err)
+		os.Exit(1)
+	}
+
+	log := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: parser.ParseSlogLevel(cfg.LogLevel),
+	}))
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.DB.URI))
+	if err != nil {
+		log.Error("failed to connect to mongodb", "detail", err)
+		os.Exit(1)
+	}
+	defer client.Disconnect(ctx)
+
+	photoStore, err := photostore.NewPhotoStore(
+		ctx,
+		cfg.PhotoStorage.Type,
+		cfg.PhotoStorage.Dir,
+		photostore.S3Config(cfg.PhotoStorage.S3),
+	)
+	if err != nil {
+		log.Error("failed to init photo storage", "detail", err)
+		os.Exit(1)
+	}
+
+	// dry run asked by either the flag or the config wins, so photos are never deleted by surprise
+	*dryRun = *dryRun || cfg.PhotoGC.DryRun
+
+	photoGCUsecase := pgcu.NewPhotoGCUsecase(
+		pr.NewPhotoRepositoryMongo(client),
+		photoStore,
+		cfg.PhotoGC.GracePeriod,
+	)
+
+	if _, err := pgcj.NewJob(photoGCUsecase, cfg.PhotoGC.Interval, *dryRun, log).RunOnce(ctx); err != nil {
+		client.Disconnect(ctx)
+		os.Exit(1)
+	}
+}
@@ -24,12 +24,56 @@ type DB struct {
 	MigrationsDir string `yaml:"migrations_dir"`
 }
 
+type PhotoStorage struct {
+	Type      string `yaml:"type" env:"PHOTO_STORAGE_TYPE" env-default:"filesystem"`
+	Dir       string `yaml:"dir"`
+	MaxPixels int64  `yaml:"max_pixels" env-default:"40000000"`
+	S3        S3     `yaml:"s3"`
+}
+
+type S3 struct {
+	Endpoint  string `yaml:"endpoint" env:"S3_ENDPOINT"`
+	AccessKey string `yaml:"access_key" env:"S3_ACCESS_KEY"`
+	SecretKey string `yaml:"secret_key" env:"S3_SECRET_KEY"`
+	Bucket    string `yaml:"bucket" env:"S3_BUCKET"`
+	Region    string `yaml:"region" env:"S3_REGION"`
+	UseSSL    bool   `yaml:"use_ssl" env:"S3_USE_SSL"`
+}
+
+type PhotoGC struct {
+	Enabled     bool          `yaml:"enabled" env:"PHOTO_GC_ENABLED"`
+	Interval    time.Duration `yaml:"interval" env-default:"6h"`
+	GracePeriod time.Duration `yaml:"grace_period" env-default:"24h"`
+	DryRun      bool          `yaml:"dry_run" env:"PHOTO_GC_DRY_RUN"`
+}
+
+type Trash struct {
+	RetentionDays int           `yaml:"retention_days" env:"TRASH_RETENTION_DAYS" env-default:"30"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+}
+
+// Retention is how long deleted comparisons and objects are kept in the trash.
+func (t Trash) Retention() time.Duration {
+	return time.Duration(t.RetentionDays) * 24 * time.Hour
+}
+
+type Search struct {
+	Timeout time.Duration `yaml:"timeout" env:"SEARCH_TIMEOUT" env-default:"5s"`
+}
+
 type Config struct {
 	HttpServer     `yaml:"http_server"`
 	MetricsAddress string `yaml:"metrics_address"`
 	DB             `yaml:"db"`
-	PhotosDir      string `yaml:"photos_dir"`
-	LogLevel       string `yaml:"log_level"`
+	PhotoStorage   PhotoStorage `yaml:"photo_storage"`
+	PhotoGC        PhotoGC      `yaml:"photo_gc"`
+	Trash          Trash        `yaml:"trash"`
+	Search         Search       `yaml:"search"`
+	LogLevel       string       `yaml:"log_level"`
+
+	// PhotosDir is the photos directory set by configs written before photo_storage section,
+	// it is used when photo_storage.dir is not set.
+	PhotosDir string `yaml:"photos_dir"`
 }
 
 func NewConfig() (*Config, error) {
@@ -44,5 +88,17 @@ func NewConfig() (*Config, error) {
 		return nil, fmt.Errorf("error while reading config: %w", err)
 	}
 
+	if cfg.PhotosDir != "" {
+		if cfg.PhotoStorage.Dir != "" && cfg.PhotoStorage.Dir != cfg.PhotosDir {
+			return nil, errors.New("photos_dir and photo_storage.dir are both set, keep only photo_storage.dir")
+		}
+
+		cfg.PhotoStorage.Dir = cfg.PhotosDir
+	}
+
+	if cfg.Trash.RetentionDays < 1 {
+		return nil, errors.New("trash retention_days must be at least 1")
+	}
+
 	return &cfg, nil
 }
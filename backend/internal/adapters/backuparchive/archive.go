@@ -0,0 +1,464 @@
+package backuparchive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+)
+
+const (
+	manifestName = "manifest.json"
+	dataName     = "data.json"
+	photosDir    = "photos"
+)
+
+type manifestJSON struct {
+	Version             int       `json:"version"`
+	CreatedAt           time.Time `json:"created_at"`
+	Folders             int       `json:"folders"`
+	Comparisons         int       `json:"comparisons"`
+	CustomOptions       int       `json:"custom_options"`
+	Objects             int       `json:"objects"`
+	ObjectCustomOptions int       `json:"object_custom_options"`
+	Photos              int       `json:"photos"`
+}
+
+type dataJSON struct {
+	Folders             []folderJSON             `json:"folders"`
+	Comparisons         []comparisonJSON         `json:"comparisons"`
+	CustomOptions       []customOptionJSON       `json:"custom_options"`
+	Objects             []objectJSON             `json:"objects"`
+	ObjectCustomOptions []objectCustomOptionJSON `json:"object_custom_options"`
+	Photos              []photoJSON              `json:"photos"`
+}
+
+type folderJSON struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	ParentId  string    `json:"parent_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type comparisonJSON struct {
+	Id              string             `json:"id"`
+	Name            string             `json:"name"`
+	CreatedAt       time.Time          `json:"created_at"`
+	CustomOptionIds []string           `json:"custom_option_ids"`
+	OptionWeights   []optionWeightJSON `json:"option_weights"`
+	Tags            []string           `json:"tags,omitempty"`
+	FolderId        string             `json:"folder_id,omitempty"`
+}
+
+type optionWeightJSON struct {
+	CustomOptionId string  `json:"custom_option_id"`
+	Weight         float64 `json:"weight"`
+	Direction      string  `json:"direction"`
+}
+
+type customOptionJSON struct {
+	Id         string   `json:"id"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	EnumValues []string `json:"enum_values,omitempty"`
+	Currency   string   `json:"currency,omitempty"`
+}
+
+type objectJSON struct {
+	Id           string    `json:"id"`
+	Name         string    `json:"name"`
+	Rating       int       `json:"rating"`
+	CreatedAt    time.Time `json:"created_at"`
+	Advs         string    `json:"advs"`
+	Disadvs      string    `json:"disadvs"`
+	ComparisonId string    `json:"comparison_id"`
+}
+
+// objectCustomOptionJSON keeps the canonical representation of the value,
+// typed value is parsed from it on restore.
+type objectCustomOptionJSON struct {
+	ObjectId       string `json:"object_id"`
+	CustomOptionId string `json:"custom_option_id"`
+	Value          string `json:"value"`
+}
+
+type photoJSON struct {
+	Id          string    `json:"id"`
+	ObjectId    string    `json:"object_id"`
+	Key         string    `json:"key"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	Position    int       `json:"position"`
+	IsCover     bool      `json:"is_cover"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TarGzWriter writes the backup as a gzipped tar with manifest.json, data.json holding
+// all entities and files of photos in photos directory, in that order, so the archive
+// can be restored as a stream.
+type TarGzWriter struct {
+	gzipWriter *gzip.Writer
+	tarWriter  *tar.Writer
+}
+
+func NewTarGzWriter(w io.Writer) *TarGzWriter {
+	gzipWriter := gzip.NewWriter(w)
+
+	return &TarGzWriter{
+		gzipWriter: gzipWriter,
+		tarWriter:  tar.NewWriter(gzipWriter),
+	}
+}
+
+func (a *TarGzWriter) WriteBackup(manifest domain.BackupManifest, backup domain.Backup) error {
+	if err := a.writeJSON(manifestName, toManifestJSON(manifest), manifest.CreatedAt); err != nil {
+		return err
+	}
+
+	return a.writeJSON(dataName, toDataJSON(backup), manifest.CreatedAt)
+}
+
+func (a *TarGzWriter) WritePhoto(name string, size int64, content io.Reader) error {
+	err := a.tarWriter.WriteHeader(&tar.Header{
+		Name:    path.Join(photosDir, name),
+		Mode:    0o644,
+		Size:    size,
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("write tar header error: %w", err)
+	}
+
+	if _, err := io.Copy(a.tarWriter, content); err != nil {
+		return fmt.Errorf("write tar content error: %w", err)
+	}
+
+	return nil
+}
+
+// Close finishes the archive, it is not valid until closed.
+func (a *TarGzWriter) Close() error {
+	if err := a.tarWriter.Close(); err != nil {
+		return fmt.Errorf("close tar error: %w", err)
+	}
+
+	if err := a.gzipWriter.Close(); err != nil {
+		return fmt.Errorf("close gzip error: %w", err)
+	}
+
+	return nil
+}
+
+func (a *TarGzWriter) writeJSON(name string, v any, modTime time.Time) error {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal %s error: %w", name, err)
+	}
+
+	err = a.tarWriter.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(content)),
+		ModTime: modTime,
+	})
+	if err != nil {
+		return fmt.Errorf("write tar header error: %w", err)
+	}
+
+	if _, err := a.tarWriter.Write(content); err != nil {
+		return fmt.Errorf("write tar content error: %w", err)
+	}
+
+	return nil
+}
+
+type TarGzReader struct {
+	gzipReader *gzip.Reader
+	tarReader  *tar.Reader
+}
+
+func NewTarGzReader(r io.Reader) (*TarGzReader, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("open gzip error: %w", err)
+	}
+
+	return &TarGzReader{
+		gzipReader: gzipReader,
+		tarReader:  tar.NewReader(gzipReader),
+	}, nil
+}
+
+func (a *TarGzReader) ReadBackup() (domain.BackupManifest, domain.Backup, error) {
+	var manifest manifestJSON
+	if err := a.readJSON(manifestName, &manifest); err != nil {
+		return domain.BackupManifest{}, domain.Backup{}, err
+	}
+
+	// data of other versions may have another layout
+	if manifest.Version != domain.BackupVersion {
+		return toDomainManifest(manifest), domain.Backup{}, nil
+	}
+
+	var data dataJSON
+	if err := a.readJSON(dataName, &data); err != nil {
+		return domain.BackupManifest{}, domain.Backup{}, err
+	}
+
+	return toDomainManifest(manifest), toDomainBackup(data), nil
+}
+
+func (a *TarGzReader) NextPhoto() (string, int64, io.Reader, error) {
+	for {
+		header, err := a.tarReader.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return "", 0, nil, io.EOF
+			}
+
+			return "", 0, nil, fmt.Errorf("read tar error: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg || path.Dir(header.Name) != photosDir {
+			continue
+		}
+
+		return path.Base(header.Name), header.Size, a.tarReader, nil
+	}
+}
+
+func (a *TarGzReader) Close() error {
+	return a.gzipReader.Close()
+}
+
+func (a *TarGzReader) readJSON(name string, v any) error {
+	header, err := a.tarReader.Next()
+	if err != nil {
+		return fmt.Errorf("read tar error: %w", err)
+	}
+
+	if header.Name != name {
+		return fmt.Errorf("unexpected %s in archive, expected %s", header.Name, name)
+	}
+
+	if err := json.NewDecoder(a.tarReader).Decode(v); err != nil {
+		return fmt.Errorf("decode %s error: %w", name, err)
+	}
+
+	return nil
+}
+
+func toManifestJSON(manifest domain.BackupManifest) manifestJSON {
+	return manifestJSON{
+		Version:             manifest.Version,
+		CreatedAt:           manifest.CreatedAt,
+		Folders:             manifest.Folders,
+		Comparisons:         manifest.Comparisons,
+		CustomOptions:       manifest.CustomOptions,
+		Objects:             manifest.Objects,
+		ObjectCustomOptions: manifest.ObjectCustomOptions,
+		Photos:              manifest.Photos,
+	}
+}
+
+func toDomainManifest(manifest manifestJSON) domain.BackupManifest {
+	return domain.BackupManifest{
+		Version:             manifest.Version,
+		CreatedAt:           manifest.CreatedAt,
+		Folders:             manifest.Folders,
+		Comparisons:         manifest.Comparisons,
+		CustomOptions:       manifest.CustomOptions,
+		Objects:             manifest.Objects,
+		ObjectCustomOptions: manifest.ObjectCustomOptions,
+		Photos:              manifest.Photos,
+	}
+}
+
+func toDataJSON(backup domain.Backup) dataJSON {
+	data := dataJSON{
+		Folders:             make([]folderJSON, len(backup.Folders)),
+		Comparisons:         make([]comparisonJSON, len(backup.Comparisons)),
+		CustomOptions:       make([]customOptionJSON, len(backup.CustomOptions)),
+		Objects:             make([]objectJSON, len(backup.Objects)),
+		ObjectCustomOptions: make([]objectCustomOptionJSON, len(backup.ObjectCustomOptions)),
+		Photos:              make([]photoJSON, len(backup.Photos)),
+	}
+
+	for i, f := range backup.Folders {
+		data.Folders[i] = folderJSON{
+			Id:        f.Id,
+			Name:      f.Name,
+			ParentId:  f.ParentId,
+			CreatedAt: f.CreatedAt,
+		}
+	}
+
+	for i, c := range backup.Comparisons {
+		optionWeights := make([]optionWeightJSON, len(c.OptionWeights))
+		for j, ow := range c.OptionWeights {
+			optionWeights[j] = optionWeightJSON{
+				CustomOptionId: ow.CustomOptionId,
+				Weight:         ow.Weight,
+				Direction:      string(ow.Direction),
+			}
+		}
+
+		data.Comparisons[i] = comparisonJSON{
+			Id:              c.Id,
+			Name:            c.Name,
+			CreatedAt:       c.CreatedAt,
+			CustomOptionIds: c.CustomOptionIds,
+			OptionWeights:   optionWeights,
+			Tags:            c.Tags,
+			FolderId:        c.FolderId,
+		}
+	}
+
+	for i, co := range backup.CustomOptions {
+		data.CustomOptions[i] = customOptionJSON{
+			Id:         co.Id,
+			Name:       co.Name,
+			Type:       string(co.Type),
+			EnumValues: co.EnumValues,
+			Currency:   co.Currency,
+		}
+	}
+
+	for i, obj := range backup.Objects {
+		data.Objects[i] = objectJSON{
+			Id:           obj.Id,
+			Name:         obj.Name,
+			Rating:       obj.Rating,
+			CreatedAt:    obj.CreatedAt,
+			Advs:         obj.Advs,
+			Disadvs:      obj.Disadvs,
+			ComparisonId: obj.ComparisonId,
+		}
+	}
+
+	for i, oco := range backup.ObjectCustomOptions {
+		data.ObjectCustomOptions[i] = objectCustomOptionJSON{
+			ObjectId:       oco.ObjectId,
+			CustomOptionId: oco.CustomOptionId,
+			Value:          oco.Value,
+		}
+	}
+
+	for i, p := range backup.Photos {
+		data.Photos[i] = photoJSON{
+			Id:          p.Id,
+			ObjectId:    p.ObjectId,
+			Key:         p.Key,
+			ContentType: p.ContentType,
+			Size:        p.Size,
+			Width:       p.Width,
+			Height:      p.Height,
+			Position:    p.Position,
+			IsCover:     p.IsCover,
+			CreatedAt:   p.CreatedAt,
+		}
+	}
+
+	return data
+}
+
+func toDomainBackup(data dataJSON) domain.Backup {
+	backup := domain.Backup{
+		Folders:             make([]domain.Folder, len(data.Folders)),
+		Comparisons:         make([]domain.Comparison, len(data.Comparisons)),
+		CustomOptions:       make([]domain.CustomOption, len(data.CustomOptions)),
+		Objects:             make([]domain.Object, len(data.Objects)),
+		ObjectCustomOptions: make([]domain.ObjectCustomOption, len(data.ObjectCustomOptions)),
+		Photos:              make([]domain.Photo, len(data.Photos)),
+	}
+
+	for i, f := range data.Folders {
+		backup.Folders[i] = domain.Folder{
+			Id:        f.Id,
+			Name:      f.Name,
+			ParentId:  f.ParentId,
+			CreatedAt: f.CreatedAt,
+		}
+	}
+
+	for i, c := range data.Comparisons {
+		optionWeights := make([]domain.OptionWeight, len(c.OptionWeights))
+		for j, ow := range c.OptionWeights {
+			optionWeights[j] = domain.OptionWeight{
+				CustomOptionId: ow.CustomOptionId,
+				Weight:         ow.Weight,
+				Direction:      domain.ScoreDirection(ow.Direction),
+			}
+		}
+
+		customOptionIds := c.CustomOptionIds
+		if customOptionIds == nil {
+			customOptionIds = make([]string, 0)
+		}
+
+		backup.Comparisons[i] = domain.Comparison{
+			Id:              c.Id,
+			Name:            c.Name,
+			CreatedAt:       c.CreatedAt,
+			CustomOptionIds: customOptionIds,
+			OptionWeights:   optionWeights,
+			Tags:            c.Tags,
+			FolderId:        c.FolderId,
+		}
+	}
+
+	for i, co := range data.CustomOptions {
+		backup.CustomOptions[i] = domain.CustomOption{
+			Id:         co.Id,
+			Name:       co.Name,
+			Type:       domain.CustomOptionType(co.Type),
+			EnumValues: co.EnumValues,
+			Currency:   co.Currency,
+		}
+	}
+
+	for i, obj := range data.Objects {
+		backup.Objects[i] = domain.Object{
+			Id:           obj.Id,
+			Name:         obj.Name,
+			Rating:       obj.Rating,
+			CreatedAt:    obj.CreatedAt,
+			Advs:         obj.Advs,
+			Disadvs:      obj.Disadvs,
+			ComparisonId: obj.ComparisonId,
+		}
+	}
+
+	for i, oco := range data.ObjectCustomOptions {
+		backup.ObjectCustomOptions[i] = domain.ObjectCustomOption{
+			ObjectId:       oco.ObjectId,
+			CustomOptionId: oco.CustomOptionId,
+			Value:          oco.Value,
+		}
+	}
+
+	for i, p := range data.Photos {
+		backup.Photos[i] = domain.Photo{
+			Id:          p.Id,
+			ObjectId:    p.ObjectId,
+			Key:         p.Key,
+			ContentType: p.ContentType,
+			Size:        p.Size,
+			Width:       p.Width,
+			Height:      p.Height,
+			Position:    p.Position,
+			IsCover:     p.IsCover,
+			CreatedAt:   p.CreatedAt,
+		}
+	}
+
+	return backup
+}
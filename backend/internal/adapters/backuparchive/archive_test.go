@@ -0,0 +1,240 @@
+package backuparchive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func testBackup() domain.Backup {
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	return domain.Backup{
+		Folders: []domain.Folder{
+			{Id: "f1", Name: "Vehicles", CreatedAt: createdAt},
+			{Id: "f2", Name: "Cars", ParentId: "f1", CreatedAt: createdAt},
+		},
+		Comparisons: []domain.Comparison{
+			{
+				Id:              "c1",
+				Name:            "Cars",
+				CreatedAt:       createdAt,
+				CustomOptionIds: []string{"co1", "co2"},
+				OptionWeights: []domain.OptionWeight{
+					{CustomOptionId: "co1", Weight: 2.5, Direction: domain.ScoreDirectionLowerIsBetter},
+				},
+				Tags:     []string{"suv"},
+				FolderId: "f2",
+			},
+		},
+		CustomOptions: []domain.CustomOption{
+			{Id: "co1", Name: "Price", Type: domain.CustomOptionTypeMoney, Currency: "USD"},
+			{Id: "co2", Name: "Drive", Type: domain.CustomOptionTypeEnum, EnumValues: []string{"FWD", "AWD"}},
+		},
+		Objects: []domain.Object{
+			{
+				Id:           "o1",
+				Name:         "BMW X5",
+				Rating:       8,
+				CreatedAt:    createdAt,
+				Advs:         "Fast",
+				Disadvs:      "Expensive",
+				ComparisonId: "c1",
+			},
+		},
+		ObjectCustomOptions: []domain.ObjectCustomOption{
+			{ObjectId: "o1", CustomOptionId: "co1", Value: "80000 USD"},
+			{ObjectId: "o1", CustomOptionId: "co2", Value: "AWD"},
+		},
+		Photos: []domain.Photo{
+			{
+				Id:          "p1",
+				ObjectId:    "o1",
+				Key:         "p1.jpg",
+				ContentType: "image/jpeg",
+				Size:        4,
+				Width:       640,
+				Height:      480,
+				IsCover:     true,
+				CreatedAt:   createdAt,
+			},
+			{
+				Id:          "p2",
+				ObjectId:    "o1",
+				Key:         "p2.jpg",
+				ContentType: "image/jpeg",
+				Size:        65536,
+				Width:       800,
+				Height:      600,
+				Position:    1,
+				CreatedAt:   createdAt,
+			},
+		},
+	}
+}
+
+// randomContent is incompressible, so most of the archive is taken by the photo.
+func randomContent(size int) []byte {
+	content := make([]byte, size)
+	rand.New(rand.NewSource(1)).Read(content)
+
+	return content
+}
+
+func writeArchive(t *testing.T, manifest domain.BackupManifest, backup domain.Backup, photos map[string][]byte) []byte {
+	var buf bytes.Buffer
+	archive := NewTarGzWriter(&buf)
+
+	assert.NoError(t, archive.WriteBackup(manifest, backup))
+	for _, p := range backup.Photos {
+		content := photos[p.Key]
+		assert.NoError(t, archive.WritePhoto(p.Key, int64(len(content)), bytes.NewReader(content)))
+	}
+	assert.NoError(t, archive.Close())
+
+	return buf.Bytes()
+}
+
+// writeTarGz writes files as they are, in the given order.
+func writeTarGz(t *testing.T, files ...[2]string) []byte {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, file := range files {
+		assert.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: file[0], Mode: 0o644, Size: int64(len(file[1]))}))
+		_, err := tarWriter.Write([]byte(file[1]))
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, tarWriter.Close())
+	assert.NoError(t, gzipWriter.Close())
+
+	return buf.Bytes()
+}
+
+// readArchive reads the whole archive and returns the first error.
+func readArchive(content []byte) (domain.BackupManifest, domain.Backup, map[string][]byte, error) {
+	archive, err := NewTarGzReader(bytes.NewReader(content))
+	if err != nil {
+		return domain.BackupManifest{}, domain.Backup{}, nil, err
+	}
+	defer archive.Close()
+
+	manifest, backup, err := archive.ReadBackup()
+	if err != nil {
+		return domain.BackupManifest{}, domain.Backup{}, nil, err
+	}
+
+	photos := make(map[string][]byte)
+	for {
+		name, size, photo, err := archive.NextPhoto()
+		if errors.Is(err, io.EOF) {
+			return manifest, backup, photos, nil
+		}
+		if err != nil {
+			return manifest, backup, photos, err
+		}
+
+		content, err := io.ReadAll(photo)
+		if err != nil {
+			return manifest, backup, photos, err
+		}
+		if int64(len(content)) != size {
+			return manifest, backup, photos, io.ErrUnexpectedEOF
+		}
+
+		photos[name] = content
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	backup := testBackup()
+	manifest := domain.NewBackupManifest(backup)
+	photos := map[string][]byte{
+		"p1.jpg": []byte("jpeg"),
+		"p2.jpg": randomContent(65536),
+	}
+
+	readManifest, readBackup, readPhotos, err := readArchive(writeArchive(t, manifest, backup, photos))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.BackupVersion, readManifest.Version)
+	assert.True(t, manifest.CreatedAt.Equal(readManifest.CreatedAt))
+	assert.Equal(t, 2, readManifest.Folders)
+	assert.Equal(t, 2, readManifest.Photos)
+	assert.Equal(t, backup, readBackup)
+	assert.Equal(t, photos, readPhotos)
+}
+
+func TestArchiveEmptyBackup(t *testing.T) {
+	backup := domain.Backup{}
+
+	_, readBackup, readPhotos, err := readArchive(writeArchive(t, domain.NewBackupManifest(backup), backup, nil))
+	assert.NoError(t, err)
+	assert.Empty(t, readBackup.Comparisons)
+	assert.Empty(t, readPhotos)
+}
+
+func TestArchiveOtherVersion(t *testing.T) {
+	// data of other versions is not decoded, the version is left for the caller to reject
+	for _, manifest := range []string{`{"version": 2, "folders": 1}`, `{"folders": 1}`} {
+		t.Run(manifest, func(t *testing.T) {
+			content := writeTarGz(t,
+				[2]string{manifestName, manifest},
+				[2]string{dataName, `{"folders": {"layout": "unknown"}}`},
+			)
+
+			archive, err := NewTarGzReader(bytes.NewReader(content))
+			assert.NoError(t, err)
+			defer archive.Close()
+
+			readManifest, readBackup, err := archive.ReadBackup()
+			assert.NoError(t, err)
+			assert.NotEqual(t, domain.BackupVersion, readManifest.Version)
+			assert.Equal(t, 1, readManifest.Folders)
+			assert.Equal(t, domain.Backup{}, readBackup)
+		})
+	}
+}
+
+func TestArchiveMalformed(t *testing.T) {
+	backup := testBackup()
+	complete := writeArchive(t, domain.NewBackupManifest(backup), backup, map[string][]byte{
+		"p1.jpg": []byte("jpeg"),
+		"p2.jpg": randomContent(65536),
+	})
+
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{name: "Empty", content: nil},
+		{name: "Not gzip", content: []byte("manifest.json")},
+		{name: "Truncated gzip header", content: complete[:5]},
+		{name: "Truncated before data", content: complete[:30]},
+		{name: "Truncated photo", content: complete[:len(complete)/2]},
+		{name: "Data before manifest", content: writeTarGz(t,
+			[2]string{dataName, `{}`},
+			[2]string{manifestName, `{"version": 1}`},
+		)},
+		{name: "Missing data", content: writeTarGz(t, [2]string{manifestName, `{"version": 1}`})},
+		{name: "Broken manifest", content: writeTarGz(t, [2]string{manifestName, `{"version":`})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NotPanics(t, func() {
+				_, _, _, err := readArchive(tt.content)
+				assert.Error(t, err)
+			})
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package builtintemplates
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed templates.yml
+var templatesYAML []byte
+
+type templateYAML struct {
+	Id          string               `yaml:"id"`
+	Name        string               `yaml:"name"`
+	Description string               `yaml:"description"`
+	Options     []templateOptionYAML `yaml:"options"`
+}
+
+type templateOptionYAML struct {
+	Name       string   `yaml:"name"`
+	Type       string   `yaml:"type"`
+	EnumValues []string `yaml:"enum_values"`
+	Currency   string   `yaml:"currency"`
+	Weight     float64  `yaml:"weight"`
+	Direction  string   `yaml:"direction"`
+}
+
+// Load returns templates embedded into the application binary.
+func Load() ([]domain.Template, error) {
+	var templates []templateYAML
+	if err := yaml.Unmarshal(templatesYAML, &templates); err != nil {
+		return nil, fmt.Errorf("decode built-in templates error: %w", err)
+	}
+
+	domainTemplates := make([]domain.Template, len(templates))
+	for i, t := range templates {
+		options := make([]domain.TemplateOption, len(t.Options))
+		for j, o := range t.Options {
+			options[j] = domain.TemplateOption{
+				Name:       o.Name,
+				Type:       domain.CustomOptionType(o.Type),
+				EnumValues: o.EnumValues,
+				Currency:   o.Currency,
+				Weight:     o.Weight,
+				Direction:  domain.ScoreDirection(o.Direction),
+			}
+		}
+
+		domainTemplates[i] = domain.Template{
+			Id:          t.Id,
+			Name:        t.Name,
+			Description: t.Description,
+			Options:     options,
+		}
+	}
+
+	return domainTemplates, nil
+}
@@ -0,0 +1,79 @@
+package comparison
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/response"
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	v "github.com/go-ozzo/ozzo-validation"
+)
+
+type cloneComparisonInput struct {
+	Name           string `json:"name"`
+	IncludeObjects bool   `json:"include_objects"`
+	IncludePhotos  bool   `json:"include_photos"`
+}
+
+func (ci *cloneComparisonInput) Bind(r *http.Request) error {
+	return v.ValidateStruct(ci,
+		v.Field(&ci.Name, v.Required, v.Length(1, 50)),
+	)
+}
+
+type returnedIdResponse struct {
+	Id string `json:"id"`
+}
+
+func (h *ComparisonHandler) CloneComparison(w http.ResponseWriter, r *http.Request) {
+	if r.Body == http.NoBody {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("validation error - request body required"),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+
+	var input cloneComparisonInput
+	if err := render.Bind(r, &input); err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("validation error - %w", err),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	cloneId, err := h.uc.CloneComparison(r.Context(), id, domain.CloneOptions{
+		Name:           input.Name,
+		IncludeObjects: input.IncludeObjects,
+		IncludePhotos:  input.IncludePhotos,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+
+		if errors.Is(err, domain.ErrAlreadyExists) || errors.Is(err, domain.ErrInvalidValue) {
+			status = http.StatusBadRequest
+		}
+
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("clone comparison error - %w", err),
+			status,
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, &returnedIdResponse{Id: cloneId})
+}
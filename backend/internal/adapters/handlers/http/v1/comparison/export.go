@@ -0,0 +1,73 @@
+package comparison
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/response"
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ExportUsecase interface {
+	ExportMatrix(matrix domain.ComparisonMatrix, format domain.ExportFormat) (domain.ExportFile, error)
+	ReadTable(format domain.ExportFormat, r io.Reader) (domain.ImportTable, error)
+}
+
+func (h *ComparisonHandler) ExportComparison(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	format := domain.ExportFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = domain.ExportFormatCSV
+	}
+
+	if !slices.Contains(domain.ProvidedExportFormats, format) {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("incorrect format value, must be one of csv, xlsx, json"),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	matrix, err := h.matrixUc.GetComparisonMatrix(r.Context(), id)
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("export comparison error - %w", err),
+			status,
+		)
+		return
+	}
+
+	// encoded before anything is written, so a failure is still reported with the error status
+	file, err := h.exportUc.ExportMatrix(matrix, format)
+	if err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("export comparison error - %w", err),
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": fmt.Sprintf("%s.%s", file.Name, file.Format),
+	}))
+	w.Header().Set("Content-Length", strconv.Itoa(len(file.Content)))
+	w.Write(file.Content)
+}
@@ -19,27 +19,68 @@ import (
 )
 
 type ComparisonUsecase interface {
-	GetComparisons(ctx context.Context, filter domain.ComparisonFilter) ([]domain.Comparison, error)
+	GetComparisons(ctx context.Context, filter domain.ComparisonFilter) ([]domain.Comparison, domain.PageInfo, error)
 	GetComparisonById(ctx context.Context, id string) (domain.Comparison, error)
 	UpdateComparison(ctx context.Context, id string, comparison domain.Comparison) error
-	CreateComparison(ctx context.Context, comparison domain.Comparison) error
+	CreateComparison(ctx context.Context, comparison domain.Comparison, templateId string) error
 	DeleteComparison(ctx context.Context, id string) error
+	RestoreComparison(ctx context.Context, id string) error
+	CloneComparison(ctx context.Context, id string, opts domain.CloneOptions) (string, error)
+}
+
+type ScoringUsecase interface {
+	GetComparisonScores(ctx context.Context, comparisonId string) ([]domain.ObjectScore, error)
+}
+
+type MatrixUsecase interface {
+	GetComparisonMatrix(ctx context.Context, comparisonId string) (domain.ComparisonMatrix, error)
 }
 
 type ComparisonHandler struct {
-	router http.Handler
-	uc     ComparisonUsecase
+	router        http.Handler
+	maxUploadSize int64
+	uc            ComparisonUsecase
+	scoringUc     ScoringUsecase
+	matrixUc      MatrixUsecase
+	importUc      ImportUsecase
+	exportUc      ExportUsecase
+	auditUc       AuditUsecase
 }
 
-func NewComparisonHandler(uc ComparisonUsecase) *ComparisonHandler {
+func NewComparisonHandler(
+	uc ComparisonUsecase,
+	scoringUc ScoringUsecase,
+	matrixUc MatrixUsecase,
+	importUc ImportUsecase,
+	exportUc ExportUsecase,
+	auditUc AuditUsecase,
+	maxSize int64,
+) *ComparisonHandler {
 	router := chi.NewRouter()
-	handler := &ComparisonHandler{router: router, uc: uc}
+	handler := &ComparisonHandler{
+		router:        router,
+		maxUploadSize: maxSize << 20,
+		uc:            uc,
+		scoringUc:     scoringUc,
+		matrixUc:      matrixUc,
+		importUc:      importUc,
+		exportUc:      exportUc,
+		auditUc:       auditUc,
+	}
 
 	router.Get("/", handler.GetComparisons)
 	router.Get("/{id}", handler.GetComparisonById)
 	router.Post("/", handler.CreateComparison)
 	router.Put("/{id}", handler.UpdateComparison)
 	router.Delete("/{id}", handler.DeleteComparison)
+	router.Post("/{id}/restore", handler.RestoreComparison)
+	router.Post("/{id}/clone", handler.CloneComparison)
+
+	router.Get("/{id}/scores", handler.GetComparisonScores)
+	router.Get("/{id}/matrix", handler.GetComparisonMatrix)
+	router.Get("/{id}/export", handler.ExportComparison)
+	router.Post("/{id}/import", handler.ImportObjects)
+	router.Get("/{id}/history", handler.GetComparisonHistory)
 
 	return handler
 }
@@ -49,10 +90,19 @@ func (h *ComparisonHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 type comparisonResponse struct {
-	Id              string    `json:"id"`
-	Name            string    `json:"name"`
-	CreatedAt       time.Time `json:"created_at"`
-	CustomOptionIds []string  `json:"custom_option_ids"`
+	Id              string                 `json:"id"`
+	Name            string                 `json:"name"`
+	CreatedAt       time.Time              `json:"created_at"`
+	CustomOptionIds []string               `json:"custom_option_ids"`
+	OptionWeights   []optionWeightResponse `json:"option_weights"`
+	Tags            []string               `json:"tags"`
+	FolderId        string                 `json:"folder_id"`
+}
+
+type optionWeightResponse struct {
+	CustomOptionId string  `json:"custom_option_id"`
+	Weight         float64 `json:"weight"`
+	Direction      string  `json:"direction"`
 }
 
 func (h *ComparisonHandler) GetComparisons(w http.ResponseWriter, r *http.Request) {
@@ -66,12 +116,18 @@ func (h *ComparisonHandler) GetComparisons(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	comparisons, err := h.uc.GetComparisons(r.Context(), filter)
+	comparisons, pageInfo, err := h.uc.GetComparisons(r.Context(), filter)
 	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrInvalidValue) {
+			status = http.StatusBadRequest
+		}
+
 		response.FailureResponse(
 			w, r,
 			fmt.Errorf("get comparisons error - %w", err),
-			http.StatusInternalServerError,
+			status,
 		)
 		return
 	}
@@ -81,7 +137,7 @@ func (h *ComparisonHandler) GetComparisons(w http.ResponseWriter, r *http.Reques
 		comparisonResponses[i] = toComparisonResponse(c)
 	}
 
-	response.SuccessResponse(w, r, comparisonResponses)
+	response.SuccessListResponse(w, r, comparisonResponses, response.Pagination(pageInfo))
 }
 
 func (h *ComparisonHandler) GetComparisonById(w http.ResponseWriter, r *http.Request) {
@@ -106,15 +162,42 @@ func (h *ComparisonHandler) GetComparisonById(w http.ResponseWriter, r *http.Req
 	response.SuccessResponse(w, r, toComparisonResponse(comparison))
 }
 
+type optionWeightInput struct {
+	CustomOptionId string  `json:"custom_option_id"`
+	Weight         float64 `json:"weight"`
+	Direction      string  `json:"direction"`
+}
+
+func (wi optionWeightInput) Validate() error {
+	directions := make([]interface{}, len(domain.ProvidedScoreDirections))
+	for i, d := range domain.ProvidedScoreDirections {
+		directions[i] = string(d)
+	}
+
+	return v.ValidateStruct(&wi,
+		v.Field(&wi.CustomOptionId, v.Required, is.UUIDv4),
+		v.Field(&wi.Weight, v.Min(0.0)),
+		v.Field(&wi.Direction, v.In(directions...)),
+	)
+}
+
 type createComparisonInput struct {
-	Name            string   `json:"name"`
-	CustomOptionIds []string `json:"custom_option_ids"`
+	Name            string              `json:"name"`
+	CustomOptionIds []string            `json:"custom_option_ids"`
+	OptionWeights   []optionWeightInput `json:"option_weights"`
+	TemplateId      string              `json:"template_id"`
+	Tags            []string            `json:"tags"`
+	FolderId        string              `json:"folder_id"`
 }
 
 func (ci *createComparisonInput) Bind(r *http.Request) error {
 	return v.ValidateStruct(ci,
 		v.Field(&ci.Name, v.Required, v.Length(1, 50)),
 		v.Field(&ci.CustomOptionIds, v.Each(is.UUIDv4)),
+		v.Field(&ci.OptionWeights),
+		v.Field(&ci.TemplateId, is.UUIDv4),
+		v.Field(&ci.Tags, v.Length(0, domain.MaxComparisonTags), v.Each(v.Required, v.Length(1, domain.MaxTagLength))),
+		v.Field(&ci.FolderId, is.UUIDv4),
 	)
 }
 
@@ -145,14 +228,21 @@ func (h *ComparisonHandler) CreateComparison(w http.ResponseWriter, r *http.Requ
 	err := h.uc.CreateComparison(r.Context(), domain.Comparison{
 		Name:            input.Name,
 		CustomOptionIds: input.CustomOptionIds,
-	})
+		OptionWeights:   toDomainOptionWeights(input.OptionWeights),
+		Tags:            input.Tags,
+		FolderId:        input.FolderId,
+	}, input.TemplateId)
 	if err != nil {
 		status := http.StatusInternalServerError
 
-		if errors.Is(err, domain.ErrAlreadyExists) {
+		if errors.Is(err, domain.ErrAlreadyExists) || errors.Is(err, domain.ErrInvalidValue) {
 			status = http.StatusBadRequest
 		}
 
+		if errors.Is(err, domain.ErrConflict) {
+			status = http.StatusConflict
+		}
+
 		response.FailureResponse(
 			w, r,
 			fmt.Errorf("create comparison error - %w", err),
@@ -165,14 +255,20 @@ func (h *ComparisonHandler) CreateComparison(w http.ResponseWriter, r *http.Requ
 }
 
 type updateComparisonInput struct {
-	Name            string   `json:"name"`
-	CustomOptionIds []string `json:"custom_option_ids"`
+	Name            string              `json:"name"`
+	CustomOptionIds []string            `json:"custom_option_ids"`
+	OptionWeights   []optionWeightInput `json:"option_weights"`
+	Tags            []string            `json:"tags"`
+	FolderId        string              `json:"folder_id"`
 }
 
 func (ci *updateComparisonInput) Bind(r *http.Request) error {
 	return v.ValidateStruct(ci,
 		v.Field(&ci.Name, v.Required, v.Length(1, 50)),
 		v.Field(&ci.CustomOptionIds, v.Each(is.UUIDv4)),
+		v.Field(&ci.OptionWeights),
+		v.Field(&ci.Tags, v.Length(0, domain.MaxComparisonTags), v.Each(v.Required, v.Length(1, domain.MaxTagLength))),
+		v.Field(&ci.FolderId, is.UUIDv4),
 	)
 }
 
@@ -205,6 +301,9 @@ func (h *ComparisonHandler) UpdateComparison(w http.ResponseWriter, r *http.Requ
 	err := h.uc.UpdateComparison(r.Context(), id, domain.Comparison{
 		Name:            input.Name,
 		CustomOptionIds: input.CustomOptionIds,
+		OptionWeights:   toDomainOptionWeights(input.OptionWeights),
+		Tags:            input.Tags,
+		FolderId:        input.FolderId,
 	})
 	if err != nil {
 		status := http.StatusInternalServerError
@@ -213,6 +312,10 @@ func (h *ComparisonHandler) UpdateComparison(w http.ResponseWriter, r *http.Requ
 			status = http.StatusNotFound
 		}
 
+		if errors.Is(err, domain.ErrAlreadyExists) || errors.Is(err, domain.ErrInvalidValue) {
+			status = http.StatusBadRequest
+		}
+
 		response.FailureResponse(
 			w, r,
 			fmt.Errorf("update comparison error - %w", err),
@@ -245,6 +348,114 @@ func (h *ComparisonHandler) DeleteComparison(w http.ResponseWriter, r *http.Requ
 	response.SuccessResponse(w, r, nil)
 }
 
+func (h *ComparisonHandler) RestoreComparison(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	err := h.uc.RestoreComparison(r.Context(), id)
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, domain.ErrAlreadyExists), errors.Is(err, domain.ErrConflict):
+			status = http.StatusConflict
+		}
+
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("restore comparison error - %w", err),
+			status,
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, nil)
+}
+
+type optionScoreResponse struct {
+	CustomOptionId string  `json:"custom_option_id"`
+	Value          string  `json:"value"`
+	Normalized     float64 `json:"normalized"`
+	Weight         float64 `json:"weight"`
+}
+
+type objectScoreResponse struct {
+	ObjectId string                `json:"object_id"`
+	Name     string                `json:"name"`
+	Score    float64               `json:"score"`
+	Rank     int                   `json:"rank"`
+	Options  []optionScoreResponse `json:"options"`
+}
+
+func (h *ComparisonHandler) GetComparisonScores(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	scores, err := h.scoringUc.GetComparisonScores(r.Context(), id)
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("get comparison scores error - %w", err),
+			status,
+		)
+		return
+	}
+
+	scoreResponses := make([]objectScoreResponse, len(scores))
+	for i, s := range scores {
+		scoreResponses[i] = toObjectScoreResponse(s)
+	}
+
+	response.SuccessResponse(w, r, scoreResponses)
+}
+
+type matrixCustomOptionResponse struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type matrixRowResponse struct {
+	ObjectId  string    `json:"object_id"`
+	Name      string    `json:"name"`
+	Rating    int       `json:"rating"`
+	CreatedAt time.Time `json:"created_at"`
+	Values    []string  `json:"values"`
+}
+
+type matrixResponse struct {
+	Comparison    comparisonResponse           `json:"comparison"`
+	CustomOptions []matrixCustomOptionResponse `json:"custom_options"`
+	Rows          []matrixRowResponse          `json:"rows"`
+}
+
+func (h *ComparisonHandler) GetComparisonMatrix(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	matrix, err := h.matrixUc.GetComparisonMatrix(r.Context(), id)
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("get comparison matrix error - %w", err),
+			status,
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, toMatrixResponse(matrix))
+}
+
 func (h *ComparisonHandler) getFilter(params url.Values) (domain.ComparisonFilter, error) {
 	var limit int
 	var offset int
@@ -270,14 +481,135 @@ func (h *ComparisonHandler) getFilter(params url.Values) (domain.ComparisonFilte
 
 	orderBy = params.Get("order_by")
 
-	return domain.NewComparisonFilter(limit, offset, orderBy)
+	createdFrom, err := parseTimeParam(params.Get("created_from"), false)
+	if err != nil {
+		return domain.ComparisonFilter{}, fmt.Errorf("incorrect created_from value")
+	}
+
+	createdTo, err := parseTimeParam(params.Get("created_to"), true)
+	if err != nil {
+		return domain.ComparisonFilter{}, fmt.Errorf("incorrect created_to value")
+	}
+
+	return domain.NewComparisonFilter(
+		limit, offset,
+		params.Get("cursor"), orderBy, params.Get("name"),
+		createdFrom, createdTo,
+		params.Get("custom_option_id"), params.Get("tag"), params.Get("folder"),
+	)
+}
+
+// parseTimeParam accepts RFC 3339 time or a date. Date used as the end of range
+// stands for the end of that day, so the whole day is included.
+func parseTimeParam(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	date, err := time.Parse(domain.DateLayout, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if endOfDay {
+		return date.Add(24*time.Hour - time.Millisecond), nil
+	}
+
+	return date, nil
+}
+
+func toDomainOptionWeights(inputs []optionWeightInput) []domain.OptionWeight {
+	optionWeights := make([]domain.OptionWeight, len(inputs))
+	for i, wi := range inputs {
+		direction := domain.ScoreDirection(wi.Direction)
+		if direction == "" {
+			direction = domain.ScoreDirectionHigherIsBetter
+		}
+
+		optionWeights[i] = domain.OptionWeight{
+			CustomOptionId: wi.CustomOptionId,
+			Weight:         wi.Weight,
+			Direction:      direction,
+		}
+	}
+
+	return optionWeights
 }
 
 func toComparisonResponse(comparison domain.Comparison) comparisonResponse {
+	optionWeights := make([]optionWeightResponse, len(comparison.OptionWeights))
+	for i, ow := range comparison.OptionWeights {
+		optionWeights[i] = optionWeightResponse{
+			CustomOptionId: ow.CustomOptionId,
+			Weight:         ow.Weight,
+			Direction:      string(ow.Direction),
+		}
+	}
+
+	tags := comparison.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
 	return comparisonResponse{
 		Id:              comparison.Id,
 		Name:            comparison.Name,
 		CreatedAt:       comparison.CreatedAt,
 		CustomOptionIds: comparison.CustomOptionIds,
+		OptionWeights:   optionWeights,
+		Tags:            tags,
+		FolderId:        comparison.FolderId,
+	}
+}
+
+func toObjectScoreResponse(score domain.ObjectScore) objectScoreResponse {
+	options := make([]optionScoreResponse, len(score.OptionScores))
+	for i, os := range score.OptionScores {
+		options[i] = optionScoreResponse{
+			CustomOptionId: os.CustomOptionId,
+			Value:          os.Value,
+			Normalized:     os.Normalized,
+			Weight:         os.Weight,
+		}
+	}
+
+	return objectScoreResponse{
+		ObjectId: score.ObjectId,
+		Name:     score.Name,
+		Score:    score.Score,
+		Rank:     score.Rank,
+		Options:  options,
+	}
+}
+
+func toMatrixResponse(matrix domain.ComparisonMatrix) matrixResponse {
+	customOptions := make([]matrixCustomOptionResponse, len(matrix.CustomOptions))
+	for i, co := range matrix.CustomOptions {
+		customOptions[i] = matrixCustomOptionResponse{
+			Id:   co.Id,
+			Name: co.Name,
+			Type: string(co.Type),
+		}
+	}
+
+	rows := make([]matrixRowResponse, len(matrix.Rows))
+	for i, row := range matrix.Rows {
+		rows[i] = matrixRowResponse{
+			ObjectId:  row.Object.Id,
+			Name:      row.Object.Name,
+			Rating:    row.Object.Rating,
+			CreatedAt: row.Object.CreatedAt,
+			Values:    row.Values,
+		}
+	}
+
+	return matrixResponse{
+		Comparison:    toComparisonResponse(matrix.Comparison),
+		CustomOptions: customOptions,
+		Rows:          rows,
 	}
 }
@@ -0,0 +1,137 @@
+package comparison
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/response"
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ImportUsecase interface {
+	ImportObjects(
+		ctx context.Context,
+		comparisonId string,
+		table domain.ImportTable,
+		opts domain.ImportOptions,
+	) (domain.ImportReport, error)
+}
+
+type importRowResponse struct {
+	Row      int      `json:"row"`
+	Name     string   `json:"name"`
+	ObjectId string   `json:"object_id,omitempty"`
+	Valid    bool     `json:"valid"`
+	Errors   []string `json:"errors"`
+}
+
+type importReportResponse struct {
+	DryRun               bool                `json:"dry_run"`
+	Total                int                 `json:"total"`
+	Invalid              int                 `json:"invalid"`
+	Imported             int                 `json:"imported"`
+	CreatedCustomOptions []string            `json:"created_custom_options"`
+	Rows                 []importRowResponse `json:"rows"`
+}
+
+func (h *ComparisonHandler) ImportObjects(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	table, err := h.readImportTable(w, r)
+	if err != nil {
+		response.FailureResponse(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.importUc.ImportObjects(r.Context(), id, table, domain.ImportOptions{
+		DryRun:              r.FormValue("dry_run") == "true",
+		CreateCustomOptions: r.FormValue("create_custom_options") == "true",
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+
+		if errors.Is(err, domain.ErrInvalidValue) || errors.Is(err, domain.ErrAlreadyExists) {
+			status = http.StatusBadRequest
+		}
+
+		var data any
+		if len(report.Rows) != 0 {
+			data = toImportReportResponse(report)
+		}
+
+		response.FailureResponseWithData(
+			w, r,
+			fmt.Errorf("import objects error - %w", err),
+			status,
+			data,
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, toImportReportResponse(report))
+}
+
+// readImportTable reads the table of the file uploaded as `file` multipart field,
+// its format is chosen by the extension of the file name.
+func (h *ComparisonHandler) readImportTable(w http.ResponseWriter, r *http.Request) (domain.ImportTable, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadSize)
+	if err := r.ParseMultipartForm(h.maxUploadSize); err != nil {
+		return domain.ImportTable{}, fmt.Errorf("failed to parse multipart form - %w", err)
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return domain.ImportTable{}, fmt.Errorf("failed to get file - %w", err)
+	}
+	defer file.Close()
+
+	format := domain.ExportFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), "."))
+	if !slices.Contains(domain.ProvidedExportFormats, format) {
+		return domain.ImportTable{}, fmt.Errorf("invalid file format, must be .csv, .xlsx or .json")
+	}
+
+	table, err := h.exportUc.ReadTable(format, file)
+	if err != nil {
+		return domain.ImportTable{}, fmt.Errorf("failed to read file - %w", err)
+	}
+
+	return table, nil
+}
+
+func toImportReportResponse(report domain.ImportReport) importReportResponse {
+	rows := make([]importRowResponse, len(report.Rows))
+	for i, row := range report.Rows {
+		rows[i] = importRowResponse{
+			Row:      row.Row,
+			Name:     row.Name,
+			ObjectId: row.ObjectId,
+			Valid:    len(row.Errors) == 0,
+			Errors:   row.Errors,
+		}
+	}
+
+	createdCustomOptions := report.CreatedCustomOptions
+	if createdCustomOptions == nil {
+		createdCustomOptions = make([]string, 0)
+	}
+
+	return importReportResponse{
+		DryRun:               report.DryRun,
+		Total:                report.Total,
+		Invalid:              report.Invalid,
+		Imported:             report.Imported,
+		CreatedCustomOptions: createdCustomOptions,
+		Rows:                 rows,
+	}
+}
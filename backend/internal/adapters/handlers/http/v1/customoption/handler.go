@@ -14,10 +14,11 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	v "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
 )
 
 type CustomOptionUsecase interface {
-	GetCustomOptions(ctx context.Context, filter domain.CustomOptionFilter) ([]domain.CustomOption, error)
+	GetCustomOptions(ctx context.Context, filter domain.CustomOptionFilter) ([]domain.CustomOption, domain.PageInfo, error)
 	GetCustomOptionById(ctx context.Context, id string) (domain.CustomOption, error)
 	UpdateCustomOption(ctx context.Context, id string, customOption domain.CustomOption) error
 	CreateCustomOption(ctx context.Context, customOption domain.CustomOption) error
@@ -47,8 +48,11 @@ func (h *CustomOptionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 }
 
 type customOptionResponse struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
+	Id         string   `json:"id"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	EnumValues []string `json:"enum_values,omitempty"`
+	Currency   string   `json:"currency,omitempty"`
 }
 
 func (h *CustomOptionHandler) GetCustomOptions(w http.ResponseWriter, r *http.Request) {
@@ -62,12 +66,18 @@ func (h *CustomOptionHandler) GetCustomOptions(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	customOptions, err := h.uc.GetCustomOptions(r.Context(), filter)
+	customOptions, pageInfo, err := h.uc.GetCustomOptions(r.Context(), filter)
 	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrInvalidValue) {
+			status = http.StatusBadRequest
+		}
+
 		response.FailureResponse(
 			w, r,
 			fmt.Errorf("get custom options error - %w", err),
-			http.StatusInternalServerError,
+			status,
 		)
 		return
 	}
@@ -77,7 +87,7 @@ func (h *CustomOptionHandler) GetCustomOptions(w http.ResponseWriter, r *http.Re
 		customOptionResponses[i] = toCustomOptionResponse(co)
 	}
 
-	response.SuccessResponse(w, r, customOptionResponses)
+	response.SuccessListResponse(w, r, customOptionResponses, response.Pagination(pageInfo))
 }
 
 func (h *CustomOptionHandler) GetCustomOptionById(w http.ResponseWriter, r *http.Request) {
@@ -103,12 +113,23 @@ func (h *CustomOptionHandler) GetCustomOptionById(w http.ResponseWriter, r *http
 }
 
 type createCustomOptionInput struct {
-	Name string `json:"name"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	EnumValues []string `json:"enum_values"`
+	Currency   string   `json:"currency"`
 }
 
 func (ci *createCustomOptionInput) Bind(r *http.Request) error {
+	types := make([]interface{}, len(domain.ProvidedCustomOptionTypes))
+	for i, t := range domain.ProvidedCustomOptionTypes {
+		types[i] = string(t)
+	}
+
 	return v.ValidateStruct(ci,
 		v.Field(&ci.Name, v.Required, v.Length(1, 50)),
+		v.Field(&ci.Type, v.In(types...)),
+		v.Field(&ci.EnumValues, v.Each(v.Required, v.Length(1, 100))),
+		v.Field(&ci.Currency, is.CurrencyCode),
 	)
 }
 
@@ -132,12 +153,23 @@ func (h *CustomOptionHandler) CreateCustomOption(w http.ResponseWriter, r *http.
 		return
 	}
 
-	err := h.uc.CreateCustomOption(r.Context(), domain.CustomOption{Name: input.Name})
+	err := h.uc.CreateCustomOption(r.Context(), domain.CustomOption{
+		Name:       input.Name,
+		Type:       domain.CustomOptionType(input.Type),
+		EnumValues: input.EnumValues,
+		Currency:   input.Currency,
+	})
 	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrInvalidValue) || errors.Is(err, domain.ErrAlreadyExists) {
+			status = http.StatusBadRequest
+		}
+
 		response.FailureResponse(
 			w, r,
 			fmt.Errorf("create custom option error - %w", err),
-			http.StatusInternalServerError,
+			status,
 		)
 		return
 	}
@@ -146,12 +178,14 @@ func (h *CustomOptionHandler) CreateCustomOption(w http.ResponseWriter, r *http.
 }
 
 type updateCustomOptionInput struct {
-	Name string `json:"name"`
+	Name       string   `json:"name"`
+	EnumValues []string `json:"enum_values"`
 }
 
 func (ci *updateCustomOptionInput) Bind(r *http.Request) error {
 	return v.ValidateStruct(ci,
 		v.Field(&ci.Name, v.Required, v.Length(1, 50)),
+		v.Field(&ci.EnumValues, v.Each(v.Required, v.Length(1, 100))),
 	)
 }
 
@@ -178,7 +212,8 @@ func (h *CustomOptionHandler) UpdateCustomOption(w http.ResponseWriter, r *http.
 	}
 
 	err := h.uc.UpdateCustomOption(r.Context(), id, domain.CustomOption{
-		Name: input.Name,
+		Name:       input.Name,
+		EnumValues: input.EnumValues,
 	})
 	if err != nil {
 		status := http.StatusInternalServerError
@@ -187,6 +222,14 @@ func (h *CustomOptionHandler) UpdateCustomOption(w http.ResponseWriter, r *http.
 			status = http.StatusNotFound
 		}
 
+		if errors.Is(err, domain.ErrInvalidValue) || errors.Is(err, domain.ErrAlreadyExists) {
+			status = http.StatusBadRequest
+		}
+
+		if errors.Is(err, domain.ErrConflict) {
+			status = http.StatusConflict
+		}
+
 		response.FailureResponse(
 			w, r,
 			fmt.Errorf("update custom option error - %w", err),
@@ -198,6 +241,12 @@ func (h *CustomOptionHandler) UpdateCustomOption(w http.ResponseWriter, r *http.
 	response.SuccessResponse(w, r, nil)
 }
 
+type dependentResponse struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
 func (h *CustomOptionHandler) DeleteCustomOption(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	err := h.uc.DeleteCustomOption(r.Context(), id)
@@ -208,6 +257,22 @@ func (h *CustomOptionHandler) DeleteCustomOption(w http.ResponseWriter, r *http.
 			status = http.StatusNotFound
 		}
 
+		var dependentsErr *domain.DependentsError
+		if errors.As(err, &dependentsErr) {
+			dependents := make([]dependentResponse, len(dependentsErr.Dependents))
+			for i, d := range dependentsErr.Dependents {
+				dependents[i] = dependentResponse{Type: d.Type, Id: d.Id, Name: d.Name}
+			}
+
+			response.FailureResponseWithData(
+				w, r,
+				fmt.Errorf("delete custom option error - %w", err),
+				http.StatusConflict,
+				dependents,
+			)
+			return
+		}
+
 		response.FailureResponse(
 			w, r,
 			fmt.Errorf("delete custom option error - %w", err),
@@ -244,12 +309,15 @@ func (h *CustomOptionHandler) getFilter(params url.Values) (domain.CustomOptionF
 
 	name = params.Get("name")
 
-	return domain.NewCustomOptionFilter(limit, offset, name)
+	return domain.NewCustomOptionFilter(limit, offset, params.Get("cursor"), params.Get("order_by"), name)
 }
 
 func toCustomOptionResponse(customOption domain.CustomOption) customOptionResponse {
 	return customOptionResponse{
-		Id:   customOption.Id,
-		Name: customOption.Name,
+		Id:         customOption.Id,
+		Name:       customOption.Name,
+		Type:       string(customOption.Type),
+		EnumValues: customOption.EnumValues,
+		Currency:   customOption.Currency,
 	}
 }
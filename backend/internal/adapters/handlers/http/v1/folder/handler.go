@@ -0,0 +1,248 @@
+package folder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/response"
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	v "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+type FolderUsecase interface {
+	GetFolders(ctx context.Context) ([]domain.Folder, error)
+	GetFolderById(ctx context.Context, id string) (domain.Folder, error)
+	CreateFolder(ctx context.Context, folder domain.Folder) (string, error)
+	UpdateFolder(ctx context.Context, id string, folder domain.Folder) error
+	DeleteFolder(ctx context.Context, id string) error
+}
+
+type FolderHandler struct {
+	router http.Handler
+	uc     FolderUsecase
+}
+
+func NewFolderHandler(uc FolderUsecase) *FolderHandler {
+	router := chi.NewRouter()
+	handler := &FolderHandler{router: router, uc: uc}
+
+	router.Get("/", handler.GetFolders)
+	router.Get("/{id}", handler.GetFolderById)
+	router.Post("/", handler.CreateFolder)
+	router.Put("/{id}", handler.UpdateFolder)
+	router.Delete("/{id}", handler.DeleteFolder)
+
+	return handler
+}
+
+func (h *FolderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.router.ServeHTTP(w, r)
+}
+
+type folderResponse struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	ParentId  string    `json:"parent_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (h *FolderHandler) GetFolders(w http.ResponseWriter, r *http.Request) {
+	folders, err := h.uc.GetFolders(r.Context())
+	if err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("get folders error - %w", err),
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	folderResponses := make([]folderResponse, len(folders))
+	for i, f := range folders {
+		folderResponses[i] = toFolderResponse(f)
+	}
+
+	response.SuccessResponse(w, r, folderResponses)
+}
+
+func (h *FolderHandler) GetFolderById(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	folder, err := h.uc.GetFolderById(r.Context(), id)
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("get folder error - %w", err),
+			status,
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, toFolderResponse(folder))
+}
+
+type folderInput struct {
+	Name     string `json:"name"`
+	ParentId string `json:"parent_id"`
+}
+
+func (fi *folderInput) Bind(r *http.Request) error {
+	return v.ValidateStruct(fi,
+		v.Field(&fi.Name, v.Required, v.Length(1, 50)),
+		v.Field(&fi.ParentId, is.UUIDv4),
+	)
+}
+
+type returnedIdResponse struct {
+	Id string `json:"id"`
+}
+
+func (h *FolderHandler) CreateFolder(w http.ResponseWriter, r *http.Request) {
+	if r.Body == http.NoBody {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("validation error - request body required"),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	var input folderInput
+	if err := render.Bind(r, &input); err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("validation error - %w", err),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	id, err := h.uc.CreateFolder(r.Context(), domain.Folder{Name: input.Name, ParentId: input.ParentId})
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrInvalidValue) || errors.Is(err, domain.ErrAlreadyExists) {
+			status = http.StatusBadRequest
+		}
+
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("create folder error - %w", err),
+			status,
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, &returnedIdResponse{Id: id})
+}
+
+func (h *FolderHandler) UpdateFolder(w http.ResponseWriter, r *http.Request) {
+	if r.Body == http.NoBody {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("validation error - request body required"),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+
+	var input folderInput
+	if err := render.Bind(r, &input); err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("validation error - %w", err),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	err := h.uc.UpdateFolder(r.Context(), id, domain.Folder{Name: input.Name, ParentId: input.ParentId})
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+
+		if errors.Is(err, domain.ErrInvalidValue) || errors.Is(err, domain.ErrAlreadyExists) {
+			status = http.StatusBadRequest
+		}
+
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("update folder error - %w", err),
+			status,
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, nil)
+}
+
+type dependentResponse struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (h *FolderHandler) DeleteFolder(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	err := h.uc.DeleteFolder(r.Context(), id)
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+
+		var dependentsErr *domain.DependentsError
+		if errors.As(err, &dependentsErr) {
+			dependents := make([]dependentResponse, len(dependentsErr.Dependents))
+			for i, d := range dependentsErr.Dependents {
+				dependents[i] = dependentResponse{Type: d.Type, Id: d.Id, Name: d.Name}
+			}
+
+			response.FailureResponseWithData(
+				w, r,
+				fmt.Errorf("delete folder error - %w", err),
+				http.StatusConflict,
+				dependents,
+			)
+			return
+		}
+
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("delete folder error - %w", err),
+			status,
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, nil)
+}
+
+func toFolderResponse(folder domain.Folder) folderResponse {
+	return folderResponse{
+		Id:        folder.Id,
+		Name:      folder.Name,
+		ParentId:  folder.ParentId,
+		CreatedAt: folder.CreatedAt,
+	}
+}
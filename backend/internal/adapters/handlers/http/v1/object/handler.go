@@ -4,11 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
-	"os"
-	"path/filepath"
+	"regexp"
 	"strconv"
 	"time"
 
@@ -19,32 +17,33 @@ import (
 	"github.com/go-chi/render"
 	"github.com/go-ozzo/ozzo-validation/is"
 	v "github.com/go-ozzo/ozzo-validation/v4"
-	"github.com/google/uuid"
 )
 
 type ObjectUsecase interface {
-	GetObjects(ctx context.Context, filter domain.ObjectFilter) ([]domain.Object, error)
+	GetObjects(ctx context.Context, filter domain.ObjectFilter) ([]domain.Object, domain.PageInfo, error)
 	GetObjectById(ctx context.Context, id string) (domain.Object, error)
 	UpdateObject(ctx context.Context, id string, object domain.Object) error
 	CreateObject(ctx context.Context, object domain.Object) (string, error)
 	DeleteObject(ctx context.Context, id string) error
-	SetObjectPhotoPath(ctx context.Context, id, path string) error
+	RestoreObject(ctx context.Context, id string) error
 }
 
 type ObjectHandler struct {
 	router        http.Handler
 	maxUploadSize int64
-	photosDir     string
 	uc            ObjectUsecase
+	photoUc       PhotoUsecase
+	auditUc       AuditUsecase
 }
 
-func NewObjectHandler(uc ObjectUsecase, photosDir string, maxSize int64) *ObjectHandler {
+func NewObjectHandler(uc ObjectUsecase, photoUc PhotoUsecase, auditUc AuditUsecase, maxSize int64) *ObjectHandler {
 	router := chi.NewRouter()
 	handler := &ObjectHandler{
 		router:        router,
 		maxUploadSize: maxSize << 20,
-		photosDir:     photosDir,
 		uc:            uc,
+		photoUc:       photoUc,
+		auditUc:       auditUc,
 	}
 
 	router.Get("/", handler.GetObjects)
@@ -52,9 +51,21 @@ func NewObjectHandler(uc ObjectUsecase, photosDir string, maxSize int64) *Object
 	router.Post("/", handler.CreateObject)
 	router.Put("/{id}", handler.UpdateObject)
 	router.Delete("/{id}", handler.DeleteObject)
-
-	router.Get("/{id}/photo", handler.GetObjectPhoto)
-	router.Post("/{id}/photo", handler.UploadObjectPhoto)
+	router.Post("/{id}/restore", handler.RestoreObject)
+	router.Get("/{id}/history", handler.GetObjectHistory)
+
+	// single photo endpoints work with the cover of the gallery
+	router.Get("/{id}/photo", handler.GetObjectCoverPhoto)
+	router.Head("/{id}/photo", handler.GetObjectCoverPhoto)
+	router.Post("/{id}/photo", handler.UploadObjectCoverPhoto)
+
+	router.Get("/{id}/photos", handler.GetObjectPhotos)
+	router.Post("/{id}/photos", handler.AddObjectPhoto)
+	router.Put("/{id}/photos/order", handler.ReorderObjectPhotos)
+	router.Get("/{id}/photos/{photoId}", handler.GetObjectPhotoContent)
+	router.Head("/{id}/photos/{photoId}", handler.GetObjectPhotoContent)
+	router.Put("/{id}/photos/{photoId}/cover", handler.SetObjectCoverPhoto)
+	router.Delete("/{id}/photos/{photoId}", handler.DeleteObjectPhoto)
 
 	return handler
 }
@@ -72,6 +83,19 @@ type objectResponse struct {
 	Disadvs       string              `json:"disadvs"`
 	ComparisonId  string              `json:"comparison_id"`
 	CustomOptions []map[string]string `json:"custom_options"`
+	Score         float64             `json:"score,omitempty"`
+	Highlight     *highlightResponse  `json:"highlight,omitempty"`
+}
+
+type highlightResponse struct {
+	Field          string                  `json:"field"`
+	CustomOptionId string                  `json:"custom_option_id,omitempty"`
+	Texts          []highlightTextResponse `json:"texts"`
+}
+
+type highlightTextResponse struct {
+	Value string `json:"value"`
+	Type  string `json:"type"`
 }
 
 func (h *ObjectHandler) GetObjects(w http.ResponseWriter, r *http.Request) {
@@ -85,12 +109,18 @@ func (h *ObjectHandler) GetObjects(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	objects, err := h.uc.GetObjects(r.Context(), filter)
+	objects, pageInfo, err := h.uc.GetObjects(r.Context(), filter)
 	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrInvalidValue) {
+			status = http.StatusBadRequest
+		}
+
 		response.FailureResponse(
 			w, r,
 			fmt.Errorf("get objects error - %w", err),
-			http.StatusInternalServerError,
+			status,
 		)
 		return
 	}
@@ -100,7 +130,7 @@ func (h *ObjectHandler) GetObjects(w http.ResponseWriter, r *http.Request) {
 		objectResponses[i] = toObjectResponse(o)
 	}
 
-	response.SuccessResponse(w, r, objectResponses)
+	response.SuccessListResponse(w, r, objectResponses, response.Pagination(pageInfo))
 }
 
 func (h *ObjectHandler) GetObjectById(w http.ResponseWriter, r *http.Request) {
@@ -135,17 +165,41 @@ type createObjectInput struct {
 }
 
 func (oi *createObjectInput) Bind(r *http.Request) error {
-	return v.ValidateStruct(oi,
-		v.Field(&oi.Name, v.Required, v.Length(1, 50)),
-		v.Field(&oi.Rating, v.Required, v.Min(1), v.Max(10)),
-		v.Field(&oi.Advs, v.Length(1, 3000)),
-		v.Field(&oi.Disadvs, v.Length(1, 3000)),
+	err := v.ValidateStruct(oi,
 		v.Field(&oi.ComparisonId, v.Required, is.UUIDv4),
 		v.Field(&oi.CustomOptions, v.Each(v.Map(
 			v.Key("id", v.Required, is.UUIDv4),
-			v.Key("value", v.Required, v.Length(1, 100)),
+			v.Key("value").Optional(),
 		))),
 	)
+	if err != nil {
+		return err
+	}
+
+	return oi.toObject().Validate()
+}
+
+func (oi *createObjectInput) toObject() domain.Object {
+	return domain.Object{
+		Name:                oi.Name,
+		Rating:              oi.Rating,
+		Advs:                oi.Advs,
+		Disadvs:             oi.Disadvs,
+		ComparisonId:        oi.ComparisonId,
+		ObjectCustomOptions: toObjectCustomOptions(oi.CustomOptions),
+	}
+}
+
+func toObjectCustomOptions(customOptions []map[string]string) []domain.ObjectCustomOption {
+	objCustOpts := make([]domain.ObjectCustomOption, len(customOptions))
+	for i, opt := range customOptions {
+		objCustOpts[i] = domain.ObjectCustomOption{
+			CustomOptionId: opt["id"],
+			Value:          opt["value"],
+		}
+	}
+
+	return objCustOpts
 }
 
 type returnedIdResponse struct {
@@ -172,27 +226,18 @@ func (h *ObjectHandler) CreateObject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	objCustOpts := make([]domain.ObjectCustomOption, len(input.CustomOptions))
-	for i, opt := range input.CustomOptions {
-		objCustOpts[i] = domain.ObjectCustomOption{
-			CustomOptionId: opt["id"],
-			Value:          opt["value"],
+	id, err := h.uc.CreateObject(r.Context(), input.toObject())
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrInvalidValue) || errors.Is(err, domain.ErrNotFound) {
+			status = http.StatusBadRequest
 		}
-	}
 
-	id, err := h.uc.CreateObject(r.Context(), domain.Object{
-		Name:                input.Name,
-		Rating:              input.Rating,
-		Advs:                input.Advs,
-		Disadvs:             input.Disadvs,
-		ComparisonId:        input.ComparisonId,
-		ObjectCustomOptions: objCustOpts,
-	})
-	if err != nil {
 		response.FailureResponse(
 			w, r,
 			fmt.Errorf("create object error - %w", err),
-			http.StatusInternalServerError,
+			status,
 		)
 		return
 	}
@@ -209,16 +254,27 @@ type updateObjectInput struct {
 }
 
 func (oi *updateObjectInput) Bind(r *http.Request) error {
-	return v.ValidateStruct(oi,
-		v.Field(&oi.Name, v.Required, v.Length(1, 50)),
-		v.Field(&oi.Rating, v.Required, v.Min(1), v.Max(10)),
-		v.Field(&oi.Advs, v.Length(1, 3000)),
-		v.Field(&oi.Disadvs, v.Length(1, 3000)),
+	err := v.ValidateStruct(oi,
 		v.Field(&oi.CustomOptions, v.Each(v.Map(
 			v.Key("id", v.Required, is.UUIDv4),
-			v.Key("value", v.Required, v.Length(1, 100)),
+			v.Key("value").Optional(),
 		))),
 	)
+	if err != nil {
+		return err
+	}
+
+	return oi.toObject().Validate()
+}
+
+func (oi *updateObjectInput) toObject() domain.Object {
+	return domain.Object{
+		Name:                oi.Name,
+		Rating:              oi.Rating,
+		Advs:                oi.Advs,
+		Disadvs:             oi.Disadvs,
+		ObjectCustomOptions: toObjectCustomOptions(oi.CustomOptions),
+	}
 }
 
 func (h *ObjectHandler) UpdateObject(w http.ResponseWriter, r *http.Request) {
@@ -243,22 +299,7 @@ func (h *ObjectHandler) UpdateObject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	objCustOpts := make([]domain.ObjectCustomOption, len(input.CustomOptions))
-	for i, opt := range input.CustomOptions {
-		objCustOpts[i] = domain.ObjectCustomOption{
-			ObjectId:       id,
-			CustomOptionId: opt["id"],
-			Value:          opt["value"],
-		}
-	}
-
-	err := h.uc.UpdateObject(r.Context(), id, domain.Object{
-		Name:                input.Name,
-		Rating:              input.Rating,
-		Advs:                input.Advs,
-		Disadvs:             input.Disadvs,
-		ObjectCustomOptions: objCustOpts,
-	})
+	err := h.uc.UpdateObject(r.Context(), id, input.toObject())
 	if err != nil {
 		status := http.StatusInternalServerError
 
@@ -266,6 +307,10 @@ func (h *ObjectHandler) UpdateObject(w http.ResponseWriter, r *http.Request) {
 			status = http.StatusNotFound
 		}
 
+		if errors.Is(err, domain.ErrInvalidValue) {
+			status = http.StatusBadRequest
+		}
+
 		response.FailureResponse(
 			w, r,
 			fmt.Errorf("update object error - %w", err),
@@ -298,148 +343,33 @@ func (h *ObjectHandler) DeleteObject(w http.ResponseWriter, r *http.Request) {
 	response.SuccessResponse(w, r, nil)
 }
 
-func (h *ObjectHandler) UploadObjectPhoto(w http.ResponseWriter, r *http.Request) {
+func (h *ObjectHandler) RestoreObject(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadSize)
-	if err := r.ParseMultipartForm(h.maxUploadSize); err != nil {
-		response.FailureResponse(
-			w, r,
-			fmt.Errorf("failted to parse multipart form - %w", err),
-			http.StatusBadRequest,
-		)
-		return
-	}
-
-	file, fileHeader, err := r.FormFile("photo")
-	if err != nil {
-		response.FailureResponse(
-			w, r,
-			fmt.Errorf("failed to get photo - %w", err),
-			http.StatusBadRequest,
-		)
-		return
-	}
-	defer file.Close()
-
-	buff := make([]byte, 512)
-	_, err = file.Read(buff)
-	if err != nil {
-		response.FailureResponse(
-			w, r,
-			fmt.Errorf("failed to read photo - %w", err),
-			http.StatusInternalServerError,
-		)
-		return
-	}
-
-	filetype := http.DetectContentType(buff)
-	if filetype != "image/jpeg" && filetype != "image/png" {
-		response.FailureResponse(
-			w, r,
-			fmt.Errorf("invalid photo format, must be image/jpeg or image/png"),
-			http.StatusBadRequest,
-		)
-		return
-	}
-
-	_, err = file.Seek(0, io.SeekStart)
-	if err != nil {
-		response.FailureResponse(
-			w, r,
-			fmt.Errorf("failed to seek photo - %w", err),
-			http.StatusBadRequest,
-		)
-		return
-	}
-
-	photoPath := fmt.Sprintf(
-		"%s/%s%s",
-		h.photosDir,
-		uuid.NewString(),
-		filepath.Ext(fileHeader.Filename),
-	)
-
-	newPhoto, err := os.Create(photoPath)
-	if err != nil {
-		response.FailureResponse(
-			w, r,
-			fmt.Errorf("failed to create photo - %w", err),
-			http.StatusInternalServerError,
-		)
-		return
-	}
-	defer newPhoto.Close()
-
-	_, err = io.Copy(newPhoto, file)
-	if err != nil {
-		response.FailureResponse(
-			w, r,
-			fmt.Errorf("failed to save photo - %w", err),
-			http.StatusInternalServerError,
-		)
-		return
-	}
-
-	if err := h.uc.SetObjectPhotoPath(r.Context(), id, photoPath); err != nil {
-		response.FailureResponse(
-			w, r,
-			fmt.Errorf("failed to set photo path - %w", err),
-			http.StatusInternalServerError,
-		)
-		os.Remove(photoPath)
-		return
-	}
-
-	response.SuccessResponse(w, r, nil)
-}
-func (h *ObjectHandler) GetObjectPhoto(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
-
-	object, err := h.uc.GetObjectById(r.Context(), id)
-	if err != nil {
-		status := http.StatusInternalServerError
-
-		if errors.Is(err, domain.ErrNotFound) {
-			status = http.StatusNotFound
-		}
-
-		response.FailureResponse(
-			w, r,
-			fmt.Errorf("failed to set photo path - %w", err),
-			status,
-		)
-		return
-	}
-
-	file, err := os.Open(object.PhotoPath)
+	err := h.uc.RestoreObject(r.Context(), id)
 	if err != nil {
 		status := http.StatusInternalServerError
 
-		if errors.Is(err, os.ErrNotExist) {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
 			status = http.StatusNotFound
+		case errors.Is(err, domain.ErrAlreadyExists), errors.Is(err, domain.ErrConflict):
+			status = http.StatusConflict
 		}
 
 		response.FailureResponse(
 			w, r,
-			fmt.Errorf("failed to open photo - %w", err),
+			fmt.Errorf("restore object error - %w", err),
 			status,
 		)
 		return
 	}
 
-	_, err = io.Copy(w, file)
-	if err != nil {
-		response.FailureResponse(
-			w, r,
-			fmt.Errorf("failed to send photo - %w", err),
-			http.StatusInternalServerError,
-		)
-		return
-	}
-
-	w.Header().Set("Content-Type", "image/jpeg")
+	response.SuccessResponse(w, r, nil)
 }
 
+// optionPredicateParam matches query parameters like option[<id>][gte]=500.
+var optionPredicateParam = regexp.MustCompile(`^option\[([^\]]+)\]\[([a-z]+)\]$`)
+
 func (h *ObjectHandler) getFilter(params url.Values) (domain.ObjectFilter, error) {
 	var limit int
 	var offset int
@@ -468,7 +398,27 @@ func (h *ObjectHandler) getFilter(params url.Values) (domain.ObjectFilter, error
 	name = params.Get("name")
 	comparisonId = params.Get("comparison_id")
 
-	return domain.NewObjectFilter(limit, offset, orderBy, name, comparisonId)
+	optionPredicates := make([]domain.OptionPredicate, 0)
+	for key, values := range params {
+		matches := optionPredicateParam.FindStringSubmatch(key)
+		if matches == nil {
+			continue
+		}
+
+		for _, value := range values {
+			optionPredicates = append(optionPredicates, domain.OptionPredicate{
+				CustomOptionId: matches[1],
+				Operator:       domain.OptionOperator(matches[2]),
+				Value:          value,
+			})
+		}
+	}
+
+	return domain.NewObjectFilter(
+		limit, offset,
+		params.Get("cursor"), orderBy, name, params.Get("q"), comparisonId,
+		optionPredicates,
+	)
 }
 
 func toObjectResponse(object domain.Object) objectResponse {
@@ -488,5 +438,31 @@ func toObjectResponse(object domain.Object) objectResponse {
 		Disadvs:       object.Disadvs,
 		ComparisonId:  object.ComparisonId,
 		CustomOptions: customOpts,
+		Score:         object.Score,
+		Highlight:     toHighlightResponse(object.Highlight),
+	}
+}
+
+// toHighlightResponse gives parts of the highlighted fragment typed as "hit" or "text",
+// nil when nothing is highlighted.
+func toHighlightResponse(highlight domain.Highlight) *highlightResponse {
+	if len(highlight.Texts) == 0 {
+		return nil
+	}
+
+	texts := make([]highlightTextResponse, len(highlight.Texts))
+	for i, t := range highlight.Texts {
+		textType := "text"
+		if t.Hit {
+			textType = "hit"
+		}
+
+		texts[i] = highlightTextResponse{Value: t.Value, Type: textType}
+	}
+
+	return &highlightResponse{
+		Field:          highlight.Field,
+		CustomOptionId: highlight.CustomOptionId,
+		Texts:          texts,
 	}
 }
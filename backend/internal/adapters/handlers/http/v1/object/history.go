@@ -0,0 +1,109 @@
+package object
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/response"
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type AuditUsecase interface {
+	GetHistory(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, domain.PageInfo, error)
+}
+
+type fieldChangeResponse struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+type auditEventResponse struct {
+	Id        string                `json:"id"`
+	Action    string                `json:"action"`
+	CreatedAt time.Time             `json:"created_at"`
+	Changes   []fieldChangeResponse `json:"changes"`
+}
+
+func (h *ObjectHandler) GetObjectHistory(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	filter, err := getHistoryFilter(r.URL.Query(), id)
+	if err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("parse filter error - %w", err),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	events, pageInfo, err := h.auditUc.GetHistory(r.Context(), filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrInvalidValue) {
+			status = http.StatusBadRequest
+		}
+
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("get object history error - %w", err),
+			status,
+		)
+		return
+	}
+
+	eventResponses := make([]auditEventResponse, len(events))
+	for i, e := range events {
+		eventResponses[i] = toAuditEventResponse(e)
+	}
+
+	response.SuccessListResponse(w, r, eventResponses, response.Pagination(pageInfo))
+}
+
+func getHistoryFilter(params url.Values, id string) (domain.AuditFilter, error) {
+	var limit int
+	var offset int
+
+	var err error
+
+	limitStr := params.Get("limit")
+	if limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return domain.AuditFilter{}, fmt.Errorf("incorrect limit value")
+		}
+	}
+
+	offsetStr := params.Get("offset")
+	if offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil {
+			return domain.AuditFilter{}, fmt.Errorf("incorrect offset value")
+		}
+	}
+
+	return domain.NewAuditFilter(limit, offset, params.Get("cursor"), domain.AuditEntityObject, id)
+}
+
+func toAuditEventResponse(event domain.AuditEvent) auditEventResponse {
+	changes := make([]fieldChangeResponse, len(event.Changes))
+	for i, c := range event.Changes {
+		changes[i] = fieldChangeResponse{Field: c.Field, Before: c.Before, After: c.After}
+	}
+
+	return auditEventResponse{
+		Id:        event.Id,
+		Action:    string(event.Action),
+		CreatedAt: event.CreatedAt,
+		Changes:   changes,
+	}
+}
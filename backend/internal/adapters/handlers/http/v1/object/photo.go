@@ -0,0 +1,321 @@
+package object
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/response"
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// photoCacheControl lets caches keep the photo for a while, after that it is
+// revalidated by ETag, since the cover of the object can be replaced.
+const photoCacheControl = "public, max-age=300, must-revalidate"
+
+type PhotoUsecase interface {
+	GetObjectPhotos(ctx context.Context, objectId string) ([]domain.Photo, error)
+	AddObjectPhoto(ctx context.Context, objectId string, content io.Reader, cover bool) (domain.Photo, error)
+	ReplaceObjectCoverPhoto(ctx context.Context, objectId string, content io.Reader) (domain.Photo, error)
+	SetObjectCoverPhoto(ctx context.Context, objectId, photoId string) error
+	ReorderObjectPhotos(ctx context.Context, objectId string, photoIds []string) error
+	DeleteObjectPhoto(ctx context.Context, objectId, photoId string) error
+	GetObjectPhotoContent(
+		ctx context.Context,
+		objectId, photoId string,
+		size domain.PhotoSize,
+	) (io.ReadSeekCloser, domain.PhotoInfo, error)
+	GetObjectCoverContent(
+		ctx context.Context,
+		objectId string,
+		size domain.PhotoSize,
+	) (io.ReadSeekCloser, domain.PhotoInfo, error)
+}
+
+type photoResponse struct {
+	Id          string    `json:"id"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	Position    int       `json:"position"`
+	IsCover     bool      `json:"is_cover"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (h *ObjectHandler) GetObjectPhotos(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	photos, err := h.photoUc.GetObjectPhotos(r.Context(), id)
+	if err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("get photos error - %w", err),
+			photoErrorStatus(err),
+		)
+		return
+	}
+
+	photoResponses := make([]photoResponse, len(photos))
+	for i, p := range photos {
+		photoResponses[i] = toPhotoResponse(p)
+	}
+
+	response.SuccessResponse(w, r, photoResponses)
+}
+
+func (h *ObjectHandler) AddObjectPhoto(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	file, err := h.readPhotoFile(w, r)
+	if err != nil {
+		response.FailureResponse(w, r, err, http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	photo, err := h.photoUc.AddObjectPhoto(r.Context(), id, file, r.FormValue("cover") == "true")
+	if err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("failed to upload photo - %w", err),
+			photoErrorStatus(err),
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, toPhotoResponse(photo))
+}
+
+// UploadObjectCoverPhoto replaces the cover of the gallery with the photo.
+func (h *ObjectHandler) UploadObjectCoverPhoto(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	file, err := h.readPhotoFile(w, r)
+	if err != nil {
+		response.FailureResponse(w, r, err, http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	photo, err := h.photoUc.ReplaceObjectCoverPhoto(r.Context(), id, file)
+	if err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("failed to upload photo - %w", err),
+			photoErrorStatus(err),
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, toPhotoResponse(photo))
+}
+
+// readPhotoFile gives the photo of the multipart form, only JPEG and PNG are accepted.
+func (h *ObjectHandler) readPhotoFile(w http.ResponseWriter, r *http.Request) (multipart.File, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadSize)
+	if err := r.ParseMultipartForm(h.maxUploadSize); err != nil {
+		return nil, fmt.Errorf("failed to parse multipart form - %w", err)
+	}
+
+	file, _, err := r.FormFile("photo")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get photo - %w", err)
+	}
+
+	buff := make([]byte, 512)
+	if _, err := file.Read(buff); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read photo - %w", err)
+	}
+
+	filetype := http.DetectContentType(buff)
+	if filetype != "image/jpeg" && filetype != "image/png" {
+		file.Close()
+		return nil, fmt.Errorf("invalid photo format, must be image/jpeg or image/png")
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to seek photo - %w", err)
+	}
+
+	return file, nil
+}
+
+type reorderPhotosInput struct {
+	PhotoIds []string `json:"photo_ids"`
+}
+
+func (pi *reorderPhotosInput) Bind(r *http.Request) error {
+	return v.ValidateStruct(pi,
+		v.Field(&pi.PhotoIds, v.Required, v.Length(1, domain.MaxObjectPhotos)),
+	)
+}
+
+func (h *ObjectHandler) ReorderObjectPhotos(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if r.Body == http.NoBody {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("validation error - request body required"),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	var input reorderPhotosInput
+	if err := render.Bind(r, &input); err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("validation error - %w", err),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	if err := h.photoUc.ReorderObjectPhotos(r.Context(), id, input.PhotoIds); err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("reorder photos error - %w", err),
+			photoErrorStatus(err),
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, nil)
+}
+
+func (h *ObjectHandler) SetObjectCoverPhoto(w http.ResponseWriter, r *http.Request) {
+	id, photoId := chi.URLParam(r, "id"), chi.URLParam(r, "photoId")
+
+	if err := h.photoUc.SetObjectCoverPhoto(r.Context(), id, photoId); err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("set cover photo error - %w", err),
+			photoErrorStatus(err),
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, nil)
+}
+
+func (h *ObjectHandler) DeleteObjectPhoto(w http.ResponseWriter, r *http.Request) {
+	id, photoId := chi.URLParam(r, "id"), chi.URLParam(r, "photoId")
+
+	if err := h.photoUc.DeleteObjectPhoto(r.Context(), id, photoId); err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("delete photo error - %w", err),
+			photoErrorStatus(err),
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, nil)
+}
+
+func (h *ObjectHandler) GetObjectPhotoContent(w http.ResponseWriter, r *http.Request) {
+	id, photoId := chi.URLParam(r, "id"), chi.URLParam(r, "photoId")
+
+	size, err := domain.ParsePhotoSize(r.URL.Query().Get("size"))
+	if err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("parse photo size error - %w", err),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	content, info, err := h.photoUc.GetObjectPhotoContent(r.Context(), id, photoId, size)
+	if err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("failed to get photo - %w", err),
+			photoErrorStatus(err),
+		)
+		return
+	}
+	defer content.Close()
+
+	servePhoto(w, r, content, info)
+}
+
+func (h *ObjectHandler) GetObjectCoverPhoto(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	size, err := domain.ParsePhotoSize(r.URL.Query().Get("size"))
+	if err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("parse photo size error - %w", err),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	content, info, err := h.photoUc.GetObjectCoverContent(r.Context(), id, size)
+	if err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("failed to get photo - %w", err),
+			photoErrorStatus(err),
+		)
+		return
+	}
+	defer content.Close()
+
+	servePhoto(w, r, content, info)
+}
+
+func servePhoto(w http.ResponseWriter, r *http.Request, content io.ReadSeeker, info domain.PhotoInfo) {
+	// without content type known to the store it is detected by ServeContent
+	if info.ContentType != "" {
+		w.Header().Set("Content-Type", info.ContentType)
+	}
+	if info.ETag != "" {
+		w.Header().Set("ETag", strconv.Quote(info.ETag))
+	}
+	w.Header().Set("Cache-Control", photoCacheControl)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	// ServeContent answers conditional requests with 304 and serves byte ranges
+	http.ServeContent(w, r, path.Base(info.Key), info.ModifiedAt, content)
+}
+
+func photoErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidValue):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func toPhotoResponse(photo domain.Photo) photoResponse {
+	return photoResponse{
+		Id:          photo.Id,
+		ContentType: photo.ContentType,
+		Size:        photo.Size,
+		Width:       photo.Width,
+		Height:      photo.Height,
+		Position:    photo.Position,
+		IsCover:     photo.IsCover,
+		CreatedAt:   photo.CreatedAt,
+	}
+}
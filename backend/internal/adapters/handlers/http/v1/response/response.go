@@ -1,7 +1,10 @@
 package response
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/go-chi/render"
 )
@@ -20,9 +23,72 @@ func SuccessResponse(w http.ResponseWriter, r *http.Request, data any) {
 }
 
 func FailureResponse(w http.ResponseWriter, r *http.Request, err error, statusCode int) {
+	FailureResponseWithData(w, r, err, statusCode, nil)
+}
+
+// FailureResponseWithData is FailureResponse carrying details of the failure, e.g. dependents
+// that prevent deletion.
+func FailureResponseWithData(w http.ResponseWriter, r *http.Request, err error, statusCode int, data any) {
 	render.Status(r, statusCode)
 	render.JSON(w, r, &response{
 		Success: false,
+		Data:    data,
 		Message: err.Error(),
 	})
 }
+
+// Pagination describes the page of a list, empty cursor means there is no page in that direction.
+type Pagination struct {
+	Total      int64
+	NextCursor string
+	PrevCursor string
+}
+
+type paginationResponse struct {
+	Total      int64   `json:"total"`
+	NextCursor *string `json:"next_cursor"`
+	PrevCursor *string `json:"prev_cursor"`
+}
+
+type listResponse struct {
+	Success    bool               `json:"success"`
+	Data       any                `json:"data"`
+	Pagination paginationResponse `json:"pagination"`
+}
+
+// SuccessListResponse responds with the page of a list and its pagination metadata.
+// Links to neighbouring pages are also set to Link header.
+func SuccessListResponse(w http.ResponseWriter, r *http.Request, data any, pagination Pagination) {
+	var links []string
+	resp := paginationResponse{Total: pagination.Total}
+
+	if pagination.NextCursor != "" {
+		resp.NextCursor = &pagination.NextCursor
+		links = append(links, pageLink(r, pagination.NextCursor, "next"))
+	}
+
+	if pagination.PrevCursor != "" {
+		resp.PrevCursor = &pagination.PrevCursor
+		links = append(links, pageLink(r, pagination.PrevCursor, "prev"))
+	}
+
+	if len(links) != 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+
+	render.JSON(w, r, &listResponse{
+		Success:    true,
+		Data:       data,
+		Pagination: resp,
+	})
+}
+
+func pageLink(r *http.Request, cursor, rel string) string {
+	query := r.URL.Query()
+	query.Del("offset")
+	query.Set("cursor", cursor)
+
+	link := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+
+	return fmt.Sprintf(`<%s>; rel="%s"`, link.String(), rel)
+}
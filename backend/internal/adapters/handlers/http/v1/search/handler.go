@@ -0,0 +1,235 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/response"
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type SearchUsecase interface {
+	Search(ctx context.Context, filter domain.SearchFilter) (domain.SearchResults, error)
+}
+
+type SearchHandler struct {
+	router http.Handler
+	uc     SearchUsecase
+}
+
+func NewSearchHandler(uc SearchUsecase) *SearchHandler {
+	router := chi.NewRouter()
+	handler := &SearchHandler{router: router, uc: uc}
+
+	router.Get("/", handler.Search)
+
+	return handler
+}
+
+func (h *SearchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.router.ServeHTTP(w, r)
+}
+
+// Every result has its type, so results of all groups can be shown in one list.
+const (
+	resultTypeComparison   = "comparison"
+	resultTypeObject       = "object"
+	resultTypeCustomOption = "custom_option"
+)
+
+// searchResponse has a group of results per type, every group is limited by limit
+// parameter and its total tells how many entities of the type match the query.
+type searchResponse struct {
+	Comparisons   comparisonResultsResponse   `json:"comparisons"`
+	Objects       objectResultsResponse       `json:"objects"`
+	CustomOptions customOptionResultsResponse `json:"custom_options"`
+}
+
+type comparisonResultsResponse struct {
+	Items []comparisonResultResponse `json:"items"`
+	Total int64                      `json:"total"`
+}
+
+type objectResultsResponse struct {
+	Items []objectResultResponse `json:"items"`
+	Total int64                  `json:"total"`
+}
+
+type customOptionResultsResponse struct {
+	Items []customOptionResultResponse `json:"items"`
+	Total int64                        `json:"total"`
+}
+
+type comparisonResultResponse struct {
+	Type      string    `json:"type"`
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	Tags      []string  `json:"tags"`
+	FolderId  string    `json:"folder_id"`
+}
+
+type objectResultResponse struct {
+	Type       string                   `json:"type"`
+	Id         string                   `json:"id"`
+	Name       string                   `json:"name"`
+	Rating     int                      `json:"rating"`
+	Score      float64                  `json:"score"`
+	Highlight  *highlightResponse       `json:"highlight,omitempty"`
+	Comparison parentComparisonResponse `json:"comparison"`
+}
+
+type parentComparisonResponse struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type customOptionResultResponse struct {
+	Type       string `json:"type"`
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	OptionType string `json:"option_type"`
+}
+
+type highlightResponse struct {
+	Field          string                  `json:"field"`
+	CustomOptionId string                  `json:"custom_option_id,omitempty"`
+	Texts          []highlightTextResponse `json:"texts"`
+}
+
+type highlightTextResponse struct {
+	Value string `json:"value"`
+	Type  string `json:"type"`
+}
+
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	filter, err := h.getFilter(r.URL.Query())
+	if err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("parse filter error - %w", err),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	results, err := h.uc.Search(r.Context(), filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrInvalidValue) {
+			status = http.StatusBadRequest
+		}
+
+		if errors.Is(err, domain.ErrTimeout) {
+			status = http.StatusGatewayTimeout
+		}
+
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("search error - %w", err),
+			status,
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, toSearchResponse(results))
+}
+
+func (h *SearchHandler) getFilter(params url.Values) (domain.SearchFilter, error) {
+	var limit int
+
+	var err error
+
+	limitStr := params.Get("limit")
+	if limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return domain.SearchFilter{}, fmt.Errorf("incorrect limit value")
+		}
+	}
+
+	return domain.NewSearchFilter(params.Get("q"), limit)
+}
+
+func toSearchResponse(results domain.SearchResults) searchResponse {
+	comparisons := make([]comparisonResultResponse, len(results.Comparisons))
+	for i, c := range results.Comparisons {
+		tags := c.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+
+		comparisons[i] = comparisonResultResponse{
+			Type:      resultTypeComparison,
+			Id:        c.Id,
+			Name:      c.Name,
+			CreatedAt: c.CreatedAt,
+			Tags:      tags,
+			FolderId:  c.FolderId,
+		}
+	}
+
+	objects := make([]objectResultResponse, len(results.Objects))
+	for i, o := range results.Objects {
+		objects[i] = objectResultResponse{
+			Type:      resultTypeObject,
+			Id:        o.Object.Id,
+			Name:      o.Object.Name,
+			Rating:    o.Object.Rating,
+			Score:     o.Object.Score,
+			Highlight: toHighlightResponse(o.Object.Highlight),
+			Comparison: parentComparisonResponse{
+				Id:   o.Object.ComparisonId,
+				Name: o.Comparison.Name,
+			},
+		}
+	}
+
+	customOptions := make([]customOptionResultResponse, len(results.CustomOptions))
+	for i, co := range results.CustomOptions {
+		customOptions[i] = customOptionResultResponse{
+			Type:       resultTypeCustomOption,
+			Id:         co.Id,
+			Name:       co.Name,
+			OptionType: string(co.Type),
+		}
+	}
+
+	return searchResponse{
+		Comparisons:   comparisonResultsResponse{Items: comparisons, Total: results.ComparisonsTotal},
+		Objects:       objectResultsResponse{Items: objects, Total: results.ObjectsTotal},
+		CustomOptions: customOptionResultsResponse{Items: customOptions, Total: results.CustomOptionsTotal},
+	}
+}
+
+// toHighlightResponse gives parts of the highlighted fragment typed as "hit" or "text",
+// nil when nothing is highlighted.
+func toHighlightResponse(highlight domain.Highlight) *highlightResponse {
+	if len(highlight.Texts) == 0 {
+		return nil
+	}
+
+	texts := make([]highlightTextResponse, len(highlight.Texts))
+	for i, t := range highlight.Texts {
+		textType := "text"
+		if t.Hit {
+			textType = "hit"
+		}
+
+		texts[i] = highlightTextResponse{Value: t.Value, Type: textType}
+	}
+
+	return &highlightResponse{
+		Field:          highlight.Field,
+		CustomOptionId: highlight.CustomOptionId,
+		Texts:          texts,
+	}
+}
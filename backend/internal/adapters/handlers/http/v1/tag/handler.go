@@ -0,0 +1,58 @@
+package tag
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/response"
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type TagUsecase interface {
+	GetTags(ctx context.Context) ([]domain.TagUsage, error)
+}
+
+type TagHandler struct {
+	router http.Handler
+	uc     TagUsecase
+}
+
+func NewTagHandler(uc TagUsecase) *TagHandler {
+	router := chi.NewRouter()
+	handler := &TagHandler{router: router, uc: uc}
+
+	router.Get("/", handler.GetTags)
+
+	return handler
+}
+
+func (h *TagHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.router.ServeHTTP(w, r)
+}
+
+type tagResponse struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func (h *TagHandler) GetTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.uc.GetTags(r.Context())
+	if err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("get tags error - %w", err),
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	tagResponses := make([]tagResponse, len(tags))
+	for i, t := range tags {
+		tagResponses[i] = tagResponse{Name: t.Name, Count: t.Count}
+	}
+
+	response.SuccessResponse(w, r, tagResponses)
+}
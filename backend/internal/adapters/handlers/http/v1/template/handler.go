@@ -0,0 +1,360 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/response"
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	v "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+type TemplateUsecase interface {
+	GetTemplates(ctx context.Context, filter domain.TemplateFilter) ([]domain.Template, domain.PageInfo, error)
+	GetTemplateById(ctx context.Context, id string) (domain.Template, error)
+	CreateTemplate(ctx context.Context, template domain.Template) (string, error)
+	UpdateTemplate(ctx context.Context, id string, template domain.Template) error
+	DeleteTemplate(ctx context.Context, id string) error
+}
+
+type TemplateHandler struct {
+	router http.Handler
+	uc     TemplateUsecase
+}
+
+func NewTemplateHandler(uc TemplateUsecase) *TemplateHandler {
+	router := chi.NewRouter()
+	handler := &TemplateHandler{router: router, uc: uc}
+
+	router.Get("/", handler.GetTemplates)
+	router.Get("/{id}", handler.GetTemplateById)
+	router.Post("/", handler.CreateTemplate)
+	router.Put("/{id}", handler.UpdateTemplate)
+	router.Delete("/{id}", handler.DeleteTemplate)
+
+	return handler
+}
+
+func (h *TemplateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.router.ServeHTTP(w, r)
+}
+
+type templateOptionResponse struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	EnumValues []string `json:"enum_values,omitempty"`
+	Currency   string   `json:"currency,omitempty"`
+	Weight     float64  `json:"weight"`
+	Direction  string   `json:"direction"`
+}
+
+type templateResponse struct {
+	Id          string                   `json:"id"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Options     []templateOptionResponse `json:"options"`
+	BuiltIn     bool                     `json:"built_in"`
+	CreatedAt   time.Time                `json:"created_at"`
+}
+
+func (h *TemplateHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	filter, err := h.getFilter(r.URL.Query())
+	if err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("parse filter error - %w", err),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	templates, pageInfo, err := h.uc.GetTemplates(r.Context(), filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrInvalidValue) {
+			status = http.StatusBadRequest
+		}
+
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("get templates error - %w", err),
+			status,
+		)
+		return
+	}
+
+	templateResponses := make([]templateResponse, len(templates))
+	for i, t := range templates {
+		templateResponses[i] = toTemplateResponse(t)
+	}
+
+	response.SuccessListResponse(w, r, templateResponses, response.Pagination(pageInfo))
+}
+
+func (h *TemplateHandler) GetTemplateById(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	template, err := h.uc.GetTemplateById(r.Context(), id)
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("get template error - %w", err),
+			status,
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, toTemplateResponse(template))
+}
+
+type templateOptionInput struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	EnumValues []string `json:"enum_values"`
+	Currency   string   `json:"currency"`
+	Weight     *float64 `json:"weight"`
+	Direction  string   `json:"direction"`
+}
+
+func (oi templateOptionInput) Validate() error {
+	types := make([]interface{}, len(domain.ProvidedCustomOptionTypes))
+	for i, t := range domain.ProvidedCustomOptionTypes {
+		types[i] = string(t)
+	}
+
+	directions := make([]interface{}, len(domain.ProvidedScoreDirections))
+	for i, d := range domain.ProvidedScoreDirections {
+		directions[i] = string(d)
+	}
+
+	return v.ValidateStruct(&oi,
+		v.Field(&oi.Name, v.Required, v.Length(1, 50)),
+		v.Field(&oi.Type, v.In(types...)),
+		v.Field(&oi.EnumValues, v.Each(v.Required, v.Length(1, 100))),
+		v.Field(&oi.Currency, is.CurrencyCode),
+		v.Field(&oi.Weight, v.Min(0.0)),
+		v.Field(&oi.Direction, v.In(directions...)),
+	)
+}
+
+type templateInput struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Options     []templateOptionInput `json:"options"`
+}
+
+func (ti *templateInput) Bind(r *http.Request) error {
+	return v.ValidateStruct(ti,
+		v.Field(&ti.Name, v.Required, v.Length(1, 50)),
+		v.Field(&ti.Description, v.Length(0, 500)),
+		v.Field(&ti.Options),
+	)
+}
+
+type returnedIdResponse struct {
+	Id string `json:"id"`
+}
+
+func (h *TemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	if r.Body == http.NoBody {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("validation error - request body required"),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	var input templateInput
+	if err := render.Bind(r, &input); err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("validation error - %w", err),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	id, err := h.uc.CreateTemplate(r.Context(), toDomainTemplate(input))
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrInvalidValue) || errors.Is(err, domain.ErrAlreadyExists) {
+			status = http.StatusBadRequest
+		}
+
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("create template error - %w", err),
+			status,
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, &returnedIdResponse{Id: id})
+}
+
+func (h *TemplateHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	if r.Body == http.NoBody {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("validation error - request body required"),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+
+	var input templateInput
+	if err := render.Bind(r, &input); err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("validation error - %w", err),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	err := h.uc.UpdateTemplate(r.Context(), id, toDomainTemplate(input))
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+
+		if errors.Is(err, domain.ErrInvalidValue) || errors.Is(err, domain.ErrAlreadyExists) {
+			status = http.StatusBadRequest
+		}
+
+		if errors.Is(err, domain.ErrConflict) {
+			status = http.StatusConflict
+		}
+
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("update template error - %w", err),
+			status,
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, nil)
+}
+
+func (h *TemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	err := h.uc.DeleteTemplate(r.Context(), id)
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+
+		if errors.Is(err, domain.ErrConflict) {
+			status = http.StatusConflict
+		}
+
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("delete template error - %w", err),
+			status,
+		)
+		return
+	}
+
+	response.SuccessResponse(w, r, nil)
+}
+
+func (h *TemplateHandler) getFilter(params url.Values) (domain.TemplateFilter, error) {
+	var limit int
+	var offset int
+
+	var err error
+
+	limitStr := params.Get("limit")
+	if limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return domain.TemplateFilter{}, fmt.Errorf("incorrect limit value")
+		}
+	}
+
+	offsetStr := params.Get("offset")
+	if offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil {
+			return domain.TemplateFilter{}, fmt.Errorf("incorrect offset value")
+		}
+	}
+
+	return domain.NewTemplateFilter(limit, offset, params.Get("cursor"), params.Get("order_by"), params.Get("name"))
+}
+
+// toDomainTemplate converts the input to the template, options without weight get weight 1.
+func toDomainTemplate(input templateInput) domain.Template {
+	options := make([]domain.TemplateOption, len(input.Options))
+	for i, oi := range input.Options {
+		weight := 1.0
+		if oi.Weight != nil {
+			weight = *oi.Weight
+		}
+
+		options[i] = domain.TemplateOption{
+			Name:       oi.Name,
+			Type:       domain.CustomOptionType(oi.Type),
+			EnumValues: oi.EnumValues,
+			Currency:   oi.Currency,
+			Weight:     weight,
+			Direction:  domain.ScoreDirection(oi.Direction),
+		}
+	}
+
+	return domain.Template{
+		Name:        input.Name,
+		Description: input.Description,
+		Options:     options,
+	}
+}
+
+func toTemplateResponse(template domain.Template) templateResponse {
+	options := make([]templateOptionResponse, len(template.Options))
+	for i, o := range template.Options {
+		options[i] = templateOptionResponse{
+			Name:       o.Name,
+			Type:       string(o.Type),
+			EnumValues: o.EnumValues,
+			Currency:   o.Currency,
+			Weight:     o.Weight,
+			Direction:  string(o.Direction),
+		}
+	}
+
+	return templateResponse{
+		Id:          template.Id,
+		Name:        template.Name,
+		Description: template.Description,
+		Options:     options,
+		BuiltIn:     template.BuiltIn,
+		CreatedAt:   template.CreatedAt,
+	}
+}
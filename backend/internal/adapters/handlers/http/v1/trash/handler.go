@@ -0,0 +1,112 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/adapters/handlers/http/v1/response"
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type TrashUsecase interface {
+	GetTrash(ctx context.Context, filter domain.TrashFilter) ([]domain.TrashItem, domain.PageInfo, error)
+}
+
+type TrashHandler struct {
+	router http.Handler
+	uc     TrashUsecase
+}
+
+func NewTrashHandler(uc TrashUsecase) *TrashHandler {
+	router := chi.NewRouter()
+	handler := &TrashHandler{router: router, uc: uc}
+
+	router.Get("/", handler.GetTrash)
+
+	return handler
+}
+
+func (h *TrashHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.router.ServeHTTP(w, r)
+}
+
+type trashItemResponse struct {
+	Type         string    `json:"type"`
+	Id           string    `json:"id"`
+	Name         string    `json:"name"`
+	ComparisonId string    `json:"comparison_id,omitempty"`
+	DeletedAt    time.Time `json:"deleted_at"`
+}
+
+func (h *TrashHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	filter, err := getTrashFilter(r.URL.Query())
+	if err != nil {
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("parse filter error - %w", err),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	items, pageInfo, err := h.uc.GetTrash(r.Context(), filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrInvalidValue) {
+			status = http.StatusBadRequest
+		}
+
+		response.FailureResponse(
+			w, r,
+			fmt.Errorf("get trash error - %w", err),
+			status,
+		)
+		return
+	}
+
+	itemsResp := make([]trashItemResponse, len(items))
+	for i, item := range items {
+		itemsResp[i] = trashItemResponse{
+			Type:         string(item.Type),
+			Id:           item.Id,
+			Name:         item.Name,
+			ComparisonId: item.ComparisonId,
+			DeletedAt:    item.DeletedAt,
+		}
+	}
+
+	response.SuccessListResponse(w, r, itemsResp, response.Pagination(pageInfo))
+}
+
+func getTrashFilter(params url.Values) (domain.TrashFilter, error) {
+	var limit int
+	var offset int
+
+	var err error
+
+	limitStr := params.Get("limit")
+	if limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return domain.TrashFilter{}, fmt.Errorf("incorrect limit value")
+		}
+	}
+
+	offsetStr := params.Get("offset")
+	if offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil {
+			return domain.TrashFilter{}, fmt.Errorf("incorrect offset value")
+		}
+	}
+
+	return domain.NewTrashFilter(limit, offset, params.Get("cursor"))
+}
@@ -0,0 +1,72 @@
+package photogc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+)
+
+type PhotoGCUsecase interface {
+	CollectOrphanedPhotos(ctx context.Context, dryRun bool) (domain.OrphanedPhotosReport, error)
+}
+
+// Job collects orphaned photos periodically in the background.
+type Job struct {
+	uc       PhotoGCUsecase
+	interval time.Duration
+	dryRun   bool
+	log      *slog.Logger
+}
+
+func NewJob(uc PhotoGCUsecase, interval time.Duration, dryRun bool, log *slog.Logger) *Job {
+	return &Job{
+		uc:       uc,
+		interval: interval,
+		dryRun:   dryRun,
+		log:      log,
+	}
+}
+
+// Run collects orphaned photos on start and then every interval until the context is done.
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce makes one pass of collecting orphaned photos, its result is logged and counted by metrics.
+func (j *Job) RunOnce(ctx context.Context) (domain.OrphanedPhotosReport, error) {
+	report, err := j.uc.CollectOrphanedPhotos(ctx, j.dryRun)
+	observeRun(report, err)
+
+	if err != nil {
+		j.log.Error("failed to collect orphaned photos", "detail", err, "deleted", report.Deleted)
+		return report, err
+	}
+
+	for _, photo := range report.Orphaned {
+		j.log.Debug("orphaned photo", "key", photo.Key, "size", photo.Size, "modified_at", photo.ModifiedAt)
+	}
+
+	j.log.Info(
+		"orphaned photos collected",
+		"dry_run", report.DryRun,
+		"scanned", report.Scanned,
+		"orphaned", len(report.Orphaned),
+		"deleted", report.Deleted,
+		"reclaimed_bytes", report.ReclaimedBytes,
+	)
+
+	return report, nil
+}
@@ -0,0 +1,56 @@
+package photogc
+
+import (
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var runsMetric = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "app",
+		Subsystem: "photo_gc",
+		Name:      "runs_total",
+		Help:      "Runs of orphaned photos collection by result",
+	},
+	[]string{"result"},
+)
+
+var orphanedPhotosMetric = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: "app",
+		Subsystem: "photo_gc",
+		Name:      "orphaned_photos_total",
+		Help:      "Orphaned photos found, including ones kept on dry run",
+	},
+)
+
+var deletedPhotosMetric = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: "app",
+		Subsystem: "photo_gc",
+		Name:      "deleted_photos_total",
+		Help:      "Orphaned photos deleted",
+	},
+)
+
+var reclaimedBytesMetric = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: "app",
+		Subsystem: "photo_gc",
+		Name:      "reclaimed_bytes_total",
+		Help:      "Bytes of the photo storage reclaimed by deleting orphaned photos",
+	},
+)
+
+func observeRun(report domain.OrphanedPhotosReport, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+
+	runsMetric.WithLabelValues(result).Inc()
+	orphanedPhotosMetric.Add(float64(len(report.Orphaned)))
+	deletedPhotosMetric.Add(float64(report.Deleted))
+	reclaimedBytesMetric.Add(float64(report.ReclaimedBytes))
+}
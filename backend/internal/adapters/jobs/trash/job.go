@@ -0,0 +1,76 @@
+package trash
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+)
+
+type TrashUsecase interface {
+	PurgeExpired(ctx context.Context) (domain.TrashPurgeReport, error)
+}
+
+// Job purges expired items of the trash periodically in the background.
+type Job struct {
+	uc       TrashUsecase
+	interval time.Duration
+	log      *slog.Logger
+}
+
+func NewJob(uc TrashUsecase, interval time.Duration, log *slog.Logger) *Job {
+	return &Job{
+		uc:       uc,
+		interval: interval,
+		log:      log,
+	}
+}
+
+// Run purges expired items on start and then every interval until the context is done.
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce makes one pass of purging expired items, its result is logged and counted by metrics.
+func (j *Job) RunOnce(ctx context.Context) (domain.TrashPurgeReport, error) {
+	report, err := j.uc.PurgeExpired(ctx)
+	observeRun(report, err)
+
+	if len(report.LeftoverPhotoKeys) != 0 {
+		j.log.Warn(
+			"photo files of purged items are not removed, left for the photo garbage collector",
+			"keys", report.LeftoverPhotoKeys,
+		)
+	}
+
+	if err != nil {
+		j.log.Error(
+			"failed to purge trash",
+			"detail", err,
+			"comparisons", report.Comparisons,
+			"objects", report.Objects,
+		)
+		return report, err
+	}
+
+	j.log.Info(
+		"trash purged",
+		"comparisons", report.Comparisons,
+		"objects", report.Objects,
+		"photos", report.Photos,
+	)
+
+	return report, nil
+}
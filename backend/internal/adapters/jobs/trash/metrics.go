@@ -0,0 +1,39 @@
+package trash
+
+import (
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var runsMetric = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "app",
+		Subsystem: "trash",
+		Name:      "purge_runs_total",
+		Help:      "Runs of expired trash purging by result",
+	},
+	[]string{"result"},
+)
+
+var purgedItemsMetric = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "app",
+		Subsystem: "trash",
+		Name:      "purged_items_total",
+		Help:      "Items removed from the trash for good by type",
+	},
+	[]string{"type"},
+)
+
+func observeRun(report domain.TrashPurgeReport, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+
+	runsMetric.WithLabelValues(result).Inc()
+	purgedItemsMetric.WithLabelValues("comparison").Add(float64(report.Comparisons))
+	purgedItemsMetric.WithLabelValues("object").Add(float64(report.Objects))
+	purgedItemsMetric.WithLabelValues("photo").Add(float64(report.Photos))
+}
@@ -0,0 +1,128 @@
+package photoprocessor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"golang.org/x/image/draw"
+)
+
+const jpegQuality = 85
+
+// maxSides limits the longest side of resized variants, the original keeps its dimensions.
+var maxSides = map[domain.PhotoSize]int{
+	domain.PhotoSizeThumb:  320,
+	domain.PhotoSizeMedium: 1280,
+}
+
+// ImageProcessor prepares uploaded JPEG and PNG photos for storing. Every variant,
+// including the original, is re-encoded from decoded pixels, so EXIF and other
+// metadata of the upload are never stored.
+type ImageProcessor struct {
+	maxPixels int64
+}
+
+func NewImageProcessor(maxPixels int64) *ImageProcessor {
+	return &ImageProcessor{maxPixels: maxPixels}
+}
+
+func (p *ImageProcessor) Process(ctx context.Context, content io.Reader) ([]domain.PhotoVariant, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, fmt.Errorf("read photo error: %w", err)
+	}
+
+	// dimensions are checked before decoding, so a small file declaring
+	// huge dimensions can not exhaust memory
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || (format != "jpeg" && format != "png") {
+		return nil, fmt.Errorf("photo must be a valid jpeg or png image - %w", domain.ErrInvalidValue)
+	}
+
+	if int64(cfg.Width)*int64(cfg.Height) > p.maxPixels {
+		return nil, fmt.Errorf(
+			"photo must not have more than %d pixels, got %dx%d - %w",
+			p.maxPixels, cfg.Width, cfg.Height, domain.ErrInvalidValue,
+		)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("photo must be a valid jpeg or png image - %w", domain.ErrInvalidValue)
+	}
+
+	if format == "jpeg" {
+		img = orient(img, exifOrientation(data))
+	}
+
+	variants := make([]domain.PhotoVariant, 0, len(domain.ProvidedPhotoSizes))
+	for _, size := range domain.ProvidedPhotoSizes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		resized := img
+		if maxSide, ok := maxSides[size]; ok {
+			resized = fit(img, maxSide)
+		}
+
+		variant, err := encode(resized, format)
+		if err != nil {
+			return nil, fmt.Errorf("encode %s photo error: %w", size, err)
+		}
+
+		variant.Size = size
+		variant.Width, variant.Height = resized.Bounds().Dx(), resized.Bounds().Dy()
+		variants = append(variants, variant)
+	}
+
+	return variants, nil
+}
+
+// fit scales the image down to have its longest side not greater than maxSide keeping
+// the aspect ratio. Images that already fit are not upscaled.
+func fit(img image.Image, maxSide int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width <= maxSide && height <= maxSide {
+		return img
+	}
+
+	if width >= height {
+		height = max(1, height*maxSide/width)
+		width = maxSide
+	} else {
+		width = max(1, width*maxSide/height)
+		height = maxSide
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+
+	return dst
+}
+
+func encode(img image.Image, format string) (domain.PhotoVariant, error) {
+	var buf bytes.Buffer
+
+	if format == "png" {
+		if err := png.Encode(&buf, img); err != nil {
+			return domain.PhotoVariant{}, err
+		}
+
+		return domain.PhotoVariant{Content: buf.Bytes(), ContentType: "image/png", Ext: ".png"}, nil
+	}
+
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return domain.PhotoVariant{}, err
+	}
+
+	return domain.PhotoVariant{Content: buf.Bytes(), ContentType: "image/jpeg", Ext: ".jpg"}, nil
+}
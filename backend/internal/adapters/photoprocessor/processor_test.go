@@ -0,0 +1,363 @@
+package photoprocessor
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// exifSegment gives APP1 segment with EXIF metadata holding only the orientation tag.
+func exifSegment(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 26)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], tagOrientation)
+	order.PutUint16(tiff[12:], typeShort)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+
+	return app1Segment(tiff)
+}
+
+func app1Segment(tiff []byte) []byte {
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+
+	segment := []byte{0xFF, markerApp1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+
+	return append(segment, payload...)
+}
+
+// withSegment inserts the segment right after the start of the JPEG image.
+func withSegment(jpegData, segment []byte) []byte {
+	data := append([]byte{}, jpegData[:2]...)
+	data = append(data, segment...)
+
+	return append(data, jpegData[2:]...)
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}))
+
+	return buf.Bytes()
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+
+	return buf.Bytes()
+}
+
+func solidImage(width, height int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+
+	return img
+}
+
+// hasSegment walks segments of the JPEG image up to the start of scan looking for the marker.
+func hasSegment(data []byte, marker byte) bool {
+	for i := 2; i+4 <= len(data); {
+		if data[i+1] == markerStartOfScan {
+			return false
+		}
+
+		if data[i+1] == marker {
+			return true
+		}
+
+		i += 2 + int(binary.BigEndian.Uint16(data[i+2:]))
+	}
+
+	return false
+}
+
+func TestExifOrientation(t *testing.T) {
+	jpegData := encodeJPEG(t, solidImage(8, 8))
+
+	for orientation := uint16(1); orientation <= 8; orientation++ {
+		for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+			data := withSegment(jpegData, exifSegment(order, orientation))
+			assert.Equal(t, int(orientation), exifOrientation(data), "orientation %d, %s", orientation, order)
+		}
+	}
+}
+
+func TestExifOrientationMalformed(t *testing.T) {
+	jpegData := encodeJPEG(t, solidImage(8, 8))
+	valid := exifSegment(binary.LittleEndian, 6)
+
+	wrongType := exifSegment(binary.LittleEndian, 6)
+	binary.LittleEndian.PutUint16(wrongType[4+6+12:], 4)
+
+	// orientation is looked for past the only entry
+	hugeCount := exifSegment(binary.LittleEndian, 6)
+	binary.LittleEndian.PutUint16(hugeCount[4+6+8:], 0xFFFF)
+	binary.LittleEndian.PutUint16(hugeCount[4+6+10:], 0x0100)
+
+	farOffset := exifSegment(binary.LittleEndian, 6)
+	binary.LittleEndian.PutUint32(farOffset[4+6+4:], 0xFFFFFFFF)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "Empty", data: []byte{}},
+		{name: "Not a JPEG", data: []byte("GIF89a")},
+		{name: "Only start of image", data: []byte{0xFF, 0xD8}},
+		{name: "No EXIF", data: jpegData},
+		{name: "Truncated segment", data: append([]byte{0xFF, 0xD8}, valid[:12]...)},
+		{name: "Segment length below minimum", data: []byte{0xFF, 0xD8, 0xFF, markerApp1, 0x00, 0x01, 0x00, 0x00}},
+		{name: "Segment length beyond data", data: []byte{0xFF, 0xD8, 0xFF, markerApp1, 0xFF, 0xFF, 'E', 'x'}},
+		{name: "Garbage instead of marker", data: []byte{0xFF, 0xD8, 0x12, 0x34, 0x56, 0x78}},
+		{name: "Short TIFF", data: withSegment(jpegData, app1Segment([]byte("II*")))},
+		{name: "Unknown byte order", data: withSegment(jpegData, app1Segment([]byte("XX*\x00\x08\x00\x00\x00\x00\x00")))},
+		{name: "IFD offset beyond data", data: withSegment(jpegData, farOffset)},
+		{name: "Entry count beyond data", data: withSegment(jpegData, hugeCount)},
+		{name: "Orientation of wrong type", data: withSegment(jpegData, wrongType)},
+		{name: "Orientation out of range", data: withSegment(jpegData, exifSegment(binary.BigEndian, 9))},
+		{name: "Zero orientation", data: withSegment(jpegData, exifSegment(binary.BigEndian, 0))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NotPanics(t, func() {
+				assert.Equal(t, 1, exifOrientation(tt.data))
+			})
+		})
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// pixels of the 3x2 source are told apart by their red channel:
+	//   a b c
+	//   d e f
+	const a, b, c, d, e, f = 10, 20, 30, 40, 50, 60
+
+	src := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	for i, v := range []uint8{a, b, c, d, e, f} {
+		src.SetNRGBA(i%3, i/3, color.NRGBA{R: v, A: 0xFF})
+	}
+
+	tests := []struct {
+		orientation int
+		want        [][]uint8
+	}{
+		{orientation: 1, want: [][]uint8{{a, b, c}, {d, e, f}}},
+		{orientation: 2, want: [][]uint8{{c, b, a}, {f, e, d}}},
+		{orientation: 3, want: [][]uint8{{f, e, d}, {c, b, a}}},
+		{orientation: 4, want: [][]uint8{{d, e, f}, {a, b, c}}},
+		{orientation: 5, want: [][]uint8{{a, d}, {b, e}, {c, f}}},
+		{orientation: 6, want: [][]uint8{{d, a}, {e, b}, {f, c}}},
+		{orientation: 7, want: [][]uint8{{f, c}, {e, b}, {d, a}}},
+		{orientation: 8, want: [][]uint8{{c, f}, {b, e}, {a, d}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(rune('0'+tt.orientation)), func(t *testing.T) {
+			img := orient(src, tt.orientation)
+
+			bounds := img.Bounds()
+			got := make([][]uint8, bounds.Dy())
+			for y := range got {
+				got[y] = make([]uint8, bounds.Dx())
+				for x := range got[y] {
+					got[y][x] = color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA).R
+				}
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProcess(t *testing.T) {
+	t.Run("Variant sizes", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			content     []byte
+			contentType string
+			ext         string
+			want        map[domain.PhotoSize][2]int
+		}{
+			{
+				name:        "Landscape PNG",
+				content:     encodePNG(t, solidImage(2000, 1000)),
+				contentType: "image/png",
+				ext:         ".png",
+				want: map[domain.PhotoSize][2]int{
+					domain.PhotoSizeThumb:    {320, 160},
+					domain.PhotoSizeMedium:   {1280, 640},
+					domain.PhotoSizeOriginal: {2000, 1000},
+				},
+			},
+			{
+				name:        "Portrait JPEG smaller than medium",
+				content:     encodeJPEG(t, solidImage(500, 1000)),
+				contentType: "image/jpeg",
+				ext:         ".jpg",
+				want: map[domain.PhotoSize][2]int{
+					domain.PhotoSizeThumb:    {160, 320},
+					domain.PhotoSizeMedium:   {500, 1000},
+					domain.PhotoSizeOriginal: {500, 1000},
+				},
+			},
+			{
+				name:        "Small image is not upscaled",
+				content:     encodePNG(t, solidImage(100, 30)),
+				contentType: "image/png",
+				ext:         ".png",
+				want: map[domain.PhotoSize][2]int{
+					domain.PhotoSizeThumb:    {100, 30},
+					domain.PhotoSizeMedium:   {100, 30},
+					domain.PhotoSizeOriginal: {100, 30},
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				p := NewImageProcessor(10_000_000)
+
+				variants, err := p.Process(context.Background(), bytes.NewReader(tt.content))
+				assert.NoError(t, err)
+				assert.Len(t, variants, len(domain.ProvidedPhotoSizes))
+
+				for _, variant := range variants {
+					want := tt.want[variant.Size]
+					assert.Equal(t, want[0], variant.Width, "width of %s", variant.Size)
+					assert.Equal(t, want[1], variant.Height, "height of %s", variant.Size)
+					assert.Equal(t, tt.contentType, variant.ContentType)
+					assert.Equal(t, tt.ext, variant.Ext)
+
+					cfg, _, err := image.DecodeConfig(bytes.NewReader(variant.Content))
+					assert.NoError(t, err)
+					assert.Equal(t, want, [2]int{cfg.Width, cfg.Height}, "encoded %s", variant.Size)
+				}
+			})
+		}
+	})
+
+	t.Run("Rotated upright by orientation", func(t *testing.T) {
+		for orientation := uint16(1); orientation <= 8; orientation++ {
+			p := NewImageProcessor(10_000_000)
+			content := withSegment(encodeJPEG(t, solidImage(400, 200)), exifSegment(binary.BigEndian, orientation))
+
+			variants, err := p.Process(context.Background(), bytes.NewReader(content))
+			assert.NoError(t, err)
+
+			original := variants[len(variants)-1]
+			assert.Equal(t, domain.PhotoSizeOriginal, original.Size)
+
+			if orientation >= 5 {
+				assert.Equal(t, [2]int{200, 400}, [2]int{original.Width, original.Height}, "orientation %d", orientation)
+			} else {
+				assert.Equal(t, [2]int{400, 200}, [2]int{original.Width, original.Height}, "orientation %d", orientation)
+			}
+		}
+	})
+
+	t.Run("Malformed EXIF is ignored", func(t *testing.T) {
+		p := NewImageProcessor(10_000_000)
+
+		segment := exifSegment(binary.LittleEndian, 6)
+		binary.LittleEndian.PutUint32(segment[4+6+4:], 0xFFFFFFFF)
+		content := withSegment(encodeJPEG(t, solidImage(400, 200)), segment)
+
+		variants, err := p.Process(context.Background(), bytes.NewReader(content))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, variants[len(variants)-1].Width)
+	})
+
+	t.Run("Metadata is not kept", func(t *testing.T) {
+		p := NewImageProcessor(10_000_000)
+
+		content := withSegment(encodeJPEG(t, solidImage(400, 200)), exifSegment(binary.LittleEndian, 3))
+		assert.True(t, hasSegment(content, markerApp1))
+
+		variants, err := p.Process(context.Background(), bytes.NewReader(content))
+		assert.NoError(t, err)
+
+		for _, variant := range variants {
+			assert.False(t, hasSegment(variant.Content, markerApp1), "APP1 segment in %s", variant.Size)
+			assert.False(t, bytes.Contains(variant.Content, []byte("Exif\x00\x00")), "EXIF in %s", variant.Size)
+		}
+	})
+
+	t.Run("Too many pixels are rejected before decoding", func(t *testing.T) {
+		p := NewImageProcessor(1_000_000)
+
+		// PNG header declaring 100000x100000 pixels without any image data
+		ihdr := make([]byte, 13)
+		binary.BigEndian.PutUint32(ihdr[0:], 100000)
+		binary.BigEndian.PutUint32(ihdr[4:], 100000)
+		ihdr[8], ihdr[9] = 8, 6
+
+		chunk := binary.BigEndian.AppendUint32(nil, uint32(len(ihdr)))
+		chunk = append(chunk, "IHDR"...)
+		chunk = append(chunk, ihdr...)
+		chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+
+		content := append([]byte("\x89PNG\r\n\x1a\n"), chunk...)
+
+		_, err := p.Process(context.Background(), bytes.NewReader(content))
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		assert.ErrorContains(t, err, "must not have more than 1000000 pixels, got 100000x100000")
+	})
+
+	t.Run("Limit of pixels is inclusive", func(t *testing.T) {
+		p := NewImageProcessor(200 * 100)
+
+		_, err := p.Process(context.Background(), bytes.NewReader(encodePNG(t, solidImage(200, 100))))
+		assert.NoError(t, err)
+
+		_, err = p.Process(context.Background(), bytes.NewReader(encodePNG(t, solidImage(201, 100))))
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+	})
+
+	t.Run("Unsupported or broken content", func(t *testing.T) {
+		var gifData bytes.Buffer
+		assert.NoError(t, gif.Encode(&gifData, solidImage(10, 10), nil))
+
+		jpegData := encodeJPEG(t, solidImage(100, 100))
+
+		tests := []struct {
+			name    string
+			content []byte
+		}{
+			{name: "Empty", content: []byte{}},
+			{name: "Text", content: []byte("not an image")},
+			{name: "GIF", content: gifData.Bytes()},
+			{name: "Truncated JPEG", content: jpegData[:len(jpegData)/2]},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				p := NewImageProcessor(10_000_000)
+
+				_, err := p.Process(context.Background(), bytes.NewReader(tt.content))
+				assert.ErrorIs(t, err, domain.ErrInvalidValue)
+			})
+		}
+	})
+}
@@ -0,0 +1,220 @@
+package photostore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+)
+
+// maxCachedETags bounds memory taken by the cache of photo hashes.
+const maxCachedETags = 10000
+
+// FilesystemPhotoStore keeps photos as files of a single directory. Only the base name
+// of a key is used, so keys saved as full paths by earlier versions keep working.
+type FilesystemPhotoStore struct {
+	dir string
+
+	mu    sync.Mutex
+	etags map[string]cachedETag
+}
+
+// cachedETag is the hash of the file content, valid while size and modification time of the file are the same.
+type cachedETag struct {
+	size       int64
+	modifiedAt time.Time
+	etag       string
+}
+
+func NewFilesystemPhotoStore(dir string) *FilesystemPhotoStore {
+	return &FilesystemPhotoStore{
+		dir:   dir,
+		etags: make(map[string]cachedETag),
+	}
+}
+
+func (s *FilesystemPhotoStore) Put(
+	ctx context.Context,
+	key string,
+	content io.Reader,
+	size int64,
+	contentType string,
+) error {
+	path := s.path(key)
+
+	// the photo is written to a temporary file renamed once complete, so a partly written
+	// photo is never seen under its key. Temporary files are hidden, so they are not listed.
+	file, err := os.CreateTemp(s.dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create photo file error: %w", err)
+	}
+
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return fmt.Errorf("write photo file error: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("close photo file error: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("rename photo file error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FilesystemPhotoStore) Get(ctx context.Context, key string) (io.ReadSeekCloser, domain.PhotoInfo, error) {
+	info, err := s.Stat(ctx, key)
+	if err != nil {
+		return nil, domain.PhotoInfo{}, err
+	}
+
+	file, err := os.Open(s.path(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, domain.PhotoInfo{}, fmt.Errorf("photo %w", domain.ErrNotFound)
+		}
+
+		return nil, domain.PhotoInfo{}, fmt.Errorf("open photo file error: %w", err)
+	}
+
+	return file, info, nil
+}
+
+func (s *FilesystemPhotoStore) Stat(ctx context.Context, key string) (domain.PhotoInfo, error) {
+	if key == "" {
+		return domain.PhotoInfo{}, fmt.Errorf("photo %w", domain.ErrNotFound)
+	}
+
+	fileInfo, err := os.Stat(s.path(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return domain.PhotoInfo{}, fmt.Errorf("photo %w", domain.ErrNotFound)
+		}
+
+		return domain.PhotoInfo{}, fmt.Errorf("stat photo file error: %w", err)
+	}
+
+	etag, err := s.etag(key, fileInfo)
+	if err != nil {
+		return domain.PhotoInfo{}, err
+	}
+
+	return domain.PhotoInfo{
+		Key:         key,
+		Size:        fileInfo.Size(),
+		ContentType: mime.TypeByExtension(strings.ToLower(filepath.Ext(key))),
+		ETag:        etag,
+		ModifiedAt:  fileInfo.ModTime(),
+	}, nil
+}
+
+// etag gives SHA-256 hash of the photo content. Photos are not changed after
+// they are put, so the hash is computed once and cached.
+func (s *FilesystemPhotoStore) etag(key string, fileInfo fs.FileInfo) (string, error) {
+	path := s.path(key)
+
+	s.mu.Lock()
+	cached, ok := s.etags[path]
+	s.mu.Unlock()
+
+	if ok && cached.size == fileInfo.Size() && cached.modifiedAt.Equal(fileInfo.ModTime()) {
+		return cached.etag, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open photo file error: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("hash photo file error: %w", err)
+	}
+
+	etag := hex.EncodeToString(hash.Sum(nil))
+
+	s.mu.Lock()
+	if len(s.etags) >= maxCachedETags {
+		clear(s.etags)
+	}
+	s.etags[path] = cachedETag{size: fileInfo.Size(), modifiedAt: fileInfo.ModTime(), etag: etag}
+	s.mu.Unlock()
+
+	return etag, nil
+}
+
+// List gives photos of the photos directory. ETag is not computed for listed photos.
+func (s *FilesystemPhotoStore) List(ctx context.Context) ([]domain.PhotoInfo, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read photos directory error: %w", err)
+	}
+
+	photos := make([]domain.PhotoInfo, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		fileInfo, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, fmt.Errorf("stat photo file error: %w", err)
+		}
+
+		photos = append(photos, domain.PhotoInfo{
+			Key:         entry.Name(),
+			Size:        fileInfo.Size(),
+			ContentType: mime.TypeByExtension(strings.ToLower(filepath.Ext(entry.Name()))),
+			ModifiedAt:  fileInfo.ModTime(),
+		})
+	}
+
+	return photos, nil
+}
+
+// Delete removes the photo from the photos directory. Photo that is already
+// missing is not considered an error.
+func (s *FilesystemPhotoStore) Delete(ctx context.Context, key string) error {
+	if key == "" {
+		return nil
+	}
+
+	path := s.path(key)
+
+	s.mu.Lock()
+	delete(s.etags, path)
+	s.mu.Unlock()
+
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("remove photo file error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FilesystemPhotoStore) path(key string) string {
+	return filepath.Join(s.dir, filepath.Base(key))
+}
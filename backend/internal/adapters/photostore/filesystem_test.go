@@ -0,0 +1,178 @@
+package photostore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func dirEntries(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name()
+	}
+
+	return names
+}
+
+func sha256Hex(content string) string {
+	hash := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestFilesystemPhotoStorePut(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		dir := t.TempDir()
+		store := NewFilesystemPhotoStore(dir)
+		ctx := context.Background()
+
+		err := store.Put(ctx, "photo.jpg", strings.NewReader("jpeg content"), 12, "image/jpeg")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"photo.jpg"}, dirEntries(t, dir))
+
+		file, info, err := store.Get(ctx, "photo.jpg")
+		assert.NoError(t, err)
+		defer file.Close()
+
+		content, err := io.ReadAll(file)
+		assert.NoError(t, err)
+		assert.Equal(t, "jpeg content", string(content))
+		assert.Equal(t, int64(12), info.Size)
+		assert.Equal(t, "image/jpeg", info.ContentType)
+		assert.Equal(t, sha256Hex("jpeg content"), info.ETag)
+	})
+
+	t.Run("Replace photo", func(t *testing.T) {
+		dir := t.TempDir()
+		store := NewFilesystemPhotoStore(dir)
+		ctx := context.Background()
+
+		assert.NoError(t, store.Put(ctx, "photo.jpg", strings.NewReader("old"), 3, "image/jpeg"))
+		_, err := store.Stat(ctx, "photo.jpg")
+		assert.NoError(t, err)
+
+		assert.NoError(t, store.Put(ctx, "photo.jpg", strings.NewReader("new content"), 11, "image/jpeg"))
+
+		info, err := store.Stat(ctx, "photo.jpg")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(11), info.Size)
+		assert.Equal(t, sha256Hex("new content"), info.ETag)
+		assert.Equal(t, []string{"photo.jpg"}, dirEntries(t, dir))
+	})
+
+	t.Run("Failed write keeps the previous photo", func(t *testing.T) {
+		dir := t.TempDir()
+		store := NewFilesystemPhotoStore(dir)
+		ctx := context.Background()
+
+		assert.NoError(t, store.Put(ctx, "photo.jpg", strings.NewReader("old"), 3, "image/jpeg"))
+
+		err := store.Put(ctx, "photo.jpg", io.MultiReader(strings.NewReader("partial"), failingReader{}), 20, "image/jpeg")
+		assert.Error(t, err)
+
+		content, err := os.ReadFile(filepath.Join(dir, "photo.jpg"))
+		assert.NoError(t, err)
+		assert.Equal(t, "old", string(content))
+		assert.Equal(t, []string{"photo.jpg"}, dirEntries(t, dir))
+	})
+
+	t.Run("Failed write of a new photo leaves nothing", func(t *testing.T) {
+		dir := t.TempDir()
+		store := NewFilesystemPhotoStore(dir)
+		ctx := context.Background()
+
+		err := store.Put(ctx, "photo.jpg", failingReader{}, 20, "image/jpeg")
+		assert.Error(t, err)
+		assert.Empty(t, dirEntries(t, dir))
+
+		_, err = store.Stat(ctx, "photo.jpg")
+		assert.True(t, errors.Is(err, domain.ErrNotFound))
+	})
+
+	t.Run("Missing directory", func(t *testing.T) {
+		store := NewFilesystemPhotoStore(filepath.Join(t.TempDir(), "missing"))
+
+		err := store.Put(context.Background(), "photo.jpg", strings.NewReader("jpeg content"), 12, "image/jpeg")
+		assert.Error(t, err)
+	})
+}
+
+func TestFilesystemPhotoStoreStat(t *testing.T) {
+	t.Run("Key saved as full path", func(t *testing.T) {
+		dir := t.TempDir()
+		store := NewFilesystemPhotoStore(dir)
+		ctx := context.Background()
+
+		assert.NoError(t, store.Put(ctx, "photo.png", strings.NewReader("png content"), 11, "image/png"))
+
+		info, err := store.Stat(ctx, "/old/photos/dir/photo.png")
+		assert.NoError(t, err)
+		assert.Equal(t, "image/png", info.ContentType)
+		assert.Equal(t, sha256Hex("png content"), info.ETag)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		store := NewFilesystemPhotoStore(t.TempDir())
+		ctx := context.Background()
+
+		_, err := store.Stat(ctx, "missing.jpg")
+		assert.True(t, errors.Is(err, domain.ErrNotFound))
+
+		_, err = store.Stat(ctx, "")
+		assert.True(t, errors.Is(err, domain.ErrNotFound))
+
+		_, _, err = store.Get(ctx, "missing.jpg")
+		assert.True(t, errors.Is(err, domain.ErrNotFound))
+	})
+}
+
+func TestFilesystemPhotoStoreList(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFilesystemPhotoStore(dir)
+	ctx := context.Background()
+
+	assert.NoError(t, store.Put(ctx, "a.jpg", strings.NewReader("a"), 1, "image/jpeg"))
+	assert.NoError(t, store.Put(ctx, "b.png", strings.NewReader("bb"), 2, "image/png"))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".c.jpg.123.tmp"), []byte("partial"), 0o600))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "nested"), 0o700))
+
+	photos, err := store.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, photos, 2)
+	assert.Equal(t, "a.jpg", photos[0].Key)
+	assert.Equal(t, int64(1), photos[0].Size)
+	assert.Equal(t, "b.png", photos[1].Key)
+	assert.Equal(t, "image/png", photos[1].ContentType)
+}
+
+func TestFilesystemPhotoStoreDelete(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFilesystemPhotoStore(dir)
+	ctx := context.Background()
+
+	assert.NoError(t, store.Put(ctx, "photo.jpg", strings.NewReader("jpeg content"), 12, "image/jpeg"))
+	assert.NoError(t, store.Delete(ctx, "photo.jpg"))
+	assert.Empty(t, dirEntries(t, dir))
+
+	// missing photos and empty keys are not errors
+	assert.NoError(t, store.Delete(ctx, "photo.jpg"))
+	assert.NoError(t, store.Delete(ctx, ""))
+}
@@ -0,0 +1,36 @@
+package photostore
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+)
+
+const (
+	TypeFilesystem = "filesystem"
+	TypeS3         = "s3"
+)
+
+// PhotoStore is any of photo stores, selected by the type of the storage.
+type PhotoStore interface {
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadSeekCloser, domain.PhotoInfo, error)
+	Stat(ctx context.Context, key string) (domain.PhotoInfo, error)
+	List(ctx context.Context) ([]domain.PhotoInfo, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewPhotoStore creates the photo store of the type, the directory is used by the
+// filesystem store and the config by the S3 one.
+func NewPhotoStore(ctx context.Context, storageType, dir string, s3Config S3Config) (PhotoStore, error) {
+	switch storageType {
+	case TypeFilesystem:
+		return NewFilesystemPhotoStore(dir), nil
+	case TypeS3:
+		return NewS3PhotoStore(ctx, s3Config)
+	default:
+		return nil, fmt.Errorf("unknown photo storage type %q", storageType)
+	}
+}
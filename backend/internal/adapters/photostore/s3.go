@@ -0,0 +1,222 @@
+package photostore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+type S3Config struct {
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string
+	UseSSL    bool
+}
+
+// sha256MetadataKey names the user metadata of an object holding SHA-256 hash of its content,
+// in the canonical form returned by the storage.
+const sha256MetadataKey = "Sha256"
+
+// S3PhotoStore keeps photos as objects of a bucket of S3 compatible storage, e.g. MinIO.
+// ETag of a photo is SHA-256 hash of the content, the same as of the filesystem store,
+// it is computed on put and kept as metadata of the object. Photos put by earlier
+// versions have no hash in metadata, ETag of their objects is used instead.
+type S3PhotoStore struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3PhotoStore connects to the storage and creates the bucket if it does not exist yet.
+func NewS3PhotoStore(ctx context.Context, cfg S3Config) (*S3PhotoStore, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create s3 client error: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("check s3 bucket error: %w", err)
+	}
+
+	if !exists {
+		err := client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{Region: cfg.Region})
+		if err != nil {
+			return nil, fmt.Errorf("create s3 bucket error: %w", err)
+		}
+	}
+
+	return &S3PhotoStore{client: client, bucket: cfg.Bucket}, nil
+}
+
+func (s *S3PhotoStore) Put(
+	ctx context.Context,
+	key string,
+	content io.Reader,
+	size int64,
+	contentType string,
+) error {
+	content, hash, err := hashContent(content)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutObject(ctx, s.bucket, objectName(key), content, size, minio.PutObjectOptions{
+		ContentType:  contentType,
+		UserMetadata: map[string]string{sha256MetadataKey: hash},
+	})
+	if err != nil {
+		return fmt.Errorf("put s3 object error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *S3PhotoStore) Get(ctx context.Context, key string) (io.ReadSeekCloser, domain.PhotoInfo, error) {
+	objInfo, err := s.stat(ctx, key)
+	if err != nil {
+		return nil, domain.PhotoInfo{}, err
+	}
+
+	// the photo is read lazily, so its content is pinned to the version described by the info
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetMatchETag(objInfo.ETag); err != nil {
+		return nil, domain.PhotoInfo{}, fmt.Errorf("set s3 object etag error: %w", err)
+	}
+
+	obj, err := s.client.GetObject(ctx, s.bucket, objectName(key), opts)
+	if err != nil {
+		return nil, domain.PhotoInfo{}, fmt.Errorf("get s3 object error: %w", err)
+	}
+
+	return obj, toPhotoInfo(key, objInfo), nil
+}
+
+func (s *S3PhotoStore) Stat(ctx context.Context, key string) (domain.PhotoInfo, error) {
+	objInfo, err := s.stat(ctx, key)
+	if err != nil {
+		return domain.PhotoInfo{}, err
+	}
+
+	return toPhotoInfo(key, objInfo), nil
+}
+
+func (s *S3PhotoStore) stat(ctx context.Context, key string) (minio.ObjectInfo, error) {
+	if key == "" {
+		return minio.ObjectInfo{}, fmt.Errorf("photo %w", domain.ErrNotFound)
+	}
+
+	objInfo, err := s.client.StatObject(ctx, s.bucket, objectName(key), minio.StatObjectOptions{})
+	if err != nil {
+		if isNotFound(err) {
+			return minio.ObjectInfo{}, fmt.Errorf("photo %w", domain.ErrNotFound)
+		}
+
+		return minio.ObjectInfo{}, fmt.Errorf("stat s3 object error: %w", err)
+	}
+
+	return objInfo, nil
+}
+
+// List gives photos of the bucket. ETag is not given for listed photos,
+// as metadata of objects is not listed.
+func (s *S3PhotoStore) List(ctx context.Context) ([]domain.PhotoInfo, error) {
+	photos := make([]domain.PhotoInfo, 0)
+	for objInfo := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Recursive: true}) {
+		if objInfo.Err != nil {
+			return nil, fmt.Errorf("list s3 objects error: %w", objInfo.Err)
+		}
+
+		photos = append(photos, domain.PhotoInfo{
+			Key:         objInfo.Key,
+			Size:        objInfo.Size,
+			ContentType: objInfo.ContentType,
+			ModifiedAt:  objInfo.LastModified,
+		})
+	}
+
+	return photos, nil
+}
+
+// Delete removes the photo from the bucket, photo that is already missing is not considered an error.
+func (s *S3PhotoStore) Delete(ctx context.Context, key string) error {
+	if key == "" {
+		return nil
+	}
+
+	err := s.client.RemoveObject(ctx, s.bucket, objectName(key), minio.RemoveObjectOptions{})
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("remove s3 object error: %w", err)
+	}
+
+	return nil
+}
+
+func toPhotoInfo(key string, objInfo minio.ObjectInfo) domain.PhotoInfo {
+	etag, ok := objInfo.UserMetadata[sha256MetadataKey]
+	if !ok {
+		etag = objInfo.ETag
+	}
+
+	return domain.PhotoInfo{
+		Key:         key,
+		Size:        objInfo.Size,
+		ContentType: objInfo.ContentType,
+		ETag:        etag,
+		ModifiedAt:  objInfo.LastModified,
+	}
+}
+
+// hashContent gives SHA-256 hash of the content and the content to read again. Seekable
+// content is rewound, any other is read into memory, photos are limited in size anyway.
+func hashContent(content io.Reader) (io.Reader, string, error) {
+	hash := sha256.New()
+
+	if seeker, ok := content.(io.ReadSeeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, "", fmt.Errorf("seek photo error: %w", err)
+		}
+
+		if _, err := io.Copy(hash, seeker); err != nil {
+			return nil, "", fmt.Errorf("hash photo error: %w", err)
+		}
+
+		if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+			return nil, "", fmt.Errorf("rewind photo error: %w", err)
+		}
+
+		return seeker, hex.EncodeToString(hash.Sum(nil)), nil
+	}
+
+	buf, err := io.ReadAll(io.TeeReader(content, hash))
+	if err != nil {
+		return nil, "", fmt.Errorf("read photo error: %w", err)
+	}
+
+	return bytes.NewReader(buf), hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// objectName uses the base name of a key the same way as the filesystem store does,
+// so photos copied from the photos directory to the bucket are found by their old keys.
+func objectName(key string) string {
+	return path.Base(key)
+}
+
+func isNotFound(err error) bool {
+	code := minio.ToErrorResponse(err).Code
+	return code == "NoSuchKey" || code == "NoSuchObject"
+}
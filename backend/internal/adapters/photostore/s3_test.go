@@ -0,0 +1,170 @@
+package photostore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestS3PhotoStore connects to the storage given by S3_* environment variables, e.g. a local MinIO,
+// and creates a bucket removed after the test. Tests are skipped when S3_ENDPOINT is not set.
+func newTestS3PhotoStore(t *testing.T) *S3PhotoStore {
+	endpoint := os.Getenv("S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_ENDPOINT is not set")
+	}
+
+	ctx := context.Background()
+
+	store, err := NewS3PhotoStore(ctx, S3Config{
+		Endpoint:  endpoint,
+		AccessKey: os.Getenv("S3_ACCESS_KEY"),
+		SecretKey: os.Getenv("S3_SECRET_KEY"),
+		Bucket:    fmt.Sprintf("photostore-test-%d", time.Now().UnixNano()),
+		Region:    os.Getenv("S3_REGION"),
+		UseSSL:    os.Getenv("S3_USE_SSL") == "true",
+	})
+	if err != nil {
+		t.Fatalf("connect to s3: %v", err)
+	}
+
+	t.Cleanup(func() {
+		for objInfo := range store.client.ListObjects(ctx, store.bucket, minio.ListObjectsOptions{Recursive: true}) {
+			if objInfo.Err == nil {
+				_ = store.client.RemoveObject(ctx, store.bucket, objInfo.Key, minio.RemoveObjectOptions{})
+			}
+		}
+
+		_ = store.client.RemoveBucket(ctx, store.bucket)
+	})
+
+	return store
+}
+
+func TestS3PhotoStorePut(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		store := newTestS3PhotoStore(t)
+		ctx := context.Background()
+
+		err := store.Put(ctx, "photo.jpg", strings.NewReader("jpeg content"), 12, "image/jpeg")
+		assert.NoError(t, err)
+
+		file, info, err := store.Get(ctx, "photo.jpg")
+		assert.NoError(t, err)
+		defer file.Close()
+
+		content, err := io.ReadAll(file)
+		assert.NoError(t, err)
+		assert.Equal(t, "jpeg content", string(content))
+		assert.Equal(t, int64(12), info.Size)
+		assert.Equal(t, "image/jpeg", info.ContentType)
+		assert.Equal(t, sha256Hex("jpeg content"), info.ETag)
+	})
+
+	t.Run("Not seekable content", func(t *testing.T) {
+		store := newTestS3PhotoStore(t)
+		ctx := context.Background()
+
+		err := store.Put(ctx, "photo.png", io.MultiReader(strings.NewReader("png "), strings.NewReader("content")), 11, "image/png")
+		assert.NoError(t, err)
+
+		info, err := store.Stat(ctx, "photo.png")
+		assert.NoError(t, err)
+		assert.Equal(t, sha256Hex("png content"), info.ETag)
+	})
+
+	t.Run("Replace photo", func(t *testing.T) {
+		store := newTestS3PhotoStore(t)
+		ctx := context.Background()
+
+		assert.NoError(t, store.Put(ctx, "photo.jpg", strings.NewReader("old"), 3, "image/jpeg"))
+		assert.NoError(t, store.Put(ctx, "photo.jpg", strings.NewReader("new content"), 11, "image/jpeg"))
+
+		info, err := store.Stat(ctx, "photo.jpg")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(11), info.Size)
+		assert.Equal(t, sha256Hex("new content"), info.ETag)
+	})
+}
+
+func TestS3PhotoStoreStat(t *testing.T) {
+	t.Run("Key saved as full path", func(t *testing.T) {
+		store := newTestS3PhotoStore(t)
+		ctx := context.Background()
+
+		assert.NoError(t, store.Put(ctx, "photo.png", strings.NewReader("png content"), 11, "image/png"))
+
+		info, err := store.Stat(ctx, "/old/photos/dir/photo.png")
+		assert.NoError(t, err)
+		assert.Equal(t, "image/png", info.ContentType)
+		assert.Equal(t, sha256Hex("png content"), info.ETag)
+	})
+
+	t.Run("Photo put by earlier version", func(t *testing.T) {
+		store := newTestS3PhotoStore(t)
+		ctx := context.Background()
+
+		uploadInfo, err := store.client.PutObject(ctx, store.bucket, "photo.jpg", strings.NewReader("jpeg content"), 12, minio.PutObjectOptions{
+			ContentType: "image/jpeg",
+		})
+		assert.NoError(t, err)
+
+		info, err := store.Stat(ctx, "photo.jpg")
+		assert.NoError(t, err)
+		assert.Equal(t, uploadInfo.ETag, info.ETag)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		store := newTestS3PhotoStore(t)
+		ctx := context.Background()
+
+		_, err := store.Stat(ctx, "missing.jpg")
+		assert.True(t, errors.Is(err, domain.ErrNotFound))
+
+		_, err = store.Stat(ctx, "")
+		assert.True(t, errors.Is(err, domain.ErrNotFound))
+
+		_, _, err = store.Get(ctx, "missing.jpg")
+		assert.True(t, errors.Is(err, domain.ErrNotFound))
+	})
+}
+
+func TestS3PhotoStoreList(t *testing.T) {
+	store := newTestS3PhotoStore(t)
+	ctx := context.Background()
+
+	assert.NoError(t, store.Put(ctx, "a.jpg", strings.NewReader("a"), 1, "image/jpeg"))
+	assert.NoError(t, store.Put(ctx, "b.png", strings.NewReader("bb"), 2, "image/png"))
+
+	photos, err := store.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, photos, 2)
+	assert.Equal(t, "a.jpg", photos[0].Key)
+	assert.Equal(t, int64(1), photos[0].Size)
+	assert.Equal(t, "b.png", photos[1].Key)
+	assert.Equal(t, int64(2), photos[1].Size)
+}
+
+func TestS3PhotoStoreDelete(t *testing.T) {
+	store := newTestS3PhotoStore(t)
+	ctx := context.Background()
+
+	assert.NoError(t, store.Put(ctx, "photo.jpg", strings.NewReader("jpeg content"), 12, "image/jpeg"))
+	assert.NoError(t, store.Delete(ctx, "photo.jpg"))
+
+	_, err := store.Stat(ctx, "photo.jpg")
+	assert.True(t, errors.Is(err, domain.ErrNotFound))
+
+	// missing photos and empty keys are not errors
+	assert.NoError(t, store.Delete(ctx, "photo.jpg"))
+	assert.NoError(t, store.Delete(ctx, ""))
+}
@@ -0,0 +1,140 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/adapters/repositories/pagination"
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AuditRepositoryMongo struct {
+	auditColl *mongo.Collection
+}
+
+type auditEventMongo struct {
+	Id        string             `bson:"_id"`
+	Entity    string             `bson:"entity"`
+	EntityId  string             `bson:"entity_id"`
+	Action    string             `bson:"action"`
+	CreatedAt time.Time          `bson:"created_at"`
+	Changes   []fieldChangeMongo `bson:"changes"`
+}
+
+type fieldChangeMongo struct {
+	Field  string `bson:"field"`
+	Before any    `bson:"before"`
+	After  any    `bson:"after"`
+}
+
+// historySortFields orders the history from the latest changes.
+var historySortFields = pagination.SortFields([]domain.SortKey{{Field: "created_at", Desc: true}})
+
+func NewAuditRepositoryMongo(client *mongo.Client) *AuditRepositoryMongo {
+	return &AuditRepositoryMongo{
+		auditColl: client.Database("database").Collection("audit"),
+	}
+}
+
+func (repo *AuditRepositoryMongo) AddAuditEvent(ctx context.Context, event domain.AuditEvent) error {
+	if _, err := repo.auditColl.InsertOne(ctx, toAuditEventMongo(event)); err != nil {
+		return fmt.Errorf("insert to mongo error: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *AuditRepositoryMongo) GetAuditEvents(
+	ctx context.Context,
+	filter domain.AuditFilter,
+) ([]domain.AuditEvent, domain.PageInfo, error) {
+	cursor, err := pagination.DecodeCursor(filter.Cursor, historySortFields)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+
+	condition := bson.M{
+		"entity":    string(filter.Entity),
+		"entity_id": filter.EntityId,
+	}
+
+	total, err := repo.auditColl.CountDocuments(ctx, condition)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("count audit events at mongo error: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(pagination.Sort(historySortFields, cursor)).
+		SetLimit(int64(filter.Limit + 1))
+
+	if cursor != nil {
+		condition = bson.M{"$and": bson.A{condition, pagination.Condition(historySortFields, cursor)}}
+	} else {
+		opts.SetSkip(int64(filter.Offset))
+	}
+
+	cur, err := repo.auditColl.Find(ctx, condition, opts)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("fetch audit events from mongo error: %w", err)
+	}
+
+	var docs []bson.Raw
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("fetch audit events from mongo error: %w", err)
+	}
+
+	docs, pageInfo, err := pagination.Page(docs, historySortFields, filter.Limit, filter.Offset, cursor)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+
+	pageInfo.Total = total
+
+	events := make([]domain.AuditEvent, 0, len(docs))
+	for _, doc := range docs {
+		var em auditEventMongo
+		if err := bson.Unmarshal(doc, &em); err != nil {
+			return nil, domain.PageInfo{}, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		events = append(events, toDomainAuditEvent(em))
+	}
+
+	return events, pageInfo, nil
+}
+
+func toAuditEventMongo(event domain.AuditEvent) auditEventMongo {
+	changes := make([]fieldChangeMongo, len(event.Changes))
+	for i, c := range event.Changes {
+		changes[i] = fieldChangeMongo{Field: c.Field, Before: c.Before, After: c.After}
+	}
+
+	return auditEventMongo{
+		Id:        event.Id,
+		Entity:    string(event.Entity),
+		EntityId:  event.EntityId,
+		Action:    string(event.Action),
+		CreatedAt: event.CreatedAt,
+		Changes:   changes,
+	}
+}
+
+func toDomainAuditEvent(em auditEventMongo) domain.AuditEvent {
+	changes := make([]domain.FieldChange, len(em.Changes))
+	for i, c := range em.Changes {
+		changes[i] = domain.FieldChange{Field: c.Field, Before: c.Before, After: c.After}
+	}
+
+	return domain.AuditEvent{
+		Id:        em.Id,
+		Entity:    domain.AuditEntity(em.Entity),
+		EntityId:  em.EntityId,
+		Action:    domain.AuditAction(em.Action),
+		CreatedAt: em.CreatedAt,
+		Changes:   changes,
+	}
+}
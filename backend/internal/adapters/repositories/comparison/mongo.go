@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"time"
 
+	"github.com/Unlites/comparison_center/backend/internal/adapters/repositories/pagination"
 	"github.com/Unlites/comparison_center/backend/internal/domain"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -17,10 +19,20 @@ type ComparisonRepositoryMongo struct {
 }
 
 type comparisonMongo struct {
-	Id              string    `bson:"_id"`
-	Name            string    `bson:"name"`
-	CreatedAt       time.Time `bson:"created_at"`
-	CustomOptionIds []string  `bson:"custom_option_ids"`
+	Id              string              `bson:"_id"`
+	Name            string              `bson:"name"`
+	CreatedAt       time.Time           `bson:"created_at"`
+	CustomOptionIds []string            `bson:"custom_option_ids"`
+	OptionWeights   []optionWeightMongo `bson:"option_weights"`
+	Tags            []string            `bson:"tags"`
+	FolderId        string              `bson:"folder_id"`
+	DeletedAt       *time.Time          `bson:"deleted_at"`
+}
+
+type optionWeightMongo struct {
+	CustomOptionId string  `bson:"custom_option_id"`
+	Weight         float64 `bson:"weight"`
+	Direction      string  `bson:"direction"`
 }
 
 func NewComparisonRepositoryMongo(client *mongo.Client) *ComparisonRepositoryMongo {
@@ -32,18 +44,152 @@ func NewComparisonRepositoryMongo(client *mongo.Client) *ComparisonRepositoryMon
 func (repo *ComparisonRepositoryMongo) GetComparisons(
 	ctx context.Context,
 	filter domain.ComparisonFilter,
-) ([]domain.Comparison, error) {
+) ([]domain.Comparison, domain.PageInfo, error) {
+	sortFields := pagination.SortFields(filter.OrderBy)
+
+	cursor, err := pagination.DecodeCursor(filter.Cursor, sortFields)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+
+	condition := bson.M{"deleted_at": nil}
+
+	if filter.Name != "" {
+		condition["name"] = bson.M{
+			"$regex":   regexp.QuoteMeta(filter.Name),
+			"$options": "i",
+		}
+	}
+
+	if !filter.CreatedFrom.IsZero() || !filter.CreatedTo.IsZero() {
+		createdAt := bson.M{}
+		if !filter.CreatedFrom.IsZero() {
+			createdAt["$gte"] = filter.CreatedFrom
+		}
+
+		if !filter.CreatedTo.IsZero() {
+			createdAt["$lte"] = filter.CreatedTo
+		}
+
+		condition["created_at"] = createdAt
+	}
+
+	if filter.CustomOptionId != "" {
+		condition["custom_option_ids"] = filter.CustomOptionId
+	}
+
+	if filter.Tag != "" {
+		condition["tags"] = filter.Tag
+	}
+
+	if filter.FolderIds != nil {
+		condition["folder_id"] = bson.M{"$in": filter.FolderIds}
+	}
+
+	total, err := repo.comparisonsColl.CountDocuments(ctx, condition)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("count comparisons at mongo error: %w", err)
+	}
+
 	opts := options.Find().
-		SetSort(bson.M{filter.OrderBy: 1}).
-		SetSkip(int64(filter.Offset)).
-		SetLimit(int64(filter.Limit))
+		SetSort(pagination.Sort(sortFields, cursor)).
+		SetLimit(int64(filter.Limit + 1))
+
+	if cursor != nil {
+		condition = bson.M{"$and": bson.A{condition, pagination.Condition(sortFields, cursor)}}
+	} else {
+		opts.SetSkip(int64(filter.Offset))
+	}
+
+	cur, err := repo.comparisonsColl.Find(ctx, condition, opts)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("fetch comparisons from mongo error: %w", err)
+	}
+
+	var docs []bson.Raw
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("fetch comparisons from mongo error: %w", err)
+	}
+
+	docs, pageInfo, err := pagination.Page(docs, sortFields, filter.Limit, filter.Offset, cursor)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+
+	pageInfo.Total = total
+
+	comparisons := make([]domain.Comparison, 0, len(docs))
+	for _, doc := range docs {
+		var cm comparisonMongo
+		if err := bson.Unmarshal(doc, &cm); err != nil {
+			return nil, domain.PageInfo{}, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		comparisons = append(comparisons, toDomainComparison(cm))
+	}
+
+	return comparisons, pageInfo, nil
+}
+
+// GetComparisonsByCustomOptionId returns comparisons using the custom option, including
+// the ones in the trash, as they get the option back when restored.
+func (repo *ComparisonRepositoryMongo) GetComparisonsByCustomOptionId(
+	ctx context.Context,
+	customOptionId string,
+) ([]domain.Comparison, error) {
+	cur, err := repo.comparisonsColl.Find(ctx, bson.M{"custom_option_ids": customOptionId})
+	if err != nil {
+		return nil, fmt.Errorf("fetch comparisons from mongo error: %w", err)
+	}
+
+	comparisons := make([]domain.Comparison, 0)
+	for cur.Next(ctx) {
+		var cm comparisonMongo
+		if err := cur.Decode(&cm); err != nil {
+			return nil, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		comparisons = append(comparisons, toDomainComparison(cm))
+	}
+
+	return comparisons, nil
+}
+
+// GetComparisonsByFolderId returns comparisons kept right in the folder, not in its subfolders.
+// Comparisons in the trash are not returned, they are restored to the root if their folder is gone.
+func (repo *ComparisonRepositoryMongo) GetComparisonsByFolderId(
+	ctx context.Context,
+	folderId string,
+) ([]domain.Comparison, error) {
+	cur, err := repo.comparisonsColl.Find(ctx, bson.M{"folder_id": folderId, "deleted_at": nil})
+	if err != nil {
+		return nil, fmt.Errorf("fetch comparisons from mongo error: %w", err)
+	}
 
-	cur, err := repo.comparisonsColl.Find(ctx, bson.M{}, opts)
+	comparisons := make([]domain.Comparison, 0)
+	for cur.Next(ctx) {
+		var cm comparisonMongo
+		if err := cur.Decode(&cm); err != nil {
+			return nil, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		comparisons = append(comparisons, toDomainComparison(cm))
+	}
+
+	return comparisons, nil
+}
+
+// GetComparisonsByIds returns comparisons out of the trash with the given ids, missing ones are skipped.
+func (repo *ComparisonRepositoryMongo) GetComparisonsByIds(
+	ctx context.Context,
+	ids []string,
+) ([]domain.Comparison, error) {
+	cur, err := repo.comparisonsColl.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": nil})
 	if err != nil {
 		return nil, fmt.Errorf("fetch comparisons from mongo error: %w", err)
 	}
 
-	comparisons := make([]domain.Comparison, 0, filter.Limit)
+	comparisons := make([]domain.Comparison, 0)
 	for cur.Next(ctx) {
 		var cm comparisonMongo
 		if err := cur.Decode(&cm); err != nil {
@@ -56,11 +202,42 @@ func (repo *ComparisonRepositoryMongo) GetComparisons(
 	return comparisons, nil
 }
 
+type tagUsageMongo struct {
+	Name  string `bson:"_id"`
+	Count int    `bson:"count"`
+}
+
+// GetTagUsages returns tags of comparisons out of the trash with the number of comparisons
+// tagged with each of them, most used tags go first.
+func (repo *ComparisonRepositoryMongo) GetTagUsages(ctx context.Context) ([]domain.TagUsage, error) {
+	cur, err := repo.comparisonsColl.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"deleted_at": nil}}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("aggregate tags at mongo error: %w", err)
+	}
+
+	tags := make([]domain.TagUsage, 0)
+	for cur.Next(ctx) {
+		var tm tagUsageMongo
+		if err := cur.Decode(&tm); err != nil {
+			return nil, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		tags = append(tags, domain.TagUsage{Name: tm.Name, Count: tm.Count})
+	}
+
+	return tags, nil
+}
+
 func (repo *ComparisonRepositoryMongo) GetComparisonById(
 	ctx context.Context,
 	id string,
 ) (domain.Comparison, error) {
-	res := repo.comparisonsColl.FindOne(ctx, bson.M{"_id": id})
+	res := repo.comparisonsColl.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil})
 	if res.Err() != nil {
 		if errors.Is(res.Err(), mongo.ErrNoDocuments) {
 			return domain.Comparison{}, fmt.Errorf("comparison %w", domain.ErrNotFound)
@@ -83,7 +260,7 @@ func (repo *ComparisonRepositoryMongo) UpdateComparison(
 ) error {
 	res, err := repo.comparisonsColl.UpdateOne(
 		ctx,
-		bson.M{"_id": comparison.Id},
+		bson.M{"_id": comparison.Id, "deleted_at": nil},
 		bson.M{"$set": toComparisonMongo(comparison)},
 	)
 	if err != nil {
@@ -125,36 +302,233 @@ func (repo *ComparisonRepositoryMongo) CreateComparison(
 	return nil
 }
 
+// DeleteComparison moves the comparison to the trash.
 func (repo *ComparisonRepositoryMongo) DeleteComparison(
 	ctx context.Context,
 	id string,
+	deletedAt time.Time,
+) error {
+	res, err := repo.comparisonsColl.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": deletedAt}},
+	)
+	if err != nil {
+		return fmt.Errorf("update at mongo error: %w", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("comparison %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+// GetDeletedComparisons returns comparisons in the trash deleted before the given time,
+// all of them when it is zero. Recently deleted comparisons go first.
+func (repo *ComparisonRepositoryMongo) GetDeletedComparisons(
+	ctx context.Context,
+	deletedBefore time.Time,
+) ([]domain.Comparison, error) {
+	deletedAt := bson.M{"$ne": nil}
+	if !deletedBefore.IsZero() {
+		deletedAt["$lt"] = deletedBefore
+	}
+
+	cur, err := repo.comparisonsColl.Find(
+		ctx,
+		bson.M{"deleted_at": deletedAt},
+		options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetch comparisons from mongo error: %w", err)
+	}
+
+	comparisons := make([]domain.Comparison, 0)
+	for cur.Next(ctx) {
+		var cm comparisonMongo
+		if err := cur.Decode(&cm); err != nil {
+			return nil, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		comparisons = append(comparisons, toDomainComparison(cm))
+	}
+
+	return comparisons, nil
+}
+
+func (repo *ComparisonRepositoryMongo) GetDeletedComparisonById(
+	ctx context.Context,
+	id string,
+) (domain.Comparison, error) {
+	res := repo.comparisonsColl.FindOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}})
+	if res.Err() != nil {
+		if errors.Is(res.Err(), mongo.ErrNoDocuments) {
+			return domain.Comparison{}, fmt.Errorf("deleted comparison %w", domain.ErrNotFound)
+		}
+
+		return domain.Comparison{}, fmt.Errorf("get comparison from mongo error %w", res.Err())
+	}
+
+	var cm comparisonMongo
+	if err := res.Decode(&cm); err != nil {
+		return domain.Comparison{}, fmt.Errorf("decode mongo result error %w", err)
+	}
+
+	return toDomainComparison(cm), nil
+}
+
+// RestoreComparison takes the comparison out of the trash into its folder. It fails with
+// ErrAlreadyExists when its name has been taken by another comparison in the meantime.
+func (repo *ComparisonRepositoryMongo) RestoreComparison(
+	ctx context.Context,
+	comparison domain.Comparison,
+) error {
+	res, err := repo.comparisonsColl.UpdateOne(
+		ctx,
+		bson.M{"_id": comparison.Id, "deleted_at": bson.M{"$ne": nil}},
+		bson.M{"$set": bson.M{"deleted_at": nil, "folder_id": comparison.FolderId}},
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf(
+				"comparison with name '%s' %w",
+				comparison.Name,
+				domain.ErrAlreadyExists,
+			)
+		}
+
+		return fmt.Errorf("update at mongo error: %w", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("deleted comparison %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+// PurgeComparison removes the comparison from the trash for good.
+func (repo *ComparisonRepositoryMongo) PurgeComparison(
+	ctx context.Context,
+	id string,
 ) error {
-	res, err := repo.comparisonsColl.DeleteOne(ctx, bson.M{"_id": id})
+	res, err := repo.comparisonsColl.DeleteOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}})
 	if err != nil {
 		return fmt.Errorf("delete from mongo error: %w", err)
 	}
 
 	if res.DeletedCount == 0 {
-		return fmt.Errorf("comparison %w", domain.ErrNotFound)
+		return fmt.Errorf("deleted comparison %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+// GetAllComparisons returns all comparisons of the database except the ones
+// in the trash, e.g. to back them up.
+func (repo *ComparisonRepositoryMongo) GetAllComparisons(ctx context.Context) ([]domain.Comparison, error) {
+	cur, err := repo.comparisonsColl.Find(ctx, bson.M{"deleted_at": nil})
+	if err != nil {
+		return nil, fmt.Errorf("fetch comparisons from mongo error: %w", err)
+	}
+
+	comparisons := make([]domain.Comparison, 0)
+	for cur.Next(ctx) {
+		var cm comparisonMongo
+		if err := cur.Decode(&cm); err != nil {
+			return nil, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		comparisons = append(comparisons, toDomainComparison(cm))
+	}
+
+	return comparisons, nil
+}
+
+// CreateComparisons inserts comparisons in one batch, e.g. to restore them from a backup.
+func (repo *ComparisonRepositoryMongo) CreateComparisons(ctx context.Context, comparisons []domain.Comparison) error {
+	if len(comparisons) == 0 {
+		return nil
+	}
+
+	documents := make([]any, len(comparisons))
+	for i, comparison := range comparisons {
+		documents[i] = toComparisonMongo(comparison)
+	}
+
+	if _, err := repo.comparisonsColl.InsertMany(ctx, documents); err != nil {
+		return fmt.Errorf("insert to mongo error: %w", err)
 	}
 
 	return nil
 }
 
 func toComparisonMongo(domainComparison domain.Comparison) comparisonMongo {
+	optionWeights := make([]optionWeightMongo, len(domainComparison.OptionWeights))
+	for i, ow := range domainComparison.OptionWeights {
+		optionWeights[i] = optionWeightMongo{
+			CustomOptionId: ow.CustomOptionId,
+			Weight:         ow.Weight,
+			Direction:      string(ow.Direction),
+		}
+	}
+
 	return comparisonMongo{
 		Id:              domainComparison.Id,
 		Name:            domainComparison.Name,
 		CreatedAt:       domainComparison.CreatedAt,
 		CustomOptionIds: domainComparison.CustomOptionIds,
+		OptionWeights:   optionWeights,
+		Tags:            tagsMongo(domainComparison.Tags),
+		FolderId:        domainComparison.FolderId,
+		DeletedAt:       deletedAtMongo(domainComparison.DeletedAt),
 	}
 }
 
+// tagsMongo keeps an empty array for the comparison without tags, so the tags
+// are cleared by an update.
+func tagsMongo(tags []string) []string {
+	if tags == nil {
+		return make([]string, 0)
+	}
+
+	return tags
+}
+
+// deletedAtMongo keeps null deletion time of the comparison that is not in the trash,
+// so that it is matched by the unique name index.
+func deletedAtMongo(deletedAt time.Time) *time.Time {
+	if deletedAt.IsZero() {
+		return nil
+	}
+
+	return &deletedAt
+}
+
 func toDomainComparison(cm comparisonMongo) domain.Comparison {
+	optionWeights := make([]domain.OptionWeight, len(cm.OptionWeights))
+	for i, owm := range cm.OptionWeights {
+		optionWeights[i] = domain.OptionWeight{
+			CustomOptionId: owm.CustomOptionId,
+			Weight:         owm.Weight,
+			Direction:      domain.ScoreDirection(owm.Direction),
+		}
+	}
+
+	var deletedAt time.Time
+	if cm.DeletedAt != nil {
+		deletedAt = *cm.DeletedAt
+	}
+
 	return domain.Comparison{
 		Id:              cm.Id,
 		Name:            cm.Name,
 		CreatedAt:       cm.CreatedAt,
 		CustomOptionIds: cm.CustomOptionIds,
+		OptionWeights:   optionWeights,
+		Tags:            cm.Tags,
+		FolderId:        cm.FolderId,
+		DeletedAt:       deletedAt,
 	}
 }
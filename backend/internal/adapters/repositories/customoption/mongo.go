@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 
+	"github.com/Unlites/comparison_center/backend/internal/adapters/repositories/pagination"
 	"github.com/Unlites/comparison_center/backend/internal/domain"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -16,8 +18,11 @@ type CustomOptionRepositoryMongo struct {
 }
 
 type customOptionMongo struct {
-	Id   string `bson:"_id"`
-	Name string `bson:"name"`
+	Id         string   `bson:"_id"`
+	Name       string   `bson:"name"`
+	Type       string   `bson:"type"`
+	EnumValues []string `bson:"enum_values,omitempty"`
+	Currency   string   `bson:"currency,omitempty"`
 }
 
 func NewCustomOptionRepositoryMongo(client *mongo.Client) *CustomOptionRepositoryMongo {
@@ -67,26 +72,38 @@ func (repo *CustomOptionRepositoryMongo) GetCustomOptionById(
 	return toDomainCustomOption(com), nil
 }
 
-func (repo *CustomOptionRepositoryMongo) GetCustomOptions(
+func (repo *CustomOptionRepositoryMongo) GetCustomOptionsByIds(
 	ctx context.Context,
-	filter domain.CustomOptionFilter,
+	ids []string,
 ) ([]domain.CustomOption, error) {
-	opts := options.Find().
-		SetSkip(int64(filter.Offset)).
-		SetLimit(int64(filter.Limit))
+	cur, err := repo.customOptionsColl.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, fmt.Errorf("fetch custom options from mongo error: %w", err)
+	}
 
-	condition := bson.M{}
+	customOptions := make([]domain.CustomOption, 0, len(ids))
+	for cur.Next(ctx) {
+		var com customOptionMongo
+		if err := cur.Decode(&com); err != nil {
+			return nil, fmt.Errorf("decode mongo result error %w", err)
+		}
 
-	if filter.Name != "" {
-		condition["name"] = bson.M{"$regex": filter.Name}
+		customOptions = append(customOptions, toDomainCustomOption(com))
 	}
 
-	cur, err := repo.customOptionsColl.Find(ctx, condition, opts)
+	return customOptions, nil
+}
+
+func (repo *CustomOptionRepositoryMongo) GetCustomOptionsByNames(
+	ctx context.Context,
+	names []string,
+) ([]domain.CustomOption, error) {
+	cur, err := repo.customOptionsColl.Find(ctx, bson.M{"name": bson.M{"$in": names}})
 	if err != nil {
 		return nil, fmt.Errorf("fetch custom options from mongo error: %w", err)
 	}
 
-	customOptions := make([]domain.CustomOption, 0, filter.Limit)
+	customOptions := make([]domain.CustomOption, 0, len(names))
 	for cur.Next(ctx) {
 		var com customOptionMongo
 		if err := cur.Decode(&com); err != nil {
@@ -99,6 +116,71 @@ func (repo *CustomOptionRepositoryMongo) GetCustomOptions(
 	return customOptions, nil
 }
 
+func (repo *CustomOptionRepositoryMongo) GetCustomOptions(
+	ctx context.Context,
+	filter domain.CustomOptionFilter,
+) ([]domain.CustomOption, domain.PageInfo, error) {
+	sortFields := pagination.SortFields(filter.OrderBy)
+
+	cursor, err := pagination.DecodeCursor(filter.Cursor, sortFields)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+
+	condition := bson.M{}
+
+	if filter.Name != "" {
+		condition["name"] = bson.M{
+			"$regex":   regexp.QuoteMeta(filter.Name),
+			"$options": "i",
+		}
+	}
+
+	total, err := repo.customOptionsColl.CountDocuments(ctx, condition)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("count custom options at mongo error: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(pagination.Sort(sortFields, cursor)).
+		SetLimit(int64(filter.Limit + 1))
+
+	if cursor != nil {
+		condition = bson.M{"$and": bson.A{condition, pagination.Condition(sortFields, cursor)}}
+	} else {
+		opts.SetSkip(int64(filter.Offset))
+	}
+
+	cur, err := repo.customOptionsColl.Find(ctx, condition, opts)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("fetch custom options from mongo error: %w", err)
+	}
+
+	var docs []bson.Raw
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("fetch custom options from mongo error: %w", err)
+	}
+
+	docs, pageInfo, err := pagination.Page(docs, sortFields, filter.Limit, filter.Offset, cursor)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+
+	pageInfo.Total = total
+
+	customOptions := make([]domain.CustomOption, 0, len(docs))
+	for _, doc := range docs {
+		var com customOptionMongo
+		if err := bson.Unmarshal(doc, &com); err != nil {
+			return nil, domain.PageInfo{}, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		customOptions = append(customOptions, toDomainCustomOption(com))
+	}
+
+	return customOptions, pageInfo, nil
+}
+
 func (repo *CustomOptionRepositoryMongo) UpdateCustomOption(
 	ctx context.Context,
 	customOption domain.CustomOption,
@@ -143,16 +225,65 @@ func (repo *CustomOptionRepositoryMongo) DeleteCustomOption(
 	return nil
 }
 
+// GetAllCustomOptions returns all custom options of the database, e.g. to back them up.
+func (repo *CustomOptionRepositoryMongo) GetAllCustomOptions(ctx context.Context) ([]domain.CustomOption, error) {
+	cur, err := repo.customOptionsColl.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("fetch custom options from mongo error: %w", err)
+	}
+
+	customOptions := make([]domain.CustomOption, 0)
+	for cur.Next(ctx) {
+		var com customOptionMongo
+		if err := cur.Decode(&com); err != nil {
+			return nil, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		customOptions = append(customOptions, toDomainCustomOption(com))
+	}
+
+	return customOptions, nil
+}
+
+// CreateCustomOptions inserts custom options in one batch, e.g. to restore them from a backup.
+func (repo *CustomOptionRepositoryMongo) CreateCustomOptions(ctx context.Context, customOptions []domain.CustomOption) error {
+	if len(customOptions) == 0 {
+		return nil
+	}
+
+	documents := make([]any, len(customOptions))
+	for i, customOption := range customOptions {
+		documents[i] = toCustomOptionMongo(customOption)
+	}
+
+	if _, err := repo.customOptionsColl.InsertMany(ctx, documents); err != nil {
+		return fmt.Errorf("insert to mongo error: %w", err)
+	}
+
+	return nil
+}
+
 func toCustomOptionMongo(domainCustomOption domain.CustomOption) customOptionMongo {
 	return customOptionMongo{
-		Id:   domainCustomOption.Id,
-		Name: domainCustomOption.Name,
+		Id:         domainCustomOption.Id,
+		Name:       domainCustomOption.Name,
+		Type:       string(domainCustomOption.Type),
+		EnumValues: domainCustomOption.EnumValues,
+		Currency:   domainCustomOption.Currency,
 	}
 }
 
 func toDomainCustomOption(com customOptionMongo) domain.CustomOption {
+	optionType := domain.CustomOptionType(com.Type)
+	if optionType == "" {
+		optionType = domain.CustomOptionTypeText
+	}
+
 	return domain.CustomOption{
-		Id:   com.Id,
-		Name: com.Name,
+		Id:         com.Id,
+		Name:       com.Name,
+		Type:       optionType,
+		EnumValues: com.EnumValues,
+		Currency:   com.Currency,
 	}
 }
@@ -0,0 +1,215 @@
+package folder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FolderRepositoryMongo struct {
+	foldersColl *mongo.Collection
+}
+
+type folderMongo struct {
+	Id        string    `bson:"_id"`
+	Name      string    `bson:"name"`
+	ParentId  string    `bson:"parent_id"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+func NewFolderRepositoryMongo(client *mongo.Client) *FolderRepositoryMongo {
+	return &FolderRepositoryMongo{
+		foldersColl: client.Database("database").Collection("folders"),
+	}
+}
+
+// GetFolders returns all folders sorted by name, so the whole tree can be built at once.
+func (repo *FolderRepositoryMongo) GetFolders(ctx context.Context) ([]domain.Folder, error) {
+	cur, err := repo.foldersColl.Find(
+		ctx,
+		bson.M{},
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetch folders from mongo error: %w", err)
+	}
+
+	return decodeFolders(ctx, cur)
+}
+
+func (repo *FolderRepositoryMongo) GetFolderById(ctx context.Context, id string) (domain.Folder, error) {
+	res := repo.foldersColl.FindOne(ctx, bson.M{"_id": id})
+	if res.Err() != nil {
+		if errors.Is(res.Err(), mongo.ErrNoDocuments) {
+			return domain.Folder{}, fmt.Errorf("folder %w", domain.ErrNotFound)
+		}
+
+		return domain.Folder{}, fmt.Errorf("get folder from mongo error %w", res.Err())
+	}
+
+	var fm folderMongo
+	if err := res.Decode(&fm); err != nil {
+		return domain.Folder{}, fmt.Errorf("decode mongo result error %w", err)
+	}
+
+	return toDomainFolder(fm), nil
+}
+
+func (repo *FolderRepositoryMongo) GetFoldersByParentId(
+	ctx context.Context,
+	parentId string,
+) ([]domain.Folder, error) {
+	cur, err := repo.foldersColl.Find(ctx, bson.M{"parent_id": parentId})
+	if err != nil {
+		return nil, fmt.Errorf("fetch folders from mongo error: %w", err)
+	}
+
+	return decodeFolders(ctx, cur)
+}
+
+type descendantsMongo struct {
+	Descendants []struct {
+		Id string `bson:"_id"`
+	} `bson:"descendants"`
+}
+
+// GetFolderDescendantIds returns ids of subfolders of the folder at any depth.
+func (repo *FolderRepositoryMongo) GetFolderDescendantIds(ctx context.Context, id string) ([]string, error) {
+	cur, err := repo.foldersColl.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": id}}},
+		{{Key: "$graphLookup", Value: bson.M{
+			"from":             "folders",
+			"startWith":        "$_id",
+			"connectFromField": "_id",
+			"connectToField":   "parent_id",
+			"as":               "descendants",
+		}}},
+		{{Key: "$project", Value: bson.M{"descendants._id": 1}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("aggregate folders at mongo error: %w", err)
+	}
+
+	if !cur.Next(ctx) {
+		if err := cur.Err(); err != nil {
+			return nil, fmt.Errorf("aggregate folders at mongo error: %w", err)
+		}
+
+		return nil, fmt.Errorf("folder %w", domain.ErrNotFound)
+	}
+
+	var dm descendantsMongo
+	if err := cur.Decode(&dm); err != nil {
+		return nil, fmt.Errorf("decode mongo result error %w", err)
+	}
+
+	ids := make([]string, len(dm.Descendants))
+	for i, d := range dm.Descendants {
+		ids[i] = d.Id
+	}
+
+	return ids, nil
+}
+
+func (repo *FolderRepositoryMongo) CreateFolder(ctx context.Context, folder domain.Folder) error {
+	_, err := repo.foldersColl.InsertOne(ctx, toFolderMongo(folder))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("folder with name '%s' %w", folder.Name, domain.ErrAlreadyExists)
+		}
+
+		return fmt.Errorf("insert to mongo error: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *FolderRepositoryMongo) UpdateFolder(ctx context.Context, folder domain.Folder) error {
+	res, err := repo.foldersColl.UpdateOne(
+		ctx,
+		bson.M{"_id": folder.Id},
+		bson.M{"$set": bson.M{"name": folder.Name, "parent_id": folder.ParentId}},
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("folder with name '%s' %w", folder.Name, domain.ErrAlreadyExists)
+		}
+
+		return fmt.Errorf("update at mongo error: %w", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("folder %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (repo *FolderRepositoryMongo) DeleteFolder(ctx context.Context, id string) error {
+	res, err := repo.foldersColl.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("delete from mongo error: %w", err)
+	}
+
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("folder %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+// CreateFolders inserts folders in one batch, e.g. to restore them from a backup.
+func (repo *FolderRepositoryMongo) CreateFolders(ctx context.Context, folders []domain.Folder) error {
+	if len(folders) == 0 {
+		return nil
+	}
+
+	documents := make([]any, len(folders))
+	for i, folder := range folders {
+		documents[i] = toFolderMongo(folder)
+	}
+
+	if _, err := repo.foldersColl.InsertMany(ctx, documents); err != nil {
+		return fmt.Errorf("insert to mongo error: %w", err)
+	}
+
+	return nil
+}
+
+func decodeFolders(ctx context.Context, cur *mongo.Cursor) ([]domain.Folder, error) {
+	folders := make([]domain.Folder, 0)
+	for cur.Next(ctx) {
+		var fm folderMongo
+		if err := cur.Decode(&fm); err != nil {
+			return nil, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		folders = append(folders, toDomainFolder(fm))
+	}
+
+	return folders, nil
+}
+
+func toFolderMongo(domainFolder domain.Folder) folderMongo {
+	return folderMongo{
+		Id:        domainFolder.Id,
+		Name:      domainFolder.Name,
+		ParentId:  domainFolder.ParentId,
+		CreatedAt: domainFolder.CreatedAt,
+	}
+}
+
+func toDomainFolder(fm folderMongo) domain.Folder {
+	return domain.Folder{
+		Id:        fm.Id,
+		Name:      fm.Name,
+		ParentId:  fm.ParentId,
+		CreatedAt: fm.CreatedAt,
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
+	ocor "github.com/Unlites/comparison_center/backend/internal/adapters/repositories/object_customoption"
+	"github.com/Unlites/comparison_center/backend/internal/adapters/repositories/pagination"
 	"github.com/Unlites/comparison_center/backend/internal/domain"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -13,15 +16,26 @@ import (
 )
 
 type ObjectRepositoryMongo struct {
-	objectsColl *mongo.Collection
+	objectsColl                 *mongo.Collection
+	objectCustomOptionsCollName string
 }
 
 func NewObjectRepositoryMongo(client *mongo.Client) *ObjectRepositoryMongo {
 	return &ObjectRepositoryMongo{
-		objectsColl: client.Database("database").Collection("objects"),
+		objectsColl:                 client.Database("database").Collection("objects"),
+		objectCustomOptionsCollName: "object_custom_options",
 	}
 }
 
+var optionOperators = map[domain.OptionOperator]string{
+	domain.OptionOperatorEq:  "$eq",
+	domain.OptionOperatorNe:  "$ne",
+	domain.OptionOperatorGt:  "$gt",
+	domain.OptionOperatorGte: "$gte",
+	domain.OptionOperatorLt:  "$lt",
+	domain.OptionOperatorLte: "$lte",
+}
+
 type objectMongo struct {
 	Id           string    `bson:"_id"`
 	Name         string    `bson:"name"`
@@ -29,20 +43,26 @@ type objectMongo struct {
 	CreatedAt    time.Time `bson:"created_at"`
 	Advs         string    `bson:"advs"`
 	Disadvs      string    `bson:"disadvs"`
-	PhotoPath    string    `bson:"photo_path"`
 	ComparisonId string    `bson:"comparison_id"`
+	// DeletedAt is kept null for the objects that are not in the trash.
+	DeletedAt *time.Time `bson:"deleted_at"`
+	// DeletedWithComparison marks objects moved to the trash along with their comparison.
+	DeletedWithComparison bool `bson:"deleted_with_comparison,omitempty"`
+	// OptionValues keeps raw values of custom options of the object,
+	// so they are covered by the text index of objects.
+	OptionValues []string `bson:"option_values"`
+}
+
+type objectWithOptionsMongo struct {
+	objectMongo   `bson:",inline"`
+	CustomOptions []ocor.ObjectCustomOptionMongo `bson:"custom_options"`
 }
 
 func (repo *ObjectRepositoryMongo) GetObjects(
 	ctx context.Context,
 	filter domain.ObjectFilter,
-) ([]domain.Object, error) {
-	opts := options.Find().
-		SetSort(bson.M{filter.OrderBy: 1}).
-		SetSkip(int64(filter.Offset)).
-		SetLimit(int64(filter.Limit))
-
-	condition := bson.M{}
+) ([]domain.Object, domain.PageInfo, error) {
+	condition := bson.M{"deleted_at": nil}
 
 	if filter.Name != "" {
 		condition["name"] = bson.M{
@@ -51,16 +71,208 @@ func (repo *ObjectRepositoryMongo) GetObjects(
 		}
 	}
 
+	if filter.Query != "" {
+		condition["$text"] = bson.M{"$search": filter.Query}
+	}
+
 	if filter.ComparisonId != "" {
 		condition["comparison_id"] = filter.ComparisonId
 	}
 
+	if filter.Query != "" || filter.OrdersByCustomOptions() || len(filter.OptionPredicates) != 0 {
+		return repo.aggregateObjects(ctx, condition, filter)
+	}
+
+	sortFields := pagination.SortFields(filter.OrderBy)
+
+	cursor, err := pagination.DecodeCursor(filter.Cursor, sortFields)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+
+	total, err := repo.objectsColl.CountDocuments(ctx, condition)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("count objects at mongo error: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(pagination.Sort(sortFields, cursor)).
+		SetLimit(int64(filter.Limit + 1))
+
+	if cursor != nil {
+		condition = bson.M{"$and": bson.A{condition, pagination.Condition(sortFields, cursor)}}
+	} else {
+		opts.SetSkip(int64(filter.Offset))
+	}
+
 	cur, err := repo.objectsColl.Find(ctx, condition, opts)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("fetch objects from mongo error: %w", err)
+	}
+
+	var docs []bson.Raw
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("fetch objects from mongo error: %w", err)
+	}
+
+	return decodeObjectsPage(docs, sortFields, filter, cursor, total)
+}
+
+// aggregateObjects joins custom option values of objects to filter and order objects
+// by them before pagination is applied. Objects found by the text search get their
+// text score as "score" field, so they can be ordered by it.
+func (repo *ObjectRepositoryMongo) aggregateObjects(
+	ctx context.Context,
+	condition bson.M,
+	filter domain.ObjectFilter,
+) ([]domain.Object, domain.PageInfo, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: condition}},
+	}
+
+	if filter.Query != "" {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{
+			domain.ScoreOrdering: bson.M{"$meta": "textScore"},
+		}}})
+	}
+
+	pipeline = append(pipeline,
+		bson.D{{Key: "$lookup", Value: bson.M{
+			"from":         repo.objectCustomOptionsCollName,
+			"localField":   "_id",
+			"foreignField": "object_id",
+			"as":           "custom_options",
+		}}},
+	)
+
+	if len(filter.OptionPredicates) != 0 {
+		predicates := make(bson.A, len(filter.OptionPredicates))
+		for i, p := range filter.OptionPredicates {
+			predicates[i] = bson.M{"custom_options": bson.M{"$elemMatch": bson.M{
+				"custom_option_id": p.CustomOptionId,
+				"value":            bson.M{optionOperators[p.Operator]: p.TypedValue},
+			}}}
+		}
+
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"$and": predicates}}})
+	}
+
+	// values of custom options objects are ordered by are placed to separate fields
+	sortFields := pagination.SortFields(filter.OrderBy)
+	optionSortValues := bson.M{}
+	for i, key := range filter.OrderBy {
+		customOptionId, ok := key.CustomOptionId()
+		if !ok {
+			continue
+		}
+
+		sortFields[i].Key = "option_sort_value_" + customOptionId
+		optionSortValues[sortFields[i].Key] = bson.M{"$first": bson.M{"$map": bson.M{
+			"input": bson.M{"$filter": bson.M{
+				"input": "$custom_options",
+				"cond":  bson.M{"$eq": bson.A{"$$this.custom_option_id", customOptionId}},
+			}},
+			"in": "$$this.value",
+		}}}
+	}
+
+	if len(optionSortValues) != 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: optionSortValues}})
+	}
+
+	cursor, err := pagination.DecodeCursor(filter.Cursor, sortFields)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+
+	countPipeline := append(slices.Clone(pipeline), bson.D{{Key: "$count", Value: "total"}})
+
+	countCur, err := repo.objectsColl.Aggregate(ctx, countPipeline)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("count objects at mongo error: %w", err)
+	}
+
+	var counts []struct {
+		Total int64 `bson:"total"`
+	}
+	if err := countCur.All(ctx, &counts); err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("count objects at mongo error: %w", err)
+	}
+
+	var total int64
+	if len(counts) != 0 {
+		total = counts[0].Total
+	}
+
+	if cursor != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: pagination.Condition(sortFields, cursor)}})
+	}
+
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: pagination.Sort(sortFields, cursor)}})
+
+	if cursor == nil {
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: int64(filter.Offset)}})
+	}
+
+	pipeline = append(pipeline, bson.D{{Key: "$limit", Value: int64(filter.Limit + 1)}})
+
+	cur, err := repo.objectsColl.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("aggregate objects at mongo error: %w", err)
+	}
+
+	var docs []bson.Raw
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("aggregate objects at mongo error: %w", err)
+	}
+
+	return decodeObjectsPage(docs, sortFields, filter, cursor, total)
+}
+
+func decodeObjectsPage(
+	docs []bson.Raw,
+	sortFields []pagination.SortField,
+	filter domain.ObjectFilter,
+	cursor *pagination.Cursor,
+	total int64,
+) ([]domain.Object, domain.PageInfo, error) {
+	docs, pageInfo, err := pagination.Page(docs, sortFields, filter.Limit, filter.Offset, cursor)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+
+	pageInfo.Total = total
+
+	objects := make([]domain.Object, 0, len(docs))
+	for _, doc := range docs {
+		var obj objectMongo
+		if err := bson.Unmarshal(doc, &obj); err != nil {
+			return nil, domain.PageInfo{}, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		object := toDomainObject(obj)
+		if score, ok := doc.Lookup(domain.ScoreOrdering).DoubleOK(); ok {
+			object.Score = score
+		}
+
+		objects = append(objects, object)
+	}
+
+	return objects, pageInfo, nil
+}
+
+func (repo *ObjectRepositoryMongo) GetObjectsByComparisonId(
+	ctx context.Context,
+	comparisonId string,
+) ([]domain.Object, error) {
+	opts := options.Find().SetSort(bson.M{"created_at": 1})
+
+	cur, err := repo.objectsColl.Find(ctx, bson.M{"comparison_id": comparisonId, "deleted_at": nil}, opts)
 	if err != nil {
 		return nil, fmt.Errorf("fetch objects from mongo error: %w", err)
 	}
 
-	objects := make([]domain.Object, 0, filter.Limit)
+	objects := make([]domain.Object, 0)
 	for cur.Next(ctx) {
 		var obj objectMongo
 		if err := cur.Decode(&obj); err != nil {
@@ -73,11 +285,52 @@ func (repo *ObjectRepositoryMongo) GetObjects(
 	return objects, nil
 }
 
+// GetObjectsWithOptionsByComparisonId fetches objects of the comparison
+// together with their custom option values in a single aggregation.
+func (repo *ObjectRepositoryMongo) GetObjectsWithOptionsByComparisonId(
+	ctx context.Context,
+	comparisonId string,
+) ([]domain.Object, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"comparison_id": comparisonId, "deleted_at": nil}}},
+		{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         repo.objectCustomOptionsCollName,
+			"localField":   "_id",
+			"foreignField": "object_id",
+			"as":           "custom_options",
+		}}},
+	}
+
+	cur, err := repo.objectsColl.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate objects at mongo error: %w", err)
+	}
+
+	objects := make([]domain.Object, 0)
+	for cur.Next(ctx) {
+		var obj objectWithOptionsMongo
+		if err := cur.Decode(&obj); err != nil {
+			return nil, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		object := toDomainObject(obj.objectMongo)
+		object.ObjectCustomOptions = make([]domain.ObjectCustomOption, len(obj.CustomOptions))
+		for i, ocom := range obj.CustomOptions {
+			object.ObjectCustomOptions[i] = ocor.ToDomainObjectCustomOption(ocom)
+		}
+
+		objects = append(objects, object)
+	}
+
+	return objects, nil
+}
+
 func (repo *ObjectRepositoryMongo) GetObjectById(
 	ctx context.Context,
 	id string,
 ) (domain.Object, error) {
-	res := repo.objectsColl.FindOne(ctx, bson.M{"_id": id})
+	res := repo.objectsColl.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil})
 	if res.Err() != nil {
 		if errors.Is(res.Err(), mongo.ErrNoDocuments) {
 			return domain.Object{}, fmt.Errorf("object %w", domain.ErrNotFound)
@@ -112,7 +365,7 @@ func (repo *ObjectRepositoryMongo) UpdateObject(
 ) error {
 	res, err := repo.objectsColl.UpdateOne(
 		ctx,
-		bson.M{"_id": object.Id},
+		bson.M{"_id": object.Id, "deleted_at": nil},
 		bson.M{"$set": toObjectMongo(object)},
 	)
 	if err != nil {
@@ -126,23 +379,257 @@ func (repo *ObjectRepositoryMongo) UpdateObject(
 	return nil
 }
 
+// UpdateObjectsOptionValues replaces raw values of custom options kept in documents
+// of the objects with values of their ObjectCustomOptions.
+func (repo *ObjectRepositoryMongo) UpdateObjectsOptionValues(
+	ctx context.Context,
+	objects []domain.Object,
+) error {
+	if len(objects) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, len(objects))
+	for i, obj := range objects {
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": obj.Id}).
+			SetUpdate(bson.M{"$set": bson.M{"option_values": optionValues(obj)}})
+	}
+
+	if _, err := repo.objectsColl.BulkWrite(ctx, models); err != nil {
+		return fmt.Errorf("update at mongo error: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteObject moves the object to the trash.
 func (repo *ObjectRepositoryMongo) DeleteObject(
 	ctx context.Context,
 	id string,
+	deletedAt time.Time,
 ) error {
-	res, err := repo.objectsColl.DeleteOne(ctx, bson.M{"_id": id})
+	res, err := repo.objectsColl.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": deletedAt}},
+	)
 	if err != nil {
-		return fmt.Errorf("delete from mongo error: %w", err)
+		return fmt.Errorf("update at mongo error: %w", err)
 	}
 
-	if res.DeletedCount == 0 {
+	if res.MatchedCount == 0 {
 		return fmt.Errorf("object %w", domain.ErrNotFound)
 	}
 
 	return nil
 }
 
+// DeleteObjectsByComparisonId moves objects of the comparison to the trash along with it.
+// Objects that are already in the trash stay there on their own.
+func (repo *ObjectRepositoryMongo) DeleteObjectsByComparisonId(
+	ctx context.Context,
+	comparisonId string,
+	deletedAt time.Time,
+) error {
+	_, err := repo.objectsColl.UpdateMany(
+		ctx,
+		bson.M{"comparison_id": comparisonId, "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": deletedAt, "deleted_with_comparison": true}},
+	)
+	if err != nil {
+		return fmt.Errorf("update at mongo error: %w", err)
+	}
+
+	return nil
+}
+
+// GetDeletedObjects returns objects moved to the trash on their own before the given time,
+// all of them when it is zero. Recently deleted objects go first.
+func (repo *ObjectRepositoryMongo) GetDeletedObjects(
+	ctx context.Context,
+	deletedBefore time.Time,
+) ([]domain.Object, error) {
+	deletedAt := bson.M{"$ne": nil}
+	if !deletedBefore.IsZero() {
+		deletedAt["$lt"] = deletedBefore
+	}
+
+	cur, err := repo.objectsColl.Find(
+		ctx,
+		bson.M{"deleted_at": deletedAt, "deleted_with_comparison": bson.M{"$ne": true}},
+		options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetch objects from mongo error: %w", err)
+	}
+
+	return decodeObjects(ctx, cur)
+}
+
+func (repo *ObjectRepositoryMongo) GetDeletedObjectById(
+	ctx context.Context,
+	id string,
+) (domain.Object, error) {
+	res := repo.objectsColl.FindOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}})
+	if res.Err() != nil {
+		if errors.Is(res.Err(), mongo.ErrNoDocuments) {
+			return domain.Object{}, fmt.Errorf("deleted object %w", domain.ErrNotFound)
+		}
+
+		return domain.Object{}, fmt.Errorf("get object from mongo error %w", res.Err())
+	}
+
+	var obj objectMongo
+	if err := res.Decode(&obj); err != nil {
+		return domain.Object{}, fmt.Errorf("decode mongo result error %w", err)
+	}
+
+	return toDomainObject(obj), nil
+}
+
+// GetDeletedObjectsByComparisonId returns objects of the comparison that are in the trash,
+// both the ones deleted along with it and on their own.
+func (repo *ObjectRepositoryMongo) GetDeletedObjectsByComparisonId(
+	ctx context.Context,
+	comparisonId string,
+) ([]domain.Object, error) {
+	cur, err := repo.objectsColl.Find(
+		ctx,
+		bson.M{"comparison_id": comparisonId, "deleted_at": bson.M{"$ne": nil}},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetch objects from mongo error: %w", err)
+	}
+
+	return decodeObjects(ctx, cur)
+}
+
+// GetObjectsDeletedWithComparison returns objects moved to the trash along with the comparison.
+func (repo *ObjectRepositoryMongo) GetObjectsDeletedWithComparison(
+	ctx context.Context,
+	comparisonId string,
+) ([]domain.Object, error) {
+	cur, err := repo.objectsColl.Find(
+		ctx,
+		bson.M{"comparison_id": comparisonId, "deleted_with_comparison": true},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetch objects from mongo error: %w", err)
+	}
+
+	return decodeObjects(ctx, cur)
+}
+
+func (repo *ObjectRepositoryMongo) RestoreObject(
+	ctx context.Context,
+	id string,
+) error {
+	res, err := repo.objectsColl.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}},
+		bson.M{"$set": bson.M{"deleted_at": nil}},
+	)
+	if err != nil {
+		return fmt.Errorf("update at mongo error: %w", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("deleted object %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+// RestoreObjectsByComparisonId takes objects deleted along with the comparison out of the trash.
+func (repo *ObjectRepositoryMongo) RestoreObjectsByComparisonId(
+	ctx context.Context,
+	comparisonId string,
+) error {
+	_, err := repo.objectsColl.UpdateMany(
+		ctx,
+		bson.M{"comparison_id": comparisonId, "deleted_with_comparison": true},
+		bson.M{
+			"$set":   bson.M{"deleted_at": nil},
+			"$unset": bson.M{"deleted_with_comparison": ""},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("update at mongo error: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeObjects removes objects from the trash for good.
+func (repo *ObjectRepositoryMongo) PurgeObjects(
+	ctx context.Context,
+	ids []string,
+) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := repo.objectsColl.DeleteMany(
+		ctx,
+		bson.M{"_id": bson.M{"$in": ids}, "deleted_at": bson.M{"$ne": nil}},
+	)
+	if err != nil {
+		return fmt.Errorf("delete from mongo error: %w", err)
+	}
+
+	return nil
+}
+
+// GetAllObjects returns all objects of the database except the ones in the trash,
+// e.g. to back them up.
+func (repo *ObjectRepositoryMongo) GetAllObjects(ctx context.Context) ([]domain.Object, error) {
+	cur, err := repo.objectsColl.Find(ctx, bson.M{"deleted_at": nil})
+	if err != nil {
+		return nil, fmt.Errorf("fetch objects from mongo error: %w", err)
+	}
+
+	return decodeObjects(ctx, cur)
+}
+
+func decodeObjects(ctx context.Context, cur *mongo.Cursor) ([]domain.Object, error) {
+	objects := make([]domain.Object, 0)
+	for cur.Next(ctx) {
+		var objMongo objectMongo
+		if err := cur.Decode(&objMongo); err != nil {
+			return nil, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		objects = append(objects, toDomainObject(objMongo))
+	}
+
+	return objects, nil
+}
+
+// CreateObjects inserts objects in one batch, e.g. to restore them from a backup.
+func (repo *ObjectRepositoryMongo) CreateObjects(ctx context.Context, objects []domain.Object) error {
+	if len(objects) == 0 {
+		return nil
+	}
+
+	documents := make([]any, len(objects))
+	for i, object := range objects {
+		documents[i] = toObjectMongo(object)
+	}
+
+	if _, err := repo.objectsColl.InsertMany(ctx, documents); err != nil {
+		return fmt.Errorf("insert to mongo error: %w", err)
+	}
+
+	return nil
+}
+
 func toDomainObject(objMongo objectMongo) domain.Object {
+	var deletedAt time.Time
+	if objMongo.DeletedAt != nil {
+		deletedAt = *objMongo.DeletedAt
+	}
+
 	return domain.Object{
 		Id:           objMongo.Id,
 		Name:         objMongo.Name,
@@ -150,8 +637,8 @@ func toDomainObject(objMongo objectMongo) domain.Object {
 		CreatedAt:    objMongo.CreatedAt,
 		Advs:         objMongo.Advs,
 		Disadvs:      objMongo.Disadvs,
-		PhotoPath:    objMongo.PhotoPath,
 		ComparisonId: objMongo.ComparisonId,
+		DeletedAt:    deletedAt,
 	}
 }
 
@@ -163,7 +650,25 @@ func toObjectMongo(obj domain.Object) objectMongo {
 		CreatedAt:    obj.CreatedAt,
 		Advs:         obj.Advs,
 		Disadvs:      obj.Disadvs,
-		PhotoPath:    obj.PhotoPath,
 		ComparisonId: obj.ComparisonId,
+		DeletedAt:    deletedAtMongo(obj.DeletedAt),
+		OptionValues: optionValues(obj),
 	}
 }
+
+func optionValues(obj domain.Object) []string {
+	values := make([]string, len(obj.ObjectCustomOptions))
+	for i, oco := range obj.ObjectCustomOptions {
+		values[i] = oco.Value
+	}
+
+	return values
+}
+
+func deletedAtMongo(deletedAt time.Time) *time.Time {
+	if deletedAt.IsZero() {
+		return nil
+	}
+
+	return &deletedAt
+}
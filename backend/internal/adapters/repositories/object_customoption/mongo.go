@@ -6,6 +6,7 @@ import (
 
 	"github.com/Unlites/comparison_center/backend/internal/domain"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -19,10 +20,14 @@ func NewObjectCustomOptionRepositoryMongo(client *mongo.Client) *ObjectCustomOpt
 	}
 }
 
-type objectCustomOptionMongo struct {
+// ObjectCustomOptionMongo keeps value in its typed form, so it can be sorted and
+// filtered by mongo, and raw value as canonical text representation of it.
+// It is also used to decode custom options joined to objects by other repositories.
+type ObjectCustomOptionMongo struct {
 	ObjectId       string `bson:"object_id"`
 	CustomOptionId string `bson:"custom_option_id"`
-	Value          string `bson:"value"`
+	Value          any    `bson:"value"`
+	RawValue       string `bson:"raw_value"`
 }
 
 func (repo *ObjectCustomOptionRepositoryMongo) GetObjectCustomOptionsByObjectId(
@@ -36,12 +41,34 @@ func (repo *ObjectCustomOptionRepositoryMongo) GetObjectCustomOptionsByObjectId(
 
 	objCustomOptions := make([]domain.ObjectCustomOption, 0)
 	for cur.Next(ctx) {
-		var ocom objectCustomOptionMongo
+		var ocom ObjectCustomOptionMongo
 		if err := cur.Decode(&ocom); err != nil {
 			return nil, fmt.Errorf("decode mongo result error %w", err)
 		}
 
-		objCustomOptions = append(objCustomOptions, toDomainObjectCustomOption(ocom))
+		objCustomOptions = append(objCustomOptions, ToDomainObjectCustomOption(ocom))
+	}
+
+	return objCustomOptions, nil
+}
+
+func (repo *ObjectCustomOptionRepositoryMongo) GetObjectCustomOptionsByObjectIds(
+	ctx context.Context,
+	objectIds []string,
+) ([]domain.ObjectCustomOption, error) {
+	cur, err := repo.objectCustomOptionsColl.Find(ctx, bson.M{"object_id": bson.M{"$in": objectIds}})
+	if err != nil {
+		return nil, fmt.Errorf("fetch object custom options from mongo error: %w", err)
+	}
+
+	objCustomOptions := make([]domain.ObjectCustomOption, 0)
+	for cur.Next(ctx) {
+		var ocom ObjectCustomOptionMongo
+		if err := cur.Decode(&ocom); err != nil {
+			return nil, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		objCustomOptions = append(objCustomOptions, ToDomainObjectCustomOption(ocom))
 	}
 
 	return objCustomOptions, nil
@@ -93,18 +120,123 @@ func (repo *ObjectCustomOptionRepositoryMongo) UpdateObjectCustomOption(
 	return nil
 }
 
-func toDomainObjectCustomOption(ocom objectCustomOptionMongo) domain.ObjectCustomOption {
+func (repo *ObjectCustomOptionRepositoryMongo) DeleteObjectCustomOptionsByObjectIds(
+	ctx context.Context,
+	objectIds []string,
+) error {
+	_, err := repo.objectCustomOptionsColl.DeleteMany(ctx, bson.M{"object_id": bson.M{"$in": objectIds}})
+	if err != nil {
+		return fmt.Errorf("delete from mongo error: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *ObjectCustomOptionRepositoryMongo) DeleteObjectCustomOptionsByCustomOptionId(
+	ctx context.Context,
+	customOptionId string,
+) error {
+	_, err := repo.objectCustomOptionsColl.DeleteMany(ctx, bson.M{"custom_option_id": customOptionId})
+	if err != nil {
+		return fmt.Errorf("delete from mongo error: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *ObjectCustomOptionRepositoryMongo) GetObjectCustomOptionsByCustomOptionId(
+	ctx context.Context,
+	customOptionId string,
+) ([]domain.ObjectCustomOption, error) {
+	cur, err := repo.objectCustomOptionsColl.Find(ctx, bson.M{"custom_option_id": customOptionId})
+	if err != nil {
+		return nil, fmt.Errorf("fetch object custom options from mongo error: %w", err)
+	}
+
+	objCustomOptions := make([]domain.ObjectCustomOption, 0)
+	for cur.Next(ctx) {
+		var ocom ObjectCustomOptionMongo
+		if err := cur.Decode(&ocom); err != nil {
+			return nil, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		objCustomOptions = append(objCustomOptions, ToDomainObjectCustomOption(ocom))
+	}
+
+	return objCustomOptions, nil
+}
+
+// GetAllObjectCustomOptions returns all object custom options of the database, e.g. to back them up.
+func (repo *ObjectCustomOptionRepositoryMongo) GetAllObjectCustomOptions(ctx context.Context) ([]domain.ObjectCustomOption, error) {
+	cur, err := repo.objectCustomOptionsColl.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("fetch object custom options from mongo error: %w", err)
+	}
+
+	objCustomOptions := make([]domain.ObjectCustomOption, 0)
+	for cur.Next(ctx) {
+		var ocom ObjectCustomOptionMongo
+		if err := cur.Decode(&ocom); err != nil {
+			return nil, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		objCustomOptions = append(objCustomOptions, ToDomainObjectCustomOption(ocom))
+	}
+
+	return objCustomOptions, nil
+}
+
+// AddObjectCustomOptions inserts object custom options in one batch, e.g. to restore them from a backup.
+func (repo *ObjectCustomOptionRepositoryMongo) AddObjectCustomOptions(ctx context.Context, objCustomOptions []domain.ObjectCustomOption) error {
+	if len(objCustomOptions) == 0 {
+		return nil
+	}
+
+	documents := make([]any, len(objCustomOptions))
+	for i, objCustomOption := range objCustomOptions {
+		documents[i] = toObjectCustomOptionMongo(objCustomOption)
+	}
+
+	if _, err := repo.objectCustomOptionsColl.InsertMany(ctx, documents); err != nil {
+		return fmt.Errorf("insert to mongo error: %w", err)
+	}
+
+	return nil
+}
+
+func ToDomainObjectCustomOption(ocom ObjectCustomOptionMongo) domain.ObjectCustomOption {
+	typedValue := ocom.Value
+	switch v := ocom.Value.(type) {
+	case int32:
+		typedValue = int64(v)
+	case primitive.DateTime:
+		typedValue = v.Time().UTC()
+	}
+
+	rawValue := ocom.RawValue
+	if rawValue == "" {
+		// values stored before typed options were introduced are plain strings
+		rawValue = fmt.Sprint(typedValue)
+	}
+
 	return domain.ObjectCustomOption{
 		ObjectId:       ocom.ObjectId,
 		CustomOptionId: ocom.CustomOptionId,
-		Value:          ocom.Value,
+		Value:          rawValue,
+		TypedValue:     typedValue,
 	}
 }
 
-func toObjectCustomOptionMongo(objectCustomOption domain.ObjectCustomOption) objectCustomOptionMongo {
-	return objectCustomOptionMongo{
+func toObjectCustomOptionMongo(objectCustomOption domain.ObjectCustomOption) ObjectCustomOptionMongo {
+	typedValue := objectCustomOption.TypedValue
+	if typedValue == nil {
+		typedValue = objectCustomOption.Value
+	}
+
+	return ObjectCustomOptionMongo{
 		ObjectId:       objectCustomOption.ObjectId,
 		CustomOptionId: objectCustomOption.CustomOptionId,
-		Value:          objectCustomOption.Value,
+		Value:          typedValue,
+		RawValue:       objectCustomOption.Value,
 	}
 }
@@ -0,0 +1,203 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// SortField is a field documents of a list are ordered by. The last field
+// of a list ordering must be unique, e.g. "_id", so the position of every
+// document in the list is unambiguous.
+type SortField struct {
+	Key  string
+	Desc bool
+}
+
+// SortFields maps ordering of the list to sort fields. Ordering is completed
+// with "_id", so the position of documents with equal values is deterministic.
+func SortFields(keys []domain.SortKey) []SortField {
+	fields := make([]SortField, 0, len(keys)+1)
+	for _, key := range keys {
+		fields = append(fields, SortField{Key: key.Field, Desc: key.Desc})
+	}
+
+	return append(fields, SortField{Key: "_id"})
+}
+
+// Cursor points at the position of a document in the list ordered by sort fields.
+// Backward cursor is used to fetch documents placed before that position.
+type Cursor struct {
+	Values   []any
+	Backward bool
+}
+
+type cursorMongo struct {
+	Keys     []string `bson:"k"`
+	Values   bson.A   `bson:"v"`
+	Backward bool     `bson:"b,omitempty"`
+}
+
+// DecodeCursor parses cursor issued for the same ordering. Empty string gives nil cursor.
+func DecodeCursor(encoded string, fields []SortField) (*Cursor, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("cursor is malformed - %w", domain.ErrInvalidValue)
+	}
+
+	var cm cursorMongo
+	if err := bson.Unmarshal(data, &cm); err != nil {
+		return nil, fmt.Errorf("cursor is malformed - %w", domain.ErrInvalidValue)
+	}
+
+	if !slices.Equal(cm.Keys, sortKeys(fields)) || len(cm.Values) != len(fields) {
+		return nil, fmt.Errorf("cursor does not match ordering - %w", domain.ErrInvalidValue)
+	}
+
+	return &Cursor{Values: cm.Values, Backward: cm.Backward}, nil
+}
+
+func encodeCursor(doc bson.Raw, fields []SortField, backward bool) (string, error) {
+	values := make(bson.A, len(fields))
+	for i, field := range fields {
+		raw, err := doc.LookupErr(field.Key)
+		if err != nil {
+			continue
+		}
+
+		if err := raw.Unmarshal(&values[i]); err != nil {
+			return "", fmt.Errorf("decode sort value of cursor error: %w", err)
+		}
+	}
+
+	data, err := bson.Marshal(cursorMongo{Keys: sortKeys(fields), Values: values, Backward: backward})
+	if err != nil {
+		return "", fmt.Errorf("encode cursor error: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func sortKeys(fields []SortField) []string {
+	keys := make([]string, len(fields))
+	for i, field := range fields {
+		keys[i] = field.Key
+	}
+
+	return keys
+}
+
+// Sort returns sort specification of the list, reversed when documents are fetched backward.
+func Sort(fields []SortField, cursor *Cursor) bson.D {
+	backward := cursor != nil && cursor.Backward
+
+	sort := make(bson.D, len(fields))
+	for i, field := range fields {
+		direction := 1
+		if field.Desc != backward {
+			direction = -1
+		}
+
+		sort[i] = bson.E{Key: field.Key, Value: direction}
+	}
+
+	return sort
+}
+
+// Condition matches documents placed after the cursor, or before it for backward cursor.
+// Missing values are considered the lowest ones as mongo sorts them.
+func Condition(fields []SortField, cursor *Cursor) bson.M {
+	alternatives := make(bson.A, 0, len(fields))
+
+	for i, field := range fields {
+		value := cursor.Values[i]
+
+		var next bson.M
+		if field.Desc == cursor.Backward {
+			if value == nil {
+				next = bson.M{field.Key: bson.M{"$ne": nil}}
+			} else {
+				next = bson.M{field.Key: bson.M{"$gt": value}}
+			}
+		} else {
+			if value == nil {
+				// nothing is placed before missing value
+				next = nil
+			} else {
+				next = bson.M{"$or": bson.A{
+					bson.M{field.Key: bson.M{"$lt": value}},
+					bson.M{field.Key: nil},
+				}}
+			}
+		}
+
+		if next != nil {
+			conditions := make(bson.A, 0, i+1)
+			for j := 0; j < i; j++ {
+				conditions = append(conditions, bson.M{fields[j].Key: cursor.Values[j]})
+			}
+
+			alternatives = append(alternatives, bson.M{"$and": append(conditions, next)})
+		}
+	}
+
+	if len(alternatives) == 0 {
+		return bson.M{"_id": bson.M{"$exists": false}}
+	}
+
+	return bson.M{"$or": alternatives}
+}
+
+// Page trims documents fetched with limit increased by one to detect further pages,
+// restores the order of documents fetched backward and issues cursors of neighbouring pages.
+func Page(
+	docs []bson.Raw,
+	fields []SortField,
+	limit, offset int,
+	cursor *Cursor,
+) ([]bson.Raw, domain.PageInfo, error) {
+	backward := cursor != nil && cursor.Backward
+
+	hasMore := len(docs) > limit
+	if hasMore {
+		docs = docs[:limit]
+	}
+
+	if backward {
+		slices.Reverse(docs)
+	}
+
+	var pageInfo domain.PageInfo
+	if len(docs) == 0 {
+		return docs, pageInfo, nil
+	}
+
+	hasNext, hasPrev := hasMore, cursor != nil || offset > 0
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	var err error
+	if hasNext {
+		pageInfo.NextCursor, err = encodeCursor(docs[len(docs)-1], fields, false)
+		if err != nil {
+			return nil, domain.PageInfo{}, err
+		}
+	}
+
+	if hasPrev {
+		pageInfo.PrevCursor, err = encodeCursor(docs[0], fields, true)
+		if err != nil {
+			return nil, domain.PageInfo{}, err
+		}
+	}
+
+	return docs, pageInfo, nil
+}
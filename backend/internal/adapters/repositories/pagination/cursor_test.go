@@ -0,0 +1,279 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var nameFields = []SortField{{Key: "name"}, {Key: "_id"}}
+
+func rawDocs(t *testing.T, docs ...bson.M) []bson.Raw {
+	raws := make([]bson.Raw, len(docs))
+	for i, doc := range docs {
+		data, err := bson.Marshal(doc)
+		assert.NoError(t, err)
+		raws[i] = data
+	}
+
+	return raws
+}
+
+func docIds(t *testing.T, docs []bson.Raw) []string {
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		id, ok := doc.Lookup("_id").StringValueOK()
+		assert.True(t, ok)
+		ids[i] = id
+	}
+
+	return ids
+}
+
+func TestSortFields(t *testing.T) {
+	fields := SortFields([]domain.SortKey{{Field: "rating", Desc: true}, {Field: "name"}})
+
+	assert.Equal(t, []SortField{{Key: "rating", Desc: true}, {Key: "name"}, {Key: "_id"}}, fields)
+}
+
+func TestSort(t *testing.T) {
+	fields := []SortField{{Key: "rating", Desc: true}, {Key: "_id"}}
+
+	tests := []struct {
+		name   string
+		cursor *Cursor
+		want   bson.D
+	}{
+		{name: "First page", cursor: nil, want: bson.D{{Key: "rating", Value: -1}, {Key: "_id", Value: 1}}},
+		{
+			name:   "Forward",
+			cursor: &Cursor{Values: []any{int32(5), "a"}},
+			want:   bson.D{{Key: "rating", Value: -1}, {Key: "_id", Value: 1}},
+		},
+		{
+			name:   "Backward is reversed",
+			cursor: &Cursor{Values: []any{int32(5), "a"}, Backward: true},
+			want:   bson.D{{Key: "rating", Value: 1}, {Key: "_id", Value: -1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Sort(fields, tt.cursor))
+		})
+	}
+}
+
+func TestCondition(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []SortField
+		cursor *Cursor
+		want   bson.M
+	}{
+		{
+			name:   "Ascending forward with _id tiebreak",
+			fields: nameFields,
+			cursor: &Cursor{Values: []any{"BMW", "id2"}},
+			want: bson.M{"$or": bson.A{
+				bson.M{"$and": bson.A{bson.M{"name": bson.M{"$gt": "BMW"}}}},
+				bson.M{"$and": bson.A{bson.M{"name": "BMW"}, bson.M{"_id": bson.M{"$gt": "id2"}}}},
+			}},
+		},
+		{
+			name:   "Descending forward includes missing values",
+			fields: []SortField{{Key: "rating", Desc: true}, {Key: "_id"}},
+			cursor: &Cursor{Values: []any{int32(5), "id2"}},
+			want: bson.M{"$or": bson.A{
+				bson.M{"$and": bson.A{bson.M{"$or": bson.A{
+					bson.M{"rating": bson.M{"$lt": int32(5)}},
+					bson.M{"rating": nil},
+				}}}},
+				bson.M{"$and": bson.A{bson.M{"rating": int32(5)}, bson.M{"_id": bson.M{"$gt": "id2"}}}},
+			}},
+		},
+		{
+			name:   "Ascending backward",
+			fields: nameFields,
+			cursor: &Cursor{Values: []any{"BMW", "id2"}, Backward: true},
+			want: bson.M{"$or": bson.A{
+				bson.M{"$and": bson.A{bson.M{"$or": bson.A{
+					bson.M{"name": bson.M{"$lt": "BMW"}},
+					bson.M{"name": nil},
+				}}}},
+				bson.M{"$and": bson.A{bson.M{"name": "BMW"}, bson.M{"$or": bson.A{
+					bson.M{"_id": bson.M{"$lt": "id2"}},
+					bson.M{"_id": nil},
+				}}}},
+			}},
+		},
+		{
+			name:   "Missing value ascending forward",
+			fields: nameFields,
+			cursor: &Cursor{Values: []any{nil, "id2"}},
+			want: bson.M{"$or": bson.A{
+				bson.M{"$and": bson.A{bson.M{"name": bson.M{"$ne": nil}}}},
+				bson.M{"$and": bson.A{bson.M{"name": nil}, bson.M{"_id": bson.M{"$gt": "id2"}}}},
+			}},
+		},
+		{
+			name:   "Missing value descending forward",
+			fields: []SortField{{Key: "rating", Desc: true}, {Key: "_id"}},
+			cursor: &Cursor{Values: []any{nil, "id2"}},
+			want: bson.M{"$or": bson.A{
+				bson.M{"$and": bson.A{bson.M{"rating": nil}, bson.M{"_id": bson.M{"$gt": "id2"}}}},
+			}},
+		},
+		{
+			name:   "Nothing before missing value",
+			fields: []SortField{{Key: "rating", Desc: true}},
+			cursor: &Cursor{Values: []any{nil}},
+			want:   bson.M{"_id": bson.M{"$exists": false}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Condition(tt.fields, tt.cursor))
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		cursor, err := DecodeCursor("", nameFields)
+
+		assert.NoError(t, err)
+		assert.Nil(t, cursor)
+	})
+
+	valid, err := bson.Marshal(cursorMongo{Keys: []string{"name", "_id"}, Values: bson.A{"BMW", "id2"}})
+	assert.NoError(t, err)
+
+	otherKeys, err := bson.Marshal(cursorMongo{Keys: []string{"rating", "_id"}, Values: bson.A{int32(5), "id2"}})
+	assert.NoError(t, err)
+
+	fewerValues, err := bson.Marshal(cursorMongo{Keys: []string{"name", "_id"}, Values: bson.A{"BMW"}})
+	assert.NoError(t, err)
+
+	invalid := []struct {
+		name    string
+		encoded string
+	}{
+		{name: "Not base64", encoded: "!!!not-a-cursor"},
+		{name: "Not bson", encoded: base64.RawURLEncoding.EncodeToString([]byte("hello"))},
+		{name: "Truncated", encoded: base64.RawURLEncoding.EncodeToString(valid[:len(valid)/2])},
+		{name: "Tampered length", encoded: base64.RawURLEncoding.EncodeToString(append([]byte{0xFF, 0xFF, 0xFF, 0x7F}, valid[4:]...))},
+		{name: "Other ordering", encoded: base64.RawURLEncoding.EncodeToString(otherKeys)},
+		{name: "Missing values", encoded: base64.RawURLEncoding.EncodeToString(fewerValues)},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NotPanics(t, func() {
+				cursor, err := DecodeCursor(tt.encoded, nameFields)
+
+				assert.ErrorIs(t, err, domain.ErrInvalidValue)
+				assert.Nil(t, cursor)
+			})
+		})
+	}
+}
+
+func TestPage(t *testing.T) {
+	docs := func(t *testing.T) []bson.Raw {
+		return rawDocs(t,
+			bson.M{"_id": "id1", "name": "Audi"},
+			bson.M{"_id": "id2", "name": "BMW"},
+			bson.M{"_id": "id3", "name": "Tesla"},
+		)
+	}
+
+	t.Run("First page with more documents", func(t *testing.T) {
+		page, pageInfo, err := Page(docs(t), nameFields, 2, 0, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"id1", "id2"}, docIds(t, page))
+		assert.NotEmpty(t, pageInfo.NextCursor)
+		assert.Empty(t, pageInfo.PrevCursor)
+	})
+
+	t.Run("Last page", func(t *testing.T) {
+		page, pageInfo, err := Page(docs(t), nameFields, 3, 0, nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, page, 3)
+		assert.Empty(t, pageInfo.NextCursor)
+		assert.Empty(t, pageInfo.PrevCursor)
+	})
+
+	t.Run("Page at offset has previous one", func(t *testing.T) {
+		_, pageInfo, err := Page(docs(t), nameFields, 3, 5, nil)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, pageInfo.PrevCursor)
+	})
+
+	t.Run("Empty page", func(t *testing.T) {
+		page, pageInfo, err := Page(nil, nameFields, 2, 0, &Cursor{Values: []any{"Z", "id9"}})
+
+		assert.NoError(t, err)
+		assert.Empty(t, page)
+		assert.Equal(t, domain.PageInfo{}, pageInfo)
+	})
+
+	t.Run("Backward page is restored in order", func(t *testing.T) {
+		// fetched backward from the cursor at "Tesla", nearest first
+		fetched := rawDocs(t,
+			bson.M{"_id": "id2", "name": "BMW"},
+			bson.M{"_id": "id1", "name": "Audi"},
+			bson.M{"_id": "id0", "name": "Alfa Romeo"},
+		)
+
+		page, pageInfo, err := Page(fetched, nameFields, 2, 0, &Cursor{Values: []any{"Tesla", "id3"}, Backward: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"id1", "id2"}, docIds(t, page))
+		assert.NotEmpty(t, pageInfo.NextCursor)
+		assert.NotEmpty(t, pageInfo.PrevCursor)
+	})
+
+	t.Run("Backward page reaching the start", func(t *testing.T) {
+		fetched := rawDocs(t, bson.M{"_id": "id1", "name": "Audi"})
+
+		_, pageInfo, err := Page(fetched, nameFields, 2, 0, &Cursor{Values: []any{"BMW", "id2"}, Backward: true})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, pageInfo.NextCursor)
+		assert.Empty(t, pageInfo.PrevCursor)
+	})
+
+	t.Run("Cursors round trip", func(t *testing.T) {
+		page, pageInfo, err := Page(docs(t), nameFields, 2, 0, &Cursor{Values: []any{"Alfa Romeo", "id0"}})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"id1", "id2"}, docIds(t, page))
+
+		next, err := DecodeCursor(pageInfo.NextCursor, nameFields)
+		assert.NoError(t, err)
+		assert.Equal(t, &Cursor{Values: []any{"BMW", "id2"}}, next)
+
+		prev, err := DecodeCursor(pageInfo.PrevCursor, nameFields)
+		assert.NoError(t, err)
+		assert.Equal(t, &Cursor{Values: []any{"Audi", "id1"}, Backward: true}, prev)
+	})
+
+	t.Run("Missing sort value is kept as nil", func(t *testing.T) {
+		fields := []SortField{{Key: "rating", Desc: true}, {Key: "_id"}}
+		fetched := rawDocs(t, bson.M{"_id": "id1"}, bson.M{"_id": "id2"})
+
+		_, pageInfo, err := Page(fetched, fields, 1, 0, nil)
+		assert.NoError(t, err)
+
+		next, err := DecodeCursor(pageInfo.NextCursor, fields)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{nil, "id1"}, next.Values)
+	})
+}
@@ -0,0 +1,214 @@
+package photo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PhotoRepositoryMongo struct {
+	photosColl *mongo.Collection
+}
+
+type photoMongo struct {
+	Id          string    `bson:"_id"`
+	ObjectId    string    `bson:"object_id"`
+	Key         string    `bson:"key"`
+	ContentType string    `bson:"content_type"`
+	Size        int64     `bson:"size"`
+	Width       int       `bson:"width"`
+	Height      int       `bson:"height"`
+	Position    int       `bson:"position"`
+	IsCover     bool      `bson:"is_cover"`
+	CreatedAt   time.Time `bson:"created_at"`
+}
+
+func NewPhotoRepositoryMongo(client *mongo.Client) *PhotoRepositoryMongo {
+	return &PhotoRepositoryMongo{
+		photosColl: client.Database("database").Collection("photos"),
+	}
+}
+
+// GetPhotosByObjectIds gives photos of the objects in their gallery order.
+func (repo *PhotoRepositoryMongo) GetPhotosByObjectIds(
+	ctx context.Context,
+	objectIds []string,
+) ([]domain.Photo, error) {
+	cur, err := repo.photosColl.Find(
+		ctx,
+		bson.M{"object_id": bson.M{"$in": objectIds}},
+		options.Find().SetSort(bson.D{{Key: "object_id", Value: 1}, {Key: "position", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetch photos from mongo error: %w", err)
+	}
+
+	var photosMongo []photoMongo
+	if err := cur.All(ctx, &photosMongo); err != nil {
+		return nil, fmt.Errorf("decode mongo result error %w", err)
+	}
+
+	photos := make([]domain.Photo, len(photosMongo))
+	for i, pm := range photosMongo {
+		photos[i] = toDomainPhoto(pm)
+	}
+
+	return photos, nil
+}
+
+func (repo *PhotoRepositoryMongo) GetPhotoById(ctx context.Context, id string) (domain.Photo, error) {
+	var pm photoMongo
+	if err := repo.photosColl.FindOne(ctx, bson.M{"_id": id}).Decode(&pm); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Photo{}, fmt.Errorf("photo %w", domain.ErrNotFound)
+		}
+
+		return domain.Photo{}, fmt.Errorf("fetch photo from mongo error: %w", err)
+	}
+
+	return toDomainPhoto(pm), nil
+}
+
+// GetPhotoKeys gives keys of originals of all photos.
+func (repo *PhotoRepositoryMongo) GetPhotoKeys(ctx context.Context) ([]string, error) {
+	cur, err := repo.photosColl.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"key": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("fetch photos from mongo error: %w", err)
+	}
+
+	var photosMongo []photoMongo
+	if err := cur.All(ctx, &photosMongo); err != nil {
+		return nil, fmt.Errorf("decode mongo result error %w", err)
+	}
+
+	keys := make([]string, len(photosMongo))
+	for i, pm := range photosMongo {
+		keys[i] = pm.Key
+	}
+
+	return keys, nil
+}
+
+func (repo *PhotoRepositoryMongo) CreatePhoto(ctx context.Context, photo domain.Photo) error {
+	if _, err := repo.photosColl.InsertOne(ctx, toPhotoMongo(photo)); err != nil {
+		return fmt.Errorf("insert to mongo error: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePhotos saves position and cover flag of the photos in one batch.
+func (repo *PhotoRepositoryMongo) UpdatePhotos(ctx context.Context, photos []domain.Photo) error {
+	if len(photos) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, len(photos))
+	for i, photo := range photos {
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": photo.Id}).
+			SetUpdate(bson.M{"$set": bson.M{"position": photo.Position, "is_cover": photo.IsCover}})
+	}
+
+	if _, err := repo.photosColl.BulkWrite(ctx, models); err != nil {
+		return fmt.Errorf("update at mongo error: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *PhotoRepositoryMongo) DeletePhoto(ctx context.Context, id string) error {
+	res, err := repo.photosColl.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("delete from mongo error: %w", err)
+	}
+
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("photo %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (repo *PhotoRepositoryMongo) DeletePhotosByObjectIds(ctx context.Context, objectIds []string) error {
+	_, err := repo.photosColl.DeleteMany(ctx, bson.M{"object_id": bson.M{"$in": objectIds}})
+	if err != nil {
+		return fmt.Errorf("delete from mongo error: %w", err)
+	}
+
+	return nil
+}
+
+// GetAllPhotos returns all photos of the database, e.g. to back them up.
+func (repo *PhotoRepositoryMongo) GetAllPhotos(ctx context.Context) ([]domain.Photo, error) {
+	cur, err := repo.photosColl.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("fetch photos from mongo error: %w", err)
+	}
+
+	photos := make([]domain.Photo, 0)
+	for cur.Next(ctx) {
+		var pm photoMongo
+		if err := cur.Decode(&pm); err != nil {
+			return nil, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		photos = append(photos, toDomainPhoto(pm))
+	}
+
+	return photos, nil
+}
+
+// CreatePhotos inserts photos in one batch, e.g. to restore them from a backup.
+func (repo *PhotoRepositoryMongo) CreatePhotos(ctx context.Context, photos []domain.Photo) error {
+	if len(photos) == 0 {
+		return nil
+	}
+
+	documents := make([]any, len(photos))
+	for i, photo := range photos {
+		documents[i] = toPhotoMongo(photo)
+	}
+
+	if _, err := repo.photosColl.InsertMany(ctx, documents); err != nil {
+		return fmt.Errorf("insert to mongo error: %w", err)
+	}
+
+	return nil
+}
+
+func toDomainPhoto(pm photoMongo) domain.Photo {
+	return domain.Photo{
+		Id:          pm.Id,
+		ObjectId:    pm.ObjectId,
+		Key:         pm.Key,
+		ContentType: pm.ContentType,
+		Size:        pm.Size,
+		Width:       pm.Width,
+		Height:      pm.Height,
+		Position:    pm.Position,
+		IsCover:     pm.IsCover,
+		CreatedAt:   pm.CreatedAt,
+	}
+}
+
+func toPhotoMongo(photo domain.Photo) photoMongo {
+	return photoMongo{
+		Id:          photo.Id,
+		ObjectId:    photo.ObjectId,
+		Key:         photo.Key,
+		ContentType: photo.ContentType,
+		Size:        photo.Size,
+		Width:       photo.Width,
+		Height:      photo.Height,
+		Position:    photo.Position,
+		IsCover:     photo.IsCover,
+		CreatedAt:   photo.CreatedAt,
+	}
+}
@@ -0,0 +1,270 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/adapters/repositories/pagination"
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TemplateRepositoryMongo struct {
+	templatesColl *mongo.Collection
+}
+
+type templateMongo struct {
+	Id          string                `bson:"_id"`
+	Name        string                `bson:"name"`
+	Description string                `bson:"description"`
+	Options     []templateOptionMongo `bson:"options"`
+	BuiltIn     bool                  `bson:"built_in"`
+	CreatedAt   time.Time             `bson:"created_at"`
+}
+
+type templateOptionMongo struct {
+	Name       string   `bson:"name"`
+	Type       string   `bson:"type"`
+	EnumValues []string `bson:"enum_values,omitempty"`
+	Currency   string   `bson:"currency,omitempty"`
+	Weight     float64  `bson:"weight"`
+	Direction  string   `bson:"direction"`
+}
+
+func NewTemplateRepositoryMongo(client *mongo.Client) *TemplateRepositoryMongo {
+	return &TemplateRepositoryMongo{
+		templatesColl: client.Database("database").Collection("templates"),
+	}
+}
+
+func (repo *TemplateRepositoryMongo) GetTemplates(
+	ctx context.Context,
+	filter domain.TemplateFilter,
+) ([]domain.Template, domain.PageInfo, error) {
+	sortFields := pagination.SortFields(filter.OrderBy)
+
+	cursor, err := pagination.DecodeCursor(filter.Cursor, sortFields)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+
+	condition := bson.M{}
+
+	if filter.Name != "" {
+		condition["name"] = bson.M{
+			"$regex":   regexp.QuoteMeta(filter.Name),
+			"$options": "i",
+		}
+	}
+
+	total, err := repo.templatesColl.CountDocuments(ctx, condition)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("count templates at mongo error: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(pagination.Sort(sortFields, cursor)).
+		SetLimit(int64(filter.Limit + 1))
+
+	if cursor != nil {
+		condition = bson.M{"$and": bson.A{condition, pagination.Condition(sortFields, cursor)}}
+	} else {
+		opts.SetSkip(int64(filter.Offset))
+	}
+
+	cur, err := repo.templatesColl.Find(ctx, condition, opts)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("fetch templates from mongo error: %w", err)
+	}
+
+	var docs []bson.Raw
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("fetch templates from mongo error: %w", err)
+	}
+
+	docs, pageInfo, err := pagination.Page(docs, sortFields, filter.Limit, filter.Offset, cursor)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+
+	pageInfo.Total = total
+
+	templates := make([]domain.Template, 0, len(docs))
+	for _, doc := range docs {
+		var tm templateMongo
+		if err := bson.Unmarshal(doc, &tm); err != nil {
+			return nil, domain.PageInfo{}, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		templates = append(templates, toDomainTemplate(tm))
+	}
+
+	return templates, pageInfo, nil
+}
+
+func (repo *TemplateRepositoryMongo) GetTemplateById(
+	ctx context.Context,
+	id string,
+) (domain.Template, error) {
+	res := repo.templatesColl.FindOne(ctx, bson.M{"_id": id})
+	if res.Err() != nil {
+		if errors.Is(res.Err(), mongo.ErrNoDocuments) {
+			return domain.Template{}, fmt.Errorf("template %w", domain.ErrNotFound)
+		}
+
+		return domain.Template{}, fmt.Errorf("get template from mongo error %w", res.Err())
+	}
+
+	var tm templateMongo
+	if err := res.Decode(&tm); err != nil {
+		return domain.Template{}, fmt.Errorf("decode mongo result error %w", err)
+	}
+
+	return toDomainTemplate(tm), nil
+}
+
+func (repo *TemplateRepositoryMongo) CreateTemplate(
+	ctx context.Context,
+	template domain.Template,
+) error {
+	_, err := repo.templatesColl.InsertOne(ctx, toTemplateMongo(template))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("template with name '%s' %w", template.Name, domain.ErrAlreadyExists)
+		}
+
+		return fmt.Errorf("insert to mongo error: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *TemplateRepositoryMongo) UpdateTemplate(
+	ctx context.Context,
+	template domain.Template,
+) error {
+	res, err := repo.templatesColl.UpdateOne(
+		ctx,
+		bson.M{"_id": template.Id},
+		bson.M{"$set": bson.M{
+			"name":        template.Name,
+			"description": template.Description,
+			"options":     toTemplateOptionsMongo(template.Options),
+		}},
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("template with name '%s' %w", template.Name, domain.ErrAlreadyExists)
+		}
+
+		return fmt.Errorf("update at mongo error: %w", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("template %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+// SaveBuiltInTemplate creates the built-in template or replaces the stored one keeping
+// its creation time, so templates shipped with a new release overwrite the old ones.
+func (repo *TemplateRepositoryMongo) SaveBuiltInTemplate(
+	ctx context.Context,
+	template domain.Template,
+) error {
+	_, err := repo.templatesColl.UpdateOne(
+		ctx,
+		bson.M{"_id": template.Id},
+		bson.M{
+			"$set": bson.M{
+				"name":        template.Name,
+				"description": template.Description,
+				"options":     toTemplateOptionsMongo(template.Options),
+				"built_in":    true,
+			},
+			"$setOnInsert": bson.M{"created_at": template.CreatedAt},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("template with name '%s' %w", template.Name, domain.ErrAlreadyExists)
+		}
+
+		return fmt.Errorf("upsert at mongo error: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *TemplateRepositoryMongo) DeleteTemplate(
+	ctx context.Context,
+	id string,
+) error {
+	res, err := repo.templatesColl.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("delete from mongo error: %w", err)
+	}
+
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("template %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+func toTemplateMongo(domainTemplate domain.Template) templateMongo {
+	return templateMongo{
+		Id:          domainTemplate.Id,
+		Name:        domainTemplate.Name,
+		Description: domainTemplate.Description,
+		Options:     toTemplateOptionsMongo(domainTemplate.Options),
+		BuiltIn:     domainTemplate.BuiltIn,
+		CreatedAt:   domainTemplate.CreatedAt,
+	}
+}
+
+func toTemplateOptionsMongo(domainOptions []domain.TemplateOption) []templateOptionMongo {
+	options := make([]templateOptionMongo, len(domainOptions))
+	for i, o := range domainOptions {
+		options[i] = templateOptionMongo{
+			Name:       o.Name,
+			Type:       string(o.Type),
+			EnumValues: o.EnumValues,
+			Currency:   o.Currency,
+			Weight:     o.Weight,
+			Direction:  string(o.Direction),
+		}
+	}
+
+	return options
+}
+
+func toDomainTemplate(tm templateMongo) domain.Template {
+	options := make([]domain.TemplateOption, len(tm.Options))
+	for i, om := range tm.Options {
+		options[i] = domain.TemplateOption{
+			Name:       om.Name,
+			Type:       domain.CustomOptionType(om.Type),
+			EnumValues: om.EnumValues,
+			Currency:   om.Currency,
+			Weight:     om.Weight,
+			Direction:  domain.ScoreDirection(om.Direction),
+		}
+	}
+
+	return domain.Template{
+		Id:          tm.Id,
+		Name:        tm.Name,
+		Description: tm.Description,
+		Options:     options,
+		BuiltIn:     tm.BuiltIn,
+		CreatedAt:   tm.CreatedAt,
+	}
+}
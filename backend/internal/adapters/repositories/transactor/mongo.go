@@ -0,0 +1,37 @@
+package transactor
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type TransactorMongo struct {
+	client *mongo.Client
+}
+
+func NewTransactorMongo(client *mongo.Client) *TransactorMongo {
+	return &TransactorMongo{client: client}
+}
+
+// WithinTransaction runs fn in a session transaction. Repositories pick the session up
+// from the context passed to fn, so all their calls made with it are committed or aborted
+// together. Called inside an already running transaction, fn joins it.
+func (t *TransactorMongo) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	session, err := t.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("start mongo session error: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (any, error) {
+		return nil, fn(sessCtx)
+	})
+
+	return err
+}
@@ -0,0 +1,142 @@
+package trash
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/adapters/repositories/pagination"
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TrashRepositoryMongo lists comparisons and objects in the trash together,
+// both are kept in their own collections.
+type TrashRepositoryMongo struct {
+	comparisonsColl *mongo.Collection
+	objectsColl     *mongo.Collection
+}
+
+type trashItemMongo struct {
+	Id           string    `bson:"_id"`
+	Type         string    `bson:"type"`
+	Name         string    `bson:"name"`
+	ComparisonId string    `bson:"comparison_id"`
+	DeletedAt    time.Time `bson:"deleted_at"`
+}
+
+// trashSortFields orders the trash from recently deleted items.
+var trashSortFields = pagination.SortFields([]domain.SortKey{{Field: "deleted_at", Desc: true}})
+
+func NewTrashRepositoryMongo(client *mongo.Client) *TrashRepositoryMongo {
+	db := client.Database("database")
+
+	return &TrashRepositoryMongo{
+		comparisonsColl: db.Collection("comparisons"),
+		objectsColl:     db.Collection("objects"),
+	}
+}
+
+// GetTrashItems gives a page of deleted comparisons and objects deleted on their own,
+// objects deleted with their comparison are a part of it.
+func (repo *TrashRepositoryMongo) GetTrashItems(
+	ctx context.Context,
+	filter domain.TrashFilter,
+) ([]domain.TrashItem, domain.PageInfo, error) {
+	cursor, err := pagination.DecodeCursor(filter.Cursor, trashSortFields)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"deleted_at": bson.M{"$ne": nil}}}},
+		{{Key: "$project", Value: bson.M{
+			"type":       bson.M{"$literal": string(domain.TrashItemTypeComparison)},
+			"name":       1,
+			"deleted_at": 1,
+		}}},
+		{{Key: "$unionWith", Value: bson.M{
+			"coll": repo.objectsColl.Name(),
+			"pipeline": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{
+					"deleted_at":              bson.M{"$ne": nil},
+					"deleted_with_comparison": bson.M{"$ne": true},
+				}}},
+				{{Key: "$project", Value: bson.M{
+					"type":          bson.M{"$literal": string(domain.TrashItemTypeObject)},
+					"name":          1,
+					"comparison_id": 1,
+					"deleted_at":    1,
+				}}},
+			},
+		}}},
+	}
+
+	countPipeline := append(slices.Clone(pipeline), bson.D{{Key: "$count", Value: "total"}})
+
+	countCur, err := repo.comparisonsColl.Aggregate(ctx, countPipeline)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("count trash items at mongo error: %w", err)
+	}
+
+	var counts []struct {
+		Total int64 `bson:"total"`
+	}
+	if err := countCur.All(ctx, &counts); err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("count trash items at mongo error: %w", err)
+	}
+
+	var total int64
+	if len(counts) != 0 {
+		total = counts[0].Total
+	}
+
+	if cursor != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: pagination.Condition(trashSortFields, cursor)}})
+	}
+
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: pagination.Sort(trashSortFields, cursor)}})
+
+	if cursor == nil {
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: int64(filter.Offset)}})
+	}
+
+	pipeline = append(pipeline, bson.D{{Key: "$limit", Value: int64(filter.Limit + 1)}})
+
+	cur, err := repo.comparisonsColl.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("aggregate trash items at mongo error: %w", err)
+	}
+
+	var docs []bson.Raw
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("aggregate trash items at mongo error: %w", err)
+	}
+
+	docs, pageInfo, err := pagination.Page(docs, trashSortFields, filter.Limit, filter.Offset, cursor)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+
+	pageInfo.Total = total
+
+	items := make([]domain.TrashItem, 0, len(docs))
+	for _, doc := range docs {
+		var im trashItemMongo
+		if err := bson.Unmarshal(doc, &im); err != nil {
+			return nil, domain.PageInfo{}, fmt.Errorf("decode mongo result error %w", err)
+		}
+
+		items = append(items, domain.TrashItem{
+			Type:         domain.TrashItemType(im.Type),
+			Id:           im.Id,
+			Name:         im.Name,
+			ComparisonId: im.ComparisonId,
+			DeletedAt:    im.DeletedAt,
+		})
+	}
+
+	return items, pageInfo, nil
+}
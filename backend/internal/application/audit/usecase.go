@@ -0,0 +1,34 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+)
+
+type AuditUsecase struct {
+	repo AuditRepository
+}
+
+type AuditRepository interface {
+	GetAuditEvents(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, domain.PageInfo, error)
+}
+
+func NewAuditUsecase(repo AuditRepository) *AuditUsecase {
+	return &AuditUsecase{repo: repo}
+}
+
+// GetHistory gives changes of the entity from the latest ones. History of deleted
+// entities is kept, entities changed before the audit log was introduced have none.
+func (uc *AuditUsecase) GetHistory(
+	ctx context.Context,
+	filter domain.AuditFilter,
+) ([]domain.AuditEvent, domain.PageInfo, error) {
+	events, pageInfo, err := uc.repo.GetAuditEvents(ctx, filter)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("failed to get audit events - %w", err)
+	}
+
+	return events, pageInfo, nil
+}
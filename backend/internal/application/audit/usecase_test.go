@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/Unlites/comparison_center/backend/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHistory(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := mocks.NewAuditRepositoryMock()
+		uc := NewAuditUsecase(repo)
+
+		ctx := context.Background()
+
+		filter := domain.AuditFilter{
+			Limit:    10,
+			Entity:   domain.AuditEntityObject,
+			EntityId: "231934sadas9123deqw",
+		}
+
+		returnedEvents := []domain.AuditEvent{
+			{
+				Id:        "5435fdgdfg3454dfgd",
+				Entity:    domain.AuditEntityObject,
+				EntityId:  "231934sadas9123deqw",
+				Action:    domain.AuditActionUpdate,
+				CreatedAt: time.Now(),
+				Changes:   []domain.FieldChange{{Field: "rating", Before: 8, After: 9}},
+			},
+		}
+
+		repo.On("GetAuditEvents", ctx, filter).Return(returnedEvents, domain.PageInfo{Total: 1}, nil)
+
+		events, pageInfo, err := uc.GetHistory(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, returnedEvents, events)
+		assert.Equal(t, int64(1), pageInfo.Total)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		repo := mocks.NewAuditRepositoryMock()
+		uc := NewAuditUsecase(repo)
+
+		ctx := context.Background()
+
+		filter := domain.AuditFilter{Limit: 10, Entity: domain.AuditEntityComparison, EntityId: "85434230werhuhi123912304"}
+
+		repo.On("GetAuditEvents", ctx, filter).Return(nil, nil, assert.AnError)
+
+		events, _, err := uc.GetHistory(ctx, filter)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, events)
+	})
+}
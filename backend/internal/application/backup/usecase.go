@@ -0,0 +1,480 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+)
+
+type BackupUsecase struct {
+	folderRepo     FolderRepository
+	comparisonRepo ComparisonRepository
+	custOptRepo    CustomOptionRepository
+	objRepo        ObjectRepository
+	custOptObjRepo ObjectCustomOptionRepository
+	photoRepo      PhotoRepository
+	photoStore     PhotoStore
+	transactor     Transactor
+	generator      IdGenerator
+}
+
+type FolderRepository interface {
+	GetFolders(ctx context.Context) ([]domain.Folder, error)
+	CreateFolders(ctx context.Context, folders []domain.Folder) error
+}
+
+type ComparisonRepository interface {
+	GetAllComparisons(ctx context.Context) ([]domain.Comparison, error)
+	CreateComparisons(ctx context.Context, comparisons []domain.Comparison) error
+}
+
+type CustomOptionRepository interface {
+	GetAllCustomOptions(ctx context.Context) ([]domain.CustomOption, error)
+	CreateCustomOptions(ctx context.Context, customOptions []domain.CustomOption) error
+}
+
+type ObjectRepository interface {
+	GetAllObjects(ctx context.Context) ([]domain.Object, error)
+	CreateObjects(ctx context.Context, objects []domain.Object) error
+}
+
+type ObjectCustomOptionRepository interface {
+	GetAllObjectCustomOptions(ctx context.Context) ([]domain.ObjectCustomOption, error)
+	AddObjectCustomOptions(ctx context.Context, objCustomOptions []domain.ObjectCustomOption) error
+}
+
+type PhotoRepository interface {
+	GetAllPhotos(ctx context.Context) ([]domain.Photo, error)
+	CreatePhotos(ctx context.Context, photos []domain.Photo) error
+}
+
+type PhotoStore interface {
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadSeekCloser, domain.PhotoInfo, error)
+}
+
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type IdGenerator interface {
+	GenerateId() string
+}
+
+// ArchiveWriter writes the backup followed by photo files.
+type ArchiveWriter interface {
+	WriteBackup(manifest domain.BackupManifest, backup domain.Backup) error
+	WritePhoto(name string, size int64, content io.Reader) error
+}
+
+// ArchiveReader reads the backup followed by photo files,
+// NextPhoto returns io.EOF when there are no more files.
+type ArchiveReader interface {
+	ReadBackup() (domain.BackupManifest, domain.Backup, error)
+	NextPhoto() (string, int64, io.Reader, error)
+}
+
+func NewBackupUsecase(
+	folderRepo FolderRepository,
+	comparisonRepo ComparisonRepository,
+	custOptRepo CustomOptionRepository,
+	objRepo ObjectRepository,
+	custOptObjRepo ObjectCustomOptionRepository,
+	photoRepo PhotoRepository,
+	photoStore PhotoStore,
+	transactor Transactor,
+	generator IdGenerator,
+) *BackupUsecase {
+	return &BackupUsecase{
+		folderRepo:     folderRepo,
+		comparisonRepo: comparisonRepo,
+		custOptRepo:    custOptRepo,
+		objRepo:        objRepo,
+		custOptObjRepo: custOptObjRepo,
+		photoRepo:      photoRepo,
+		photoStore:     photoStore,
+		transactor:     transactor,
+		generator:      generator,
+	}
+}
+
+// Dump writes all entities and photo files to the archive, returning its manifest
+// and the number of written photo files.
+func (uc *BackupUsecase) Dump(ctx context.Context, archive ArchiveWriter) (domain.BackupManifest, int, error) {
+	var backup domain.Backup
+
+	// entities are read within one transaction to get a consistent snapshot of them
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		backup, err = uc.readBackup(ctx)
+		return err
+	})
+	if err != nil {
+		return domain.BackupManifest{}, 0, err
+	}
+
+	manifest := domain.NewBackupManifest(backup)
+	if err := archive.WriteBackup(manifest, backup); err != nil {
+		return domain.BackupManifest{}, 0, fmt.Errorf("failed to write backup - %w", err)
+	}
+
+	photoFiles := 0
+	for _, photo := range backup.Photos {
+		for _, size := range domain.ProvidedPhotoSizes {
+			written, err := uc.dumpPhotoFile(ctx, archive, domain.PhotoVariantKey(photo.Key, size))
+			if err != nil {
+				return domain.BackupManifest{}, 0, err
+			}
+
+			if written {
+				photoFiles++
+			}
+		}
+	}
+
+	return manifest, photoFiles, nil
+}
+
+func (uc *BackupUsecase) readBackup(ctx context.Context) (domain.Backup, error) {
+	folders, err := uc.folderRepo.GetFolders(ctx)
+	if err != nil {
+		return domain.Backup{}, fmt.Errorf("failed to get folders - %w", err)
+	}
+
+	comparisons, err := uc.comparisonRepo.GetAllComparisons(ctx)
+	if err != nil {
+		return domain.Backup{}, fmt.Errorf("failed to get comparisons - %w", err)
+	}
+
+	customOptions, err := uc.custOptRepo.GetAllCustomOptions(ctx)
+	if err != nil {
+		return domain.Backup{}, fmt.Errorf("failed to get custom options - %w", err)
+	}
+
+	objects, err := uc.objRepo.GetAllObjects(ctx)
+	if err != nil {
+		return domain.Backup{}, fmt.Errorf("failed to get objects - %w", err)
+	}
+
+	objCustomOptions, err := uc.custOptObjRepo.GetAllObjectCustomOptions(ctx)
+	if err != nil {
+		return domain.Backup{}, fmt.Errorf("failed to get object custom options - %w", err)
+	}
+
+	photos, err := uc.photoRepo.GetAllPhotos(ctx)
+	if err != nil {
+		return domain.Backup{}, fmt.Errorf("failed to get photos - %w", err)
+	}
+
+	// objects in the trash are not backed up, so are their custom option values and photos
+	liveObjects := make(map[string]bool, len(objects))
+	for _, obj := range objects {
+		liveObjects[obj.Id] = true
+	}
+
+	objCustomOptions = slices.DeleteFunc(objCustomOptions, func(oco domain.ObjectCustomOption) bool {
+		return !liveObjects[oco.ObjectId]
+	})
+
+	photos = slices.DeleteFunc(photos, func(photo domain.Photo) bool {
+		return !liveObjects[photo.ObjectId]
+	})
+
+	return domain.Backup{
+		Folders:             folders,
+		Comparisons:         comparisons,
+		CustomOptions:       customOptions,
+		Objects:             objects,
+		ObjectCustomOptions: objCustomOptions,
+		Photos:              photos,
+	}, nil
+}
+
+// dumpPhotoFile writes the photo file to the archive. Photos uploaded before sizes
+// were introduced have no files of other sizes, these are skipped.
+func (uc *BackupUsecase) dumpPhotoFile(ctx context.Context, archive ArchiveWriter, key string) (bool, error) {
+	content, info, err := uc.photoStore.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to get photo file - %w", err)
+	}
+	defer content.Close()
+
+	if err := archive.WritePhoto(path.Base(key), info.Size, content); err != nil {
+		return false, fmt.Errorf("failed to write photo file - %w", err)
+	}
+
+	return true, nil
+}
+
+// Restore puts entities and photo files of the archive into an empty database.
+// With remapIds all entities get new ids, so the same backup can be restored next to
+// data restored from it before, e.g. to the same photo storage. Returns the manifest
+// of the archive and the number of restored photo files.
+func (uc *BackupUsecase) Restore(
+	ctx context.Context,
+	archive ArchiveReader,
+	remapIds bool,
+) (domain.BackupManifest, int, error) {
+	manifest, backup, err := archive.ReadBackup()
+	if err != nil {
+		return domain.BackupManifest{}, 0, fmt.Errorf("failed to read backup - %w", err)
+	}
+
+	if manifest.Version != domain.BackupVersion {
+		return domain.BackupManifest{}, 0, fmt.Errorf(
+			"backup version %d is not supported, must be %d - %w",
+			manifest.Version,
+			domain.BackupVersion,
+			domain.ErrInvalidValue,
+		)
+	}
+
+	if err := uc.checkEmpty(ctx); err != nil {
+		return domain.BackupManifest{}, 0, err
+	}
+
+	parseObjectCustomOptions(backup)
+
+	// archive files are named after keys they had, keys of photos change with their ids
+	files := make(map[string]photoFile, len(backup.Photos)*len(domain.ProvidedPhotoSizes))
+	if remapIds {
+		backup = uc.remapIds(backup, files)
+	} else {
+		for _, photo := range backup.Photos {
+			for _, size := range domain.ProvidedPhotoSizes {
+				key := domain.PhotoVariantKey(photo.Key, size)
+				files[path.Base(key)] = photoFile{key: key, contentType: photo.ContentType}
+			}
+		}
+	}
+
+	// files are put first, if entities fail to be restored then, they are left
+	// without references and are removed by the photo garbage collector
+	photoFiles := 0
+	for {
+		name, size, content, err := archive.NextPhoto()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return domain.BackupManifest{}, 0, fmt.Errorf("failed to read photo file - %w", err)
+		}
+
+		file, ok := files[name]
+		if !ok {
+			continue
+		}
+
+		if err := uc.photoStore.Put(ctx, file.key, content, size, file.contentType); err != nil {
+			return domain.BackupManifest{}, 0, fmt.Errorf("failed to put photo file - %w", err)
+		}
+
+		photoFiles++
+	}
+
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.custOptRepo.CreateCustomOptions(ctx, backup.CustomOptions); err != nil {
+			return fmt.Errorf("failed to restore custom options - %w", err)
+		}
+
+		if err := uc.folderRepo.CreateFolders(ctx, backup.Folders); err != nil {
+			return fmt.Errorf("failed to restore folders - %w", err)
+		}
+
+		if err := uc.comparisonRepo.CreateComparisons(ctx, backup.Comparisons); err != nil {
+			return fmt.Errorf("failed to restore comparisons - %w", err)
+		}
+
+		if err := uc.objRepo.CreateObjects(ctx, withObjectCustomOptions(backup)); err != nil {
+			return fmt.Errorf("failed to restore objects - %w", err)
+		}
+
+		if err := uc.custOptObjRepo.AddObjectCustomOptions(ctx, backup.ObjectCustomOptions); err != nil {
+			return fmt.Errorf("failed to restore object custom options - %w", err)
+		}
+
+		if err := uc.photoRepo.CreatePhotos(ctx, backup.Photos); err != nil {
+			return fmt.Errorf("failed to restore photos - %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.BackupManifest{}, 0, err
+	}
+
+	return manifest, photoFiles, nil
+}
+
+type photoFile struct {
+	key         string
+	contentType string
+}
+
+// checkEmpty makes sure that restored entities do not mix with existing ones.
+func (uc *BackupUsecase) checkEmpty(ctx context.Context) error {
+	comparisons, err := uc.comparisonRepo.GetAllComparisons(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get comparisons - %w", err)
+	}
+
+	customOptions, err := uc.custOptRepo.GetAllCustomOptions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get custom options - %w", err)
+	}
+
+	folders, err := uc.folderRepo.GetFolders(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get folders - %w", err)
+	}
+
+	if len(comparisons) != 0 || len(customOptions) != 0 || len(folders) != 0 {
+		return fmt.Errorf("backup can be restored only into an empty database - %w", domain.ErrConflict)
+	}
+
+	return nil
+}
+
+// parseObjectCustomOptions restores typed values from their canonical representation kept
+// in the backup. Values that can not be parsed, e.g. ones stored before typed options were
+// introduced, are restored as text, as they were stored.
+func parseObjectCustomOptions(backup domain.Backup) {
+	customOptions := make(map[string]domain.CustomOption, len(backup.CustomOptions))
+	for _, co := range backup.CustomOptions {
+		customOptions[co.Id] = co
+	}
+
+	for i, oco := range backup.ObjectCustomOptions {
+		co, ok := customOptions[oco.CustomOptionId]
+		if !ok {
+			continue
+		}
+
+		if typedValue, err := co.ParseValue(oco.Value); err == nil {
+			backup.ObjectCustomOptions[i].TypedValue = typedValue
+		}
+	}
+}
+
+// withObjectCustomOptions gives objects of the backup along with their custom options,
+// so values of the options are kept in objects for the full-text search.
+func withObjectCustomOptions(backup domain.Backup) []domain.Object {
+	optionsByObjectId := make(map[string][]domain.ObjectCustomOption, len(backup.Objects))
+	for _, oco := range backup.ObjectCustomOptions {
+		optionsByObjectId[oco.ObjectId] = append(optionsByObjectId[oco.ObjectId], oco)
+	}
+
+	objects := make([]domain.Object, len(backup.Objects))
+	for i, obj := range backup.Objects {
+		obj.ObjectCustomOptions = optionsByObjectId[obj.Id]
+		objects[i] = obj
+	}
+
+	return objects
+}
+
+// remapIds gives new ids to all entities of the backup and updates references to them,
+// references to entities missing in the backup are kept as they are. Keys of photos follow
+// their new ids, files maps archived files to these keys.
+func (uc *BackupUsecase) remapIds(backup domain.Backup, files map[string]photoFile) domain.Backup {
+	ids := make(map[string]string)
+	newId := func(id string) string {
+		ids[id] = uc.generator.GenerateId()
+		return ids[id]
+	}
+	ref := func(id string) string {
+		if mapped, ok := ids[id]; ok {
+			return mapped
+		}
+
+		return id
+	}
+
+	customOptions := make([]domain.CustomOption, len(backup.CustomOptions))
+	for i, co := range backup.CustomOptions {
+		co.Id = newId(co.Id)
+		customOptions[i] = co
+	}
+
+	// every folder gets its id before parents are referred to, as a folder may go before its parent
+	folders := make([]domain.Folder, len(backup.Folders))
+	for i, f := range backup.Folders {
+		f.Id = newId(f.Id)
+		folders[i] = f
+	}
+
+	for i := range folders {
+		folders[i].ParentId = ref(folders[i].ParentId)
+	}
+
+	comparisons := make([]domain.Comparison, len(backup.Comparisons))
+	for i, c := range backup.Comparisons {
+		c.Id = newId(c.Id)
+		c.FolderId = ref(c.FolderId)
+
+		customOptionIds := make([]string, len(c.CustomOptionIds))
+		for j, id := range c.CustomOptionIds {
+			customOptionIds[j] = ref(id)
+		}
+		c.CustomOptionIds = customOptionIds
+
+		optionWeights := make([]domain.OptionWeight, len(c.OptionWeights))
+		for j, ow := range c.OptionWeights {
+			ow.CustomOptionId = ref(ow.CustomOptionId)
+			optionWeights[j] = ow
+		}
+		c.OptionWeights = optionWeights
+
+		comparisons[i] = c
+	}
+
+	objects := make([]domain.Object, len(backup.Objects))
+	for i, obj := range backup.Objects {
+		obj.Id = newId(obj.Id)
+		obj.ComparisonId = ref(obj.ComparisonId)
+		objects[i] = obj
+	}
+
+	objCustomOptions := make([]domain.ObjectCustomOption, len(backup.ObjectCustomOptions))
+	for i, oco := range backup.ObjectCustomOptions {
+		oco.ObjectId = ref(oco.ObjectId)
+		oco.CustomOptionId = ref(oco.CustomOptionId)
+		objCustomOptions[i] = oco
+	}
+
+	photos := make([]domain.Photo, len(backup.Photos))
+	for i, photo := range backup.Photos {
+		oldKey := photo.Key
+
+		photo.Id = newId(photo.Id)
+		photo.ObjectId = ref(photo.ObjectId)
+		photo.Key = photo.Id + path.Ext(oldKey)
+		photos[i] = photo
+
+		for _, size := range domain.ProvidedPhotoSizes {
+			files[path.Base(domain.PhotoVariantKey(oldKey, size))] = photoFile{
+				key:         domain.PhotoVariantKey(photo.Key, size),
+				contentType: photo.ContentType,
+			}
+		}
+	}
+
+	return domain.Backup{
+		Folders:             folders,
+		Comparisons:         comparisons,
+		CustomOptions:       customOptions,
+		Objects:             objects,
+		ObjectCustomOptions: objCustomOptions,
+		Photos:              photos,
+	}
+}
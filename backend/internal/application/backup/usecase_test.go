@@ -0,0 +1,385 @@
+package backup
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/Unlites/comparison_center/backend/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// memoryArchive keeps the backup and photo files written to it,
+// so they can be read back as they were written.
+type memoryArchive struct {
+	manifest domain.BackupManifest
+	backup   domain.Backup
+	photos   []string
+	contents []string
+}
+
+func (a *memoryArchive) WriteBackup(manifest domain.BackupManifest, backup domain.Backup) error {
+	a.manifest = manifest
+	a.backup = backup
+	return nil
+}
+
+func (a *memoryArchive) WritePhoto(name string, size int64, content io.Reader) error {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+
+	a.photos = append(a.photos, name)
+	a.contents = append(a.contents, string(data))
+	return nil
+}
+
+func (a *memoryArchive) ReadBackup() (domain.BackupManifest, domain.Backup, error) {
+	return a.manifest, a.backup, nil
+}
+
+func (a *memoryArchive) NextPhoto() (string, int64, io.Reader, error) {
+	if len(a.photos) == 0 {
+		return "", 0, nil, io.EOF
+	}
+
+	name, content := a.photos[0], a.contents[0]
+	a.photos, a.contents = a.photos[1:], a.contents[1:]
+
+	return name, int64(len(content)), strings.NewReader(content), nil
+}
+
+var backupData = domain.Backup{
+	Folders: []domain.Folder{
+		{Id: "1b2c3d4e5f6a7b8c", Name: "SUV", ParentId: "3f0a2b3c4d5e6f70"},
+		{Id: "3f0a2b3c4d5e6f70", Name: "Vehicles"},
+	},
+	Comparisons: []domain.Comparison{{
+		Id:              "85434230werhuhi123912304",
+		Name:            "Cars",
+		Tags:            []string{"family"},
+		FolderId:        "1b2c3d4e5f6a7b8c",
+		CustomOptionIds: []string{"432230ewrew3424rwe", "missing3424rwe"},
+		OptionWeights:   []domain.OptionWeight{{CustomOptionId: "432230ewrew3424rwe", Weight: 1}},
+	}},
+	CustomOptions: []domain.CustomOption{
+		{Id: "432230ewrew3424rwe", Name: "Power", Type: domain.CustomOptionTypeInteger},
+	},
+	Objects: []domain.Object{
+		{Id: "231934sadas9123deqw", Name: "BMW X5", Rating: 8, ComparisonId: "85434230werhuhi123912304"},
+	},
+	ObjectCustomOptions: []domain.ObjectCustomOption{
+		{ObjectId: "231934sadas9123deqw", CustomOptionId: "432230ewrew3424rwe", Value: "600"},
+	},
+	Photos: []domain.Photo{
+		{Id: "65765fdgdf567fdg", ObjectId: "231934sadas9123deqw", Key: "65765fdgdf567fdg.jpg", ContentType: "image/jpeg"},
+	},
+}
+
+func TestDump(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		folderRepo := mocks.NewFolderRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewBackupUsecase(
+			folderRepo, comparisonRepo, custOptRepo, objRepo, custOptObjRepo, photoRepo,
+			photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+
+		folderRepo.On("GetFolders", ctx).Return(backupData.Folders, nil)
+		comparisonRepo.On("GetAllComparisons", ctx).Return(backupData.Comparisons, nil)
+		custOptRepo.On("GetAllCustomOptions", ctx).Return(backupData.CustomOptions, nil)
+		objRepo.On("GetAllObjects", ctx).Return(backupData.Objects, nil)
+		custOptObjRepo.On("GetAllObjectCustomOptions", ctx).Return(backupData.ObjectCustomOptions, nil)
+		photoRepo.On("GetAllPhotos", ctx).Return(backupData.Photos, nil)
+		photoStore.On("Get", ctx, "65765fdgdf567fdg_thumb.jpg").
+			Return(mocks.NewPhotoContent("thumb"), domain.PhotoInfo{Size: 5}, nil)
+		photoStore.On("Get", ctx, "65765fdgdf567fdg_medium.jpg").
+			Return(nil, domain.PhotoInfo{}, domain.ErrNotFound)
+		photoStore.On("Get", ctx, "65765fdgdf567fdg.jpg").
+			Return(mocks.NewPhotoContent("original"), domain.PhotoInfo{Size: 8}, nil)
+
+		archive := &memoryArchive{}
+
+		manifest, photoFiles, err := uc.Dump(ctx, archive)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.BackupVersion, manifest.Version)
+		assert.Equal(t, 2, manifest.Folders)
+		assert.Equal(t, 1, manifest.Comparisons)
+		assert.Equal(t, 1, manifest.Photos)
+		assert.Equal(t, 2, photoFiles)
+		assert.Equal(t, manifest, archive.manifest)
+		assert.Equal(t, backupData, archive.backup)
+		assert.Equal(t, []string{"65765fdgdf567fdg_thumb.jpg", "65765fdgdf567fdg.jpg"}, archive.photos)
+		assert.Equal(t, []string{"thumb", "original"}, archive.contents)
+		assert.Equal(t, 1, transactor.Committed)
+	})
+
+	t.Run("Photo store error", func(t *testing.T) {
+		folderRepo := mocks.NewFolderRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewBackupUsecase(
+			folderRepo, comparisonRepo, custOptRepo, objRepo, custOptObjRepo, photoRepo,
+			photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+
+		folderRepo.On("GetFolders", ctx).Return(backupData.Folders, nil)
+		comparisonRepo.On("GetAllComparisons", ctx).Return(backupData.Comparisons, nil)
+		custOptRepo.On("GetAllCustomOptions", ctx).Return(backupData.CustomOptions, nil)
+		objRepo.On("GetAllObjects", ctx).Return(backupData.Objects, nil)
+		custOptObjRepo.On("GetAllObjectCustomOptions", ctx).Return(backupData.ObjectCustomOptions, nil)
+		photoRepo.On("GetAllPhotos", ctx).Return(backupData.Photos, nil)
+		photoStore.On("Get", ctx, mock.Anything).Return(nil, domain.PhotoInfo{}, assert.AnError)
+
+		_, _, err := uc.Dump(ctx, &memoryArchive{})
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestRestore(t *testing.T) {
+	newArchive := func() *memoryArchive {
+		return &memoryArchive{
+			manifest: domain.NewBackupManifest(backupData),
+			backup:   backupData,
+			photos:   []string{"65765fdgdf567fdg_thumb.jpg", "65765fdgdf567fdg.jpg", "unknown.jpg"},
+			contents: []string{"thumb", "original", "unknown"},
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		folderRepo := mocks.NewFolderRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewBackupUsecase(
+			folderRepo, comparisonRepo, custOptRepo, objRepo, custOptObjRepo, photoRepo,
+			photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+
+		comparisonRepo.On("GetAllComparisons", ctx).Return(nil, nil)
+		custOptRepo.On("GetAllCustomOptions", ctx).Return(nil, nil)
+		folderRepo.On("GetFolders", ctx).Return(nil, nil)
+		photoStore.On("Put", ctx, "65765fdgdf567fdg_thumb.jpg", mock.Anything, int64(5), "image/jpeg").Return(nil)
+		photoStore.On("Put", ctx, "65765fdgdf567fdg.jpg", mock.Anything, int64(8), "image/jpeg").Return(nil)
+		custOptRepo.On("CreateCustomOptions", ctx, backupData.CustomOptions).Return(nil)
+		folderRepo.On("CreateFolders", ctx, backupData.Folders).Return(nil)
+		comparisonRepo.On("CreateComparisons", ctx, backupData.Comparisons).Return(nil)
+		objRepo.On("CreateObjects", ctx, []domain.Object{{
+			Id:           "231934sadas9123deqw",
+			Name:         "BMW X5",
+			Rating:       8,
+			ComparisonId: "85434230werhuhi123912304",
+			ObjectCustomOptions: []domain.ObjectCustomOption{
+				{ObjectId: "231934sadas9123deqw", CustomOptionId: "432230ewrew3424rwe", Value: "600", TypedValue: int64(600)},
+			},
+		}}).Return(nil)
+		custOptObjRepo.On("AddObjectCustomOptions", ctx, []domain.ObjectCustomOption{
+			{ObjectId: "231934sadas9123deqw", CustomOptionId: "432230ewrew3424rwe", Value: "600", TypedValue: int64(600)},
+		}).Return(nil)
+		photoRepo.On("CreatePhotos", ctx, backupData.Photos).Return(nil)
+
+		manifest, photoFiles, err := uc.Restore(ctx, newArchive(), false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, manifest.Objects)
+		assert.Equal(t, 2, photoFiles)
+		assert.Equal(t, 1, transactor.Committed)
+		photoStore.AssertExpectations(t)
+		custOptRepo.AssertExpectations(t)
+		folderRepo.AssertExpectations(t)
+		comparisonRepo.AssertExpectations(t)
+		objRepo.AssertExpectations(t)
+		custOptObjRepo.AssertExpectations(t)
+		photoRepo.AssertExpectations(t)
+	})
+
+	t.Run("Remap ids", func(t *testing.T) {
+		folderRepo := mocks.NewFolderRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewBackupUsecase(
+			folderRepo, comparisonRepo, custOptRepo, objRepo, custOptObjRepo, photoRepo,
+			photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+
+		comparisonRepo.On("GetAllComparisons", ctx).Return(nil, nil)
+		custOptRepo.On("GetAllCustomOptions", ctx).Return(nil, nil)
+		folderRepo.On("GetFolders", ctx).Return(nil, nil)
+		generator.On("GenerateId").Return("new432230ewrew").Once()
+		generator.On("GenerateId").Return("new1b2c3d4e5f").Once()
+		generator.On("GenerateId").Return("new3f0a2b3c4d").Once()
+		generator.On("GenerateId").Return("new85434230wer").Once()
+		generator.On("GenerateId").Return("new231934sadas").Once()
+		generator.On("GenerateId").Return("new65765fdgdf5").Once()
+		photoStore.On("Put", ctx, "new65765fdgdf5_thumb.jpg", mock.Anything, int64(5), "image/jpeg").Return(nil)
+		photoStore.On("Put", ctx, "new65765fdgdf5.jpg", mock.Anything, int64(8), "image/jpeg").Return(nil)
+		custOptRepo.On("CreateCustomOptions", ctx, []domain.CustomOption{
+			{Id: "new432230ewrew", Name: "Power", Type: domain.CustomOptionTypeInteger},
+		}).Return(nil)
+		folderRepo.On("CreateFolders", ctx, []domain.Folder{
+			{Id: "new1b2c3d4e5f", Name: "SUV", ParentId: "new3f0a2b3c4d"},
+			{Id: "new3f0a2b3c4d", Name: "Vehicles"},
+		}).Return(nil)
+		comparisonRepo.On("CreateComparisons", ctx, []domain.Comparison{{
+			Id:              "new85434230wer",
+			Name:            "Cars",
+			Tags:            []string{"family"},
+			FolderId:        "new1b2c3d4e5f",
+			CustomOptionIds: []string{"new432230ewrew", "missing3424rwe"},
+			OptionWeights:   []domain.OptionWeight{{CustomOptionId: "new432230ewrew", Weight: 1}},
+		}}).Return(nil)
+		objRepo.On("CreateObjects", ctx, []domain.Object{
+			{
+				Id:           "new231934sadas",
+				Name:         "BMW X5",
+				Rating:       8,
+				ComparisonId: "new85434230wer",
+				ObjectCustomOptions: []domain.ObjectCustomOption{
+					{ObjectId: "new231934sadas", CustomOptionId: "new432230ewrew", Value: "600", TypedValue: int64(600)},
+				},
+			},
+		}).Return(nil)
+		custOptObjRepo.On("AddObjectCustomOptions", ctx, []domain.ObjectCustomOption{
+			{ObjectId: "new231934sadas", CustomOptionId: "new432230ewrew", Value: "600", TypedValue: int64(600)},
+		}).Return(nil)
+		photoRepo.On("CreatePhotos", ctx, []domain.Photo{
+			{Id: "new65765fdgdf5", ObjectId: "new231934sadas", Key: "new65765fdgdf5.jpg", ContentType: "image/jpeg"},
+		}).Return(nil)
+
+		_, photoFiles, err := uc.Restore(ctx, newArchive(), true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, photoFiles)
+		photoStore.AssertExpectations(t)
+		custOptRepo.AssertExpectations(t)
+		folderRepo.AssertExpectations(t)
+		comparisonRepo.AssertExpectations(t)
+		objRepo.AssertExpectations(t)
+		custOptObjRepo.AssertExpectations(t)
+		photoRepo.AssertExpectations(t)
+	})
+
+	t.Run("Database is not empty", func(t *testing.T) {
+		folderRepo := mocks.NewFolderRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewBackupUsecase(
+			folderRepo, comparisonRepo, custOptRepo, objRepo, custOptObjRepo, photoRepo,
+			photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+
+		comparisonRepo.On("GetAllComparisons", ctx).Return(backupData.Comparisons, nil)
+		custOptRepo.On("GetAllCustomOptions", ctx).Return(nil, nil)
+		folderRepo.On("GetFolders", ctx).Return(nil, nil)
+
+		_, _, err := uc.Restore(ctx, newArchive(), false)
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		photoStore.AssertNotCalled(t, "Put")
+		custOptRepo.AssertNotCalled(t, "CreateCustomOptions", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unsupported version", func(t *testing.T) {
+		folderRepo := mocks.NewFolderRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewBackupUsecase(
+			folderRepo, comparisonRepo, custOptRepo, objRepo, custOptObjRepo, photoRepo,
+			photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+
+		archive := newArchive()
+		archive.manifest.Version = domain.BackupVersion + 1
+
+		_, _, err := uc.Restore(ctx, archive, false)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		comparisonRepo.AssertNotCalled(t, "GetAllComparisons", mock.Anything)
+	})
+
+	t.Run("Error rolls back", func(t *testing.T) {
+		folderRepo := mocks.NewFolderRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewBackupUsecase(
+			folderRepo, comparisonRepo, custOptRepo, objRepo, custOptObjRepo, photoRepo,
+			photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+
+		comparisonRepo.On("GetAllComparisons", ctx).Return(nil, nil)
+		custOptRepo.On("GetAllCustomOptions", ctx).Return(nil, nil)
+		folderRepo.On("GetFolders", ctx).Return(nil, nil)
+		photoStore.On("Put", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		custOptRepo.On("CreateCustomOptions", ctx, mock.Anything).Return(nil)
+		folderRepo.On("CreateFolders", ctx, mock.Anything).Return(nil)
+		comparisonRepo.On("CreateComparisons", ctx, mock.Anything).Return(assert.AnError)
+
+		_, _, err := uc.Restore(ctx, newArchive(), false)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 1, transactor.RolledBack)
+		objRepo.AssertNotCalled(t, "CreateObjects", mock.Anything, mock.Anything)
+	})
+}
@@ -2,23 +2,83 @@ package comparison
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"path"
 	"time"
 
 	"github.com/Unlites/comparison_center/backend/internal/domain"
 )
 
 type ComparisonUsecase struct {
-	repo        ComparisonRepository
-	idGenerator IdGenerator
+	repo           ComparisonRepository
+	objRepo        ObjectRepository
+	custOptObjRepo ObjectCustomOptionRepository
+	custOptRepo    CustomOptionRepository
+	photoRepo      PhotoRepository
+	templateRepo   TemplateRepository
+	folderRepo     FolderRepository
+	auditRepo      AuditRepository
+	photoStore     PhotoStore
+	transactor     Transactor
+	idGenerator    IdGenerator
 }
 
 type ComparisonRepository interface {
-	GetComparisons(ctx context.Context, filter domain.ComparisonFilter) ([]domain.Comparison, error)
+	GetComparisons(ctx context.Context, filter domain.ComparisonFilter) ([]domain.Comparison, domain.PageInfo, error)
 	GetComparisonById(ctx context.Context, id string) (domain.Comparison, error)
 	UpdateComparison(ctx context.Context, comparison domain.Comparison) error
 	CreateComparison(ctx context.Context, comparison domain.Comparison) error
-	DeleteComparison(ctx context.Context, id string) error
+	DeleteComparison(ctx context.Context, id string, deletedAt time.Time) error
+	GetDeletedComparisonById(ctx context.Context, id string) (domain.Comparison, error)
+	RestoreComparison(ctx context.Context, comparison domain.Comparison) error
+}
+
+type ObjectRepository interface {
+	GetObjectsByComparisonId(ctx context.Context, comparisonId string) ([]domain.Object, error)
+	DeleteObjectsByComparisonId(ctx context.Context, comparisonId string, deletedAt time.Time) error
+	GetObjectsDeletedWithComparison(ctx context.Context, comparisonId string) ([]domain.Object, error)
+	RestoreObjectsByComparisonId(ctx context.Context, comparisonId string) error
+	CreateObjects(ctx context.Context, objects []domain.Object) error
+}
+
+type ObjectCustomOptionRepository interface {
+	GetObjectCustomOptionsByObjectIds(ctx context.Context, objectIds []string) ([]domain.ObjectCustomOption, error)
+	AddObjectCustomOptions(ctx context.Context, objCustomOptions []domain.ObjectCustomOption) error
+}
+
+type CustomOptionRepository interface {
+	GetCustomOptionsByNames(ctx context.Context, names []string) ([]domain.CustomOption, error)
+	CreateCustomOption(ctx context.Context, customOption domain.CustomOption) error
+}
+
+type PhotoRepository interface {
+	GetPhotosByObjectIds(ctx context.Context, objectIds []string) ([]domain.Photo, error)
+	CreatePhotos(ctx context.Context, photos []domain.Photo) error
+}
+
+type TemplateRepository interface {
+	GetTemplateById(ctx context.Context, id string) (domain.Template, error)
+}
+
+type FolderRepository interface {
+	GetFolderById(ctx context.Context, id string) (domain.Folder, error)
+	GetFolderDescendantIds(ctx context.Context, id string) ([]string, error)
+}
+
+type AuditRepository interface {
+	AddAuditEvent(ctx context.Context, event domain.AuditEvent) error
+}
+
+type PhotoStore interface {
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadSeekCloser, domain.PhotoInfo, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type IdGenerator interface {
@@ -27,24 +87,61 @@ type IdGenerator interface {
 
 func NewComparisonUsecase(
 	repo ComparisonRepository,
+	objRepo ObjectRepository,
+	custOptObjRepo ObjectCustomOptionRepository,
+	custOptRepo CustomOptionRepository,
+	photoRepo PhotoRepository,
+	templateRepo TemplateRepository,
+	folderRepo FolderRepository,
+	auditRepo AuditRepository,
+	photoStore PhotoStore,
+	transactor Transactor,
 	idGenerator IdGenerator,
 ) *ComparisonUsecase {
 	return &ComparisonUsecase{
-		repo:        repo,
-		idGenerator: idGenerator,
+		repo:           repo,
+		objRepo:        objRepo,
+		custOptObjRepo: custOptObjRepo,
+		custOptRepo:    custOptRepo,
+		photoRepo:      photoRepo,
+		templateRepo:   templateRepo,
+		folderRepo:     folderRepo,
+		auditRepo:      auditRepo,
+		photoStore:     photoStore,
+		transactor:     transactor,
+		idGenerator:    idGenerator,
 	}
 }
 
+// GetComparisons returns a page of comparisons. Comparisons of a folder are
+// returned together with comparisons of its subfolders.
 func (uc *ComparisonUsecase) GetComparisons(
 	ctx context.Context,
 	filter domain.ComparisonFilter,
-) ([]domain.Comparison, error) {
-	comparisons, err := uc.repo.GetComparisons(ctx, filter)
+) ([]domain.Comparison, domain.PageInfo, error) {
+	if len(filter.FolderIds) != 0 {
+		descendantIds, err := uc.folderRepo.GetFolderDescendantIds(ctx, filter.FolderIds[0])
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, domain.PageInfo{}, fmt.Errorf(
+					"folder '%s' not found - %w",
+					filter.FolderIds[0],
+					domain.ErrInvalidValue,
+				)
+			}
+
+			return nil, domain.PageInfo{}, fmt.Errorf("failed to get subfolders - %w", err)
+		}
+
+		filter.FolderIds = append(filter.FolderIds[:1:1], descendantIds...)
+	}
+
+	comparisons, pageInfo, err := uc.repo.GetComparisons(ctx, filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get comparisons - %w", err)
+		return nil, domain.PageInfo{}, fmt.Errorf("failed to get comparisons - %w", err)
 	}
 
-	return comparisons, nil
+	return comparisons, pageInfo, nil
 }
 
 func (uc *ComparisonUsecase) GetComparisonById(
@@ -72,30 +169,506 @@ func (uc *ComparisonUsecase) UpdateComparison(
 	comparison.Id = existingComparison.Id
 	comparison.CreatedAt = existingComparison.CreatedAt
 
-	if err := uc.repo.UpdateComparison(ctx, comparison); err != nil {
-		return fmt.Errorf("failed to update comparison - %w", err)
+	comparison, err = uc.organize(ctx, comparison)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	if err := comparison.Validate(); err != nil {
+		return fmt.Errorf("invalid comparison - %w", err)
+	}
+
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.repo.UpdateComparison(ctx, comparison); err != nil {
+			return fmt.Errorf("failed to update comparison - %w", err)
+		}
+
+		return uc.recordChange(ctx, domain.AuditEntityComparison, comparison.Id,
+			existingComparison.AuditFields(), comparison.AuditFields())
+	})
 }
 
+// CreateComparison creates the comparison. When templateId is given, custom options of
+// the template are placed before the ones of the comparison with default weights of the
+// template, and options of the template missing in the database are created along with it.
 func (uc *ComparisonUsecase) CreateComparison(
 	ctx context.Context,
 	comparison domain.Comparison,
+	templateId string,
 ) error {
+	var customOptions []domain.CustomOption
+
+	if templateId != "" {
+		template, err := uc.templateRepo.GetTemplateById(ctx, templateId)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return fmt.Errorf("template '%s' not found - %w", templateId, domain.ErrInvalidValue)
+			}
+
+			return fmt.Errorf("failed to get template - %w", err)
+		}
+
+		comparison, customOptions, err = uc.applyTemplate(ctx, comparison, template)
+		if err != nil {
+			return err
+		}
+	}
+
+	comparison, err := uc.organize(ctx, comparison)
+	if err != nil {
+		return err
+	}
+
+	if err := comparison.Validate(); err != nil {
+		return fmt.Errorf("invalid comparison - %w", err)
+	}
+
 	comparison.Id = uc.idGenerator.GenerateId()
 	comparison.CreatedAt = time.Now()
 
-	if err := uc.repo.CreateComparison(ctx, comparison); err != nil {
-		return fmt.Errorf("failed to create comparison - %w", err)
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, customOption := range customOptions {
+			if err := uc.custOptRepo.CreateCustomOption(ctx, customOption); err != nil {
+				return fmt.Errorf("failed to create custom option - %w", err)
+			}
+
+			err := uc.recordChange(ctx, domain.AuditEntityCustomOption, customOption.Id, nil, customOption.AuditFields())
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := uc.repo.CreateComparison(ctx, comparison); err != nil {
+			return fmt.Errorf("failed to create comparison - %w", err)
+		}
+
+		return uc.recordChange(ctx, domain.AuditEntityComparison, comparison.Id, nil, comparison.AuditFields())
+	})
+}
+
+// organize gives the comparison with normalized tags, making sure its folder exists.
+func (uc *ComparisonUsecase) organize(ctx context.Context, comparison domain.Comparison) (domain.Comparison, error) {
+	tags, err := domain.NormalizeTags(comparison.Tags)
+	if err != nil {
+		return domain.Comparison{}, fmt.Errorf("invalid comparison - %w", err)
 	}
 
-	return nil
+	comparison.Tags = tags
+
+	if comparison.FolderId == "" {
+		return comparison, nil
+	}
+
+	if _, err := uc.folderRepo.GetFolderById(ctx, comparison.FolderId); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.Comparison{}, fmt.Errorf(
+				"folder '%s' not found - %w",
+				comparison.FolderId,
+				domain.ErrInvalidValue,
+			)
+		}
+
+		return domain.Comparison{}, fmt.Errorf("failed to get folder - %w", err)
+	}
+
+	return comparison, nil
+}
+
+// applyTemplate gives the comparison with custom options of the template along with
+// custom options of the template that do not exist yet and have to be created.
+// Existing options are found by name, they are reused only if their type and currency
+// match the template, otherwise domain.ErrConflict is returned.
+func (uc *ComparisonUsecase) applyTemplate(
+	ctx context.Context,
+	comparison domain.Comparison,
+	template domain.Template,
+) (domain.Comparison, []domain.CustomOption, error) {
+	existing, err := uc.custOptRepo.GetCustomOptionsByNames(ctx, template.OptionNames())
+	if err != nil {
+		return domain.Comparison{}, nil, fmt.Errorf("failed to get custom options - %w", err)
+	}
+
+	existingByName := make(map[string]domain.CustomOption, len(existing))
+	for _, co := range existing {
+		existingByName[co.Name] = co
+	}
+
+	ids := make(map[string]string, len(template.Options))
+	var missing []domain.CustomOption
+
+	for _, o := range template.Options {
+		if co, ok := existingByName[o.Name]; ok {
+			if !o.Matches(co) {
+				return domain.Comparison{}, nil, fmt.Errorf(
+					"custom option '%s' of the template is %s, but the existing one is %s - %w",
+					o.Name,
+					describeOptionType(o.Type, o.Currency),
+					describeOptionType(co.Type, co.Currency),
+					domain.ErrConflict,
+				)
+			}
+
+			ids[o.Name] = co.Id
+			continue
+		}
+
+		customOption := o.CustomOption()
+		customOption.Id = uc.idGenerator.GenerateId()
+		ids[o.Name] = customOption.Id
+
+		missing = append(missing, customOption)
+	}
+
+	return template.ApplyTo(comparison, ids), missing, nil
 }
 
+func describeOptionType(optionType domain.CustomOptionType, currency string) string {
+	if currency == "" {
+		return string(optionType)
+	}
+
+	return fmt.Sprintf("%s in %s", optionType, currency)
+}
+
+// CloneComparison creates a comparison with the same custom options and weights as the
+// source one and, if asked, copies of its objects with their custom option values and
+// photos. Everything copied gets new ids. Photo files are copied before the transaction,
+// so committed photos never refer to missing files, and are removed again if it fails.
+// Files that can not be removed are reported along with the error, they are left for
+// the photo garbage collector.
+func (uc *ComparisonUsecase) CloneComparison(
+	ctx context.Context,
+	id string,
+	opts domain.CloneOptions,
+) (string, error) {
+	if err := opts.Validate(); err != nil {
+		return "", fmt.Errorf("invalid clone options - %w", err)
+	}
+
+	source, err := uc.repo.GetComparisonById(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("failed to get comparison - %w", err)
+	}
+
+	clone := source.Clone(opts.Name)
+	clone.Id = uc.idGenerator.GenerateId()
+	clone.CreatedAt = time.Now()
+
+	var (
+		objects          []domain.Object
+		objCustomOptions []domain.ObjectCustomOption
+		photos           []domain.Photo
+		copiedKeys       []string
+	)
+
+	if opts.IncludeObjects {
+		objects, objCustomOptions, photos, err = uc.cloneObjects(ctx, source.Id, clone, opts.IncludePhotos)
+		if err != nil {
+			return "", err
+		}
+
+		copiedKeys, err = uc.copyPhotoFiles(ctx, photos)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.repo.CreateComparison(ctx, clone); err != nil {
+			return fmt.Errorf("failed to create comparison - %w", err)
+		}
+
+		if err := uc.recordChange(ctx, domain.AuditEntityComparison, clone.Id, nil, clone.AuditFields()); err != nil {
+			return err
+		}
+
+		if len(objects) == 0 {
+			return nil
+		}
+
+		if err := uc.objRepo.CreateObjects(ctx, objects); err != nil {
+			return fmt.Errorf("failed to create objects - %w", err)
+		}
+
+		if len(objCustomOptions) > 0 {
+			if err := uc.custOptObjRepo.AddObjectCustomOptions(ctx, objCustomOptions); err != nil {
+				return fmt.Errorf("failed to add object custom options - %w", err)
+			}
+		}
+
+		if len(photos) > 0 {
+			if err := uc.photoRepo.CreatePhotos(ctx, photos); err != nil {
+				return fmt.Errorf("failed to create photos - %w", err)
+			}
+		}
+
+		for _, obj := range objects {
+			if err := uc.recordChange(ctx, domain.AuditEntityObject, obj.Id, nil, obj.AuditFields()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", errors.Join(err, uc.deletePhotoFiles(ctx, copiedKeys))
+	}
+
+	return clone.Id, nil
+}
+
+// cloneObjects gives copies of objects of the source comparison moved to the clone,
+// together with copies of their custom option values and, if asked, of their photos
+// referring to keys of files that are yet to be copied.
+func (uc *ComparisonUsecase) cloneObjects(
+	ctx context.Context,
+	sourceId string,
+	clone domain.Comparison,
+	includePhotos bool,
+) ([]domain.Object, []domain.ObjectCustomOption, []domain.Photo, error) {
+	objects, err := uc.objRepo.GetObjectsByComparisonId(ctx, sourceId)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get objects - %w", err)
+	}
+
+	if len(objects) == 0 {
+		return nil, nil, nil, nil
+	}
+
+	sourceIds := make([]string, len(objects))
+	cloneIds := make(map[string]string, len(objects))
+	for i, obj := range objects {
+		sourceIds[i] = obj.Id
+		cloneIds[obj.Id] = uc.idGenerator.GenerateId()
+
+		objects[i].Id = cloneIds[obj.Id]
+		objects[i].ComparisonId = clone.Id
+		objects[i].CreatedAt = clone.CreatedAt
+	}
+
+	objCustomOptions, err := uc.custOptObjRepo.GetObjectCustomOptionsByObjectIds(ctx, sourceIds)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get object custom options - %w", err)
+	}
+
+	optionsByObjectId := make(map[string][]domain.ObjectCustomOption, len(objects))
+	for i, oco := range objCustomOptions {
+		objCustomOptions[i].ObjectId = cloneIds[oco.ObjectId]
+		optionsByObjectId[objCustomOptions[i].ObjectId] = append(
+			optionsByObjectId[objCustomOptions[i].ObjectId],
+			objCustomOptions[i],
+		)
+	}
+
+	for i, obj := range objects {
+		objects[i].ObjectCustomOptions = optionsByObjectId[obj.Id]
+	}
+
+	if !includePhotos {
+		return objects, objCustomOptions, nil, nil
+	}
+
+	photos, err := uc.photoRepo.GetPhotosByObjectIds(ctx, sourceIds)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get object photos - %w", err)
+	}
+
+	for i, photo := range photos {
+		photos[i].Id = uc.idGenerator.GenerateId()
+		photos[i].ObjectId = cloneIds[photo.ObjectId]
+		photos[i].CreatedAt = clone.CreatedAt
+	}
+
+	return objects, objCustomOptions, photos, nil
+}
+
+// copyPhotoFiles copies files of all sizes of the source photos to keys of their
+// clones given by ids of the clones, updating keys of photos in place. Keys of
+// copied files are returned, so they can be removed if cloning fails.
+func (uc *ComparisonUsecase) copyPhotoFiles(ctx context.Context, photos []domain.Photo) ([]string, error) {
+	copied := make([]string, 0, len(photos)*len(domain.ProvidedPhotoSizes))
+
+	for i, photo := range photos {
+		cloneKey := photo.Id + path.Ext(photo.Key)
+
+		for _, size := range domain.ProvidedPhotoSizes {
+			sourceKey := domain.PhotoVariantKey(photo.Key, size)
+			key := domain.PhotoVariantKey(cloneKey, size)
+
+			ok, err := uc.copyPhotoFile(ctx, sourceKey, key)
+			if err != nil {
+				return nil, errors.Join(err, uc.deletePhotoFiles(ctx, copied))
+			}
+
+			if ok {
+				copied = append(copied, key)
+			}
+		}
+
+		photos[i].Key = cloneKey
+	}
+
+	return copied, nil
+}
+
+// copyPhotoFile copies the photo file to the given key. Photos uploaded before sizes
+// were introduced have no files of other sizes, these are skipped.
+func (uc *ComparisonUsecase) copyPhotoFile(ctx context.Context, sourceKey, key string) (bool, error) {
+	content, info, err := uc.photoStore.Get(ctx, sourceKey)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to get photo file - %w", err)
+	}
+	defer content.Close()
+
+	if err := uc.photoStore.Put(ctx, key, content, info.Size, info.ContentType); err != nil {
+		return false, fmt.Errorf("failed to put photo file - %w", err)
+	}
+
+	return true, nil
+}
+
+// deletePhotoFiles removes files copied by a failed clone, trying every key
+// even if some of them fail.
+func (uc *ComparisonUsecase) deletePhotoFiles(ctx context.Context, keys []string) error {
+	errs := make([]error, 0)
+	for _, key := range keys {
+		if err := uc.photoStore.Delete(ctx, key); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove copied photo file '%s' - %w", key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// DeleteComparison moves the comparison with all of its objects to the trash,
+// they are removed for good once the retention period is over. Deletion of objects
+// is recorded in their history as well, without values of their custom options.
 func (uc *ComparisonUsecase) DeleteComparison(ctx context.Context, id string) error {
-	if err := uc.repo.DeleteComparison(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete comparison - %w", err)
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		comparison, err := uc.repo.GetComparisonById(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get comparison - %w", err)
+		}
+
+		objects, err := uc.objRepo.GetObjectsByComparisonId(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get objects - %w", err)
+		}
+
+		deletedAt := time.Now()
+
+		if err := uc.repo.DeleteComparison(ctx, id, deletedAt); err != nil {
+			return fmt.Errorf("failed to delete comparison - %w", err)
+		}
+
+		err = uc.recordChange(ctx, domain.AuditEntityComparison, comparison.Id, comparison.AuditFields(), nil)
+		if err != nil {
+			return err
+		}
+
+		if len(objects) == 0 {
+			return nil
+		}
+
+		for _, obj := range objects {
+			if err := uc.recordChange(ctx, domain.AuditEntityObject, obj.Id, obj.AuditFields(), nil); err != nil {
+				return err
+			}
+		}
+
+		if err := uc.objRepo.DeleteObjectsByComparisonId(ctx, id, deletedAt); err != nil {
+			return fmt.Errorf("failed to delete objects - %w", err)
+		}
+
+		return nil
+	})
+}
+
+// RestoreComparison takes the comparison out of the trash together with the objects
+// deleted along with it. Objects deleted on their own before stay in the trash.
+func (uc *ComparisonUsecase) RestoreComparison(ctx context.Context, id string) error {
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		comparison, err := uc.repo.GetDeletedComparisonById(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get deleted comparison - %w", err)
+		}
+
+		objects, err := uc.objRepo.GetObjectsDeletedWithComparison(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get deleted objects - %w", err)
+		}
+
+		// the folder may have been deleted while the comparison was in the trash
+		if comparison.FolderId != "" {
+			_, err := uc.folderRepo.GetFolderById(ctx, comparison.FolderId)
+			if errors.Is(err, domain.ErrNotFound) {
+				comparison.FolderId = ""
+			} else if err != nil {
+				return fmt.Errorf("failed to get folder - %w", err)
+			}
+		}
+
+		if err := uc.repo.RestoreComparison(ctx, comparison); err != nil {
+			return fmt.Errorf("failed to restore comparison - %w", err)
+		}
+
+		if err := uc.recordRestore(ctx, domain.AuditEntityComparison, comparison.Id, comparison.AuditFields()); err != nil {
+			return err
+		}
+
+		if len(objects) == 0 {
+			return nil
+		}
+
+		if err := uc.objRepo.RestoreObjectsByComparisonId(ctx, id); err != nil {
+			return fmt.Errorf("failed to restore objects - %w", err)
+		}
+
+		for _, obj := range objects {
+			if err := uc.recordRestore(ctx, domain.AuditEntityObject, obj.Id, obj.AuditFields()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// recordChange adds the change of the entity to the audit log,
+// updates that changed nothing are not recorded.
+func (uc *ComparisonUsecase) recordChange(
+	ctx context.Context,
+	entity domain.AuditEntity,
+	id string,
+	before, after []domain.AuditField,
+) error {
+	event := domain.NewAuditEvent(entity, id, before, after)
+	if event.Action == domain.AuditActionUpdate && len(event.Changes) == 0 {
+		return nil
+	}
+
+	return uc.addAuditEvent(ctx, event)
+}
+
+// recordRestore adds the restoration of the entity from the trash to the audit log.
+func (uc *ComparisonUsecase) recordRestore(
+	ctx context.Context,
+	entity domain.AuditEntity,
+	id string,
+	fields []domain.AuditField,
+) error {
+	return uc.addAuditEvent(ctx, domain.NewRestoreAuditEvent(entity, id, fields))
+}
+
+func (uc *ComparisonUsecase) addAuditEvent(ctx context.Context, event domain.AuditEvent) error {
+	event.Id = uc.idGenerator.GenerateId()
+
+	if err := uc.auditRepo.AddAuditEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to add audit event - %w", err)
 	}
 
 	return nil
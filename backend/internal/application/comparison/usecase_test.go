@@ -2,6 +2,7 @@ package comparison
 
 import (
 	"context"
+	"fmt"
 	"slices"
 	"testing"
 	"time"
@@ -15,8 +16,20 @@ import (
 func TestComparisons(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewComparisonUsecase(repo, generator)
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
 
 		ctx := context.Background()
 		returnedComparisons := []domain.Comparison{
@@ -35,23 +48,41 @@ func TestComparisons(t *testing.T) {
 		}
 
 		filter := domain.ComparisonFilter{
-			Limit:  2,
-			Offset: 0,
+			Limit:          2,
+			Offset:         0,
+			Name:           "car",
+			CreatedFrom:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			CustomOptionId: "432230ewrew3424rwe",
 		}
 
-		repo.On("GetComparisons", ctx, filter).Return(returnedComparisons, nil)
+		returnedPageInfo := domain.PageInfo{Total: 3, NextCursor: "ZXdydzM0MjM0"}
 
-		comparisons, err := uc.GetComparisons(ctx, filter)
+		repo.On("GetComparisons", ctx, filter).Return(returnedComparisons, returnedPageInfo, nil)
+
+		comparisons, pageInfo, err := uc.GetComparisons(ctx, filter)
 
 		assert.NoError(t, err)
 		assert.Equal(t, returnedComparisons, comparisons)
+		assert.Equal(t, returnedPageInfo, pageInfo)
 		repo.AssertExpectations(t)
 	})
 
 	t.Run("Error", func(t *testing.T) {
 		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewComparisonUsecase(repo, generator)
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
 
 		ctx := context.Background()
 		filter := domain.ComparisonFilter{
@@ -59,21 +90,101 @@ func TestComparisons(t *testing.T) {
 			Offset: 0,
 		}
 
-		repo.On("GetComparisons", ctx, filter).Return(nil, assert.AnError)
+		repo.On("GetComparisons", ctx, filter).Return(nil, nil, assert.AnError)
 
-		comparisons, err := uc.GetComparisons(ctx, filter)
+		comparisons, _, err := uc.GetComparisons(ctx, filter)
 
 		assert.Error(t, err)
 		assert.Nil(t, comparisons)
 		repo.AssertExpectations(t)
 	})
+
+	t.Run("Folder with subfolders", func(t *testing.T) {
+		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+		filter := domain.ComparisonFilter{
+			Limit:     2,
+			Tag:       "travel",
+			FolderIds: []string{"9a8b7c6d5e4f3a2b"},
+		}
+
+		folderRepo.On("GetFolderDescendantIds", ctx, "9a8b7c6d5e4f3a2b").
+			Return([]string{"1b2c3d4e5f6a7b8c", "2c3d4e5f6a7b8c9d"}, nil)
+		repo.On("GetComparisons", ctx, domain.ComparisonFilter{
+			Limit:     2,
+			Tag:       "travel",
+			FolderIds: []string{"9a8b7c6d5e4f3a2b", "1b2c3d4e5f6a7b8c", "2c3d4e5f6a7b8c9d"},
+		}).Return([]domain.Comparison{}, domain.PageInfo{}, nil)
+
+		comparisons, _, err := uc.GetComparisons(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Empty(t, comparisons)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Folder not found", func(t *testing.T) {
+		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+		filter := domain.ComparisonFilter{Limit: 2, FolderIds: []string{"9a8b7c6d5e4f3a2b"}}
+
+		folderRepo.On("GetFolderDescendantIds", ctx, "9a8b7c6d5e4f3a2b").
+			Return(nil, fmt.Errorf("folder %w", domain.ErrNotFound))
+
+		_, _, err := uc.GetComparisons(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		repo.AssertNotCalled(t, "GetComparisons")
+	})
 }
 
 func TestGetComparisonById(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewComparisonUsecase(repo, generator)
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
 
 		returnedComparison := domain.Comparison{
 			Id:              "85434230werhuhi123912304",
@@ -96,8 +207,20 @@ func TestGetComparisonById(t *testing.T) {
 
 	t.Run("Error", func(t *testing.T) {
 		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewComparisonUsecase(repo, generator)
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
 
 		ctx := context.Background()
 		id := "213213ewrwe9423432"
@@ -115,8 +238,20 @@ func TestGetComparisonById(t *testing.T) {
 func TestCreateComparison(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewComparisonUsecase(repo, generator)
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
 
 		inputComparison := domain.Comparison{
 			Name:            "Cars",
@@ -134,17 +269,43 @@ func TestCreateComparison(t *testing.T) {
 					[]string{"3332415fdsfsd31231", "5412asdsa131231"},
 				)
 		})).Return(nil)
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Entity == domain.AuditEntityComparison &&
+				event.EntityId == "49234991asdsanjd12305" &&
+				event.Action == domain.AuditActionCreate &&
+				assert.ObjectsAreEqual([]domain.FieldChange{
+					{Field: "name", After: "Cars"},
+					{
+						Field: "custom_option_ids",
+						After: []string{"3332415fdsfsd31231", "5412asdsa131231"},
+					},
+				}, event.Changes)
+		})).Return(nil)
 
-		err := uc.CreateComparison(ctx, inputComparison)
+		err := uc.CreateComparison(ctx, inputComparison, "")
 
 		assert.NoError(t, err)
+		assert.Equal(t, 1, transactor.Committed)
 		repo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
 	})
 
 	t.Run("Error", func(t *testing.T) {
 		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewComparisonUsecase(repo, generator)
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
 
 		inputComparison := domain.Comparison{
 			Name:            "Cars",
@@ -163,18 +324,330 @@ func TestCreateComparison(t *testing.T) {
 				)
 		})).Return(assert.AnError)
 
-		err := uc.CreateComparison(ctx, inputComparison)
+		err := uc.CreateComparison(ctx, inputComparison, "")
 
 		assert.Error(t, err)
 		repo.AssertExpectations(t)
 	})
+
+	t.Run("Weight of unused custom option", func(t *testing.T) {
+		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
+
+		inputComparison := domain.Comparison{
+			Name:            "Cars",
+			CustomOptionIds: []string{"432432sadas5433da"},
+			OptionWeights: []domain.OptionWeight{
+				{
+					CustomOptionId: "349fsda32bfsd21d",
+					Weight:         2,
+					Direction:      domain.ScoreDirectionHigherIsBetter,
+				},
+			},
+		}
+
+		ctx := context.Background()
+
+		err := uc.CreateComparison(ctx, inputComparison, "")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		repo.AssertNotCalled(t, "CreateComparison")
+	})
+
+	t.Run("Tags and folder", func(t *testing.T) {
+		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+
+		folderRepo.On("GetFolderById", ctx, "9a8b7c6d5e4f3a2b").
+			Return(domain.Folder{Id: "9a8b7c6d5e4f3a2b", Name: "Travel"}, nil)
+		generator.On("GenerateId").Return("49234991asdsanjd12305")
+		repo.On("CreateComparison", ctx, mock.MatchedBy(func(comparison domain.Comparison) bool {
+			return slices.Equal(comparison.Tags, []string{"summer", "family trip"}) &&
+				comparison.FolderId == "9a8b7c6d5e4f3a2b"
+		})).Return(nil)
+		auditRepo.On("AddAuditEvent", ctx, mock.Anything).Return(nil)
+
+		err := uc.CreateComparison(ctx, domain.Comparison{
+			Name:     "Hotels in Rome",
+			Tags:     []string{" Summer", "family trip", "summer "},
+			FolderId: "9a8b7c6d5e4f3a2b",
+		}, "")
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Folder not found", func(t *testing.T) {
+		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+
+		folderRepo.On("GetFolderById", ctx, "9a8b7c6d5e4f3a2b").Return(nil, fmt.Errorf("folder %w", domain.ErrNotFound))
+
+		err := uc.CreateComparison(ctx, domain.Comparison{Name: "Hotels in Rome", FolderId: "9a8b7c6d5e4f3a2b"}, "")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		repo.AssertNotCalled(t, "CreateComparison")
+	})
+
+	t.Run("From template", func(t *testing.T) {
+		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+
+		inputComparison := domain.Comparison{
+			Name:            "My phones",
+			CustomOptionIds: []string{"349fsda32bfsd21d", "432432sadas5433da"},
+			OptionWeights: []domain.OptionWeight{
+				{
+					CustomOptionId: "432432sadas5433da",
+					Weight:         3,
+					Direction:      domain.ScoreDirectionLowerIsBetter,
+				},
+			},
+		}
+
+		templateRepo.On("GetTemplateById", ctx, "0f8d3c8e-4a8b-4c1e-9d3a-6b1f2e7c5a01").Return(domain.Template{
+			Id:   "0f8d3c8e-4a8b-4c1e-9d3a-6b1f2e7c5a01",
+			Name: "Phones",
+			Options: []domain.TemplateOption{
+				{
+					Name:      "Price",
+					Type:      domain.CustomOptionTypeMoney,
+					Currency:  "USD",
+					Weight:    2,
+					Direction: domain.ScoreDirectionLowerIsBetter,
+				},
+				{
+					Name:      "Battery capacity",
+					Type:      domain.CustomOptionTypeInteger,
+					Weight:    1,
+					Direction: domain.ScoreDirectionHigherIsBetter,
+				},
+			},
+			BuiltIn: true,
+		}, nil)
+		custOptRepo.On("GetCustomOptionsByNames", ctx, []string{"Price", "Battery capacity"}).
+			Return([]domain.CustomOption{
+				{Id: "432432sadas5433da", Name: "Price", Type: domain.CustomOptionTypeMoney, Currency: "USD"},
+			}, nil)
+		generator.On("GenerateId").Return("5412asdsa131231").Once()
+		generator.On("GenerateId").Return("49234991asdsanjd12305").Once()
+		generator.On("GenerateId").Return("1232nfdsf9123fds")
+		custOptRepo.On("CreateCustomOption", ctx, domain.CustomOption{
+			Id:   "5412asdsa131231",
+			Name: "Battery capacity",
+			Type: domain.CustomOptionTypeInteger,
+		}).Return(nil)
+		repo.On("CreateComparison", ctx, mock.MatchedBy(func(comparison domain.Comparison) bool {
+			return comparison.Id == "49234991asdsanjd12305" &&
+				slices.Equal(
+					comparison.CustomOptionIds,
+					[]string{"432432sadas5433da", "5412asdsa131231", "349fsda32bfsd21d"},
+				) &&
+				slices.Equal(comparison.OptionWeights, []domain.OptionWeight{
+					{
+						CustomOptionId: "5412asdsa131231",
+						Weight:         1,
+						Direction:      domain.ScoreDirectionHigherIsBetter,
+					},
+					{
+						CustomOptionId: "432432sadas5433da",
+						Weight:         3,
+						Direction:      domain.ScoreDirectionLowerIsBetter,
+					},
+				})
+		})).Return(nil)
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Entity == domain.AuditEntityCustomOption && event.EntityId == "5412asdsa131231"
+		})).Return(nil).Once()
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Entity == domain.AuditEntityComparison && event.EntityId == "49234991asdsanjd12305"
+		})).Return(nil).Once()
+
+		err := uc.CreateComparison(ctx, inputComparison, "0f8d3c8e-4a8b-4c1e-9d3a-6b1f2e7c5a01")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, transactor.Committed)
+		repo.AssertExpectations(t)
+		custOptRepo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Template option conflicts with existing one", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			existing domain.CustomOption
+		}{
+			{
+				name:     "Other type",
+				existing: domain.CustomOption{Id: "432432sadas5433da", Name: "Price", Type: domain.CustomOptionTypeText},
+			},
+			{
+				name: "Other currency",
+				existing: domain.CustomOption{
+					Id:       "432432sadas5433da",
+					Name:     "Price",
+					Type:     domain.CustomOptionTypeMoney,
+					Currency: "EUR",
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				repo := mocks.NewComparisonRepositoryMock()
+				objRepo := mocks.NewObjectRepositoryMock()
+				custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+				custOptRepo := mocks.NewCustomOptionRepositoryMock()
+				photoRepo := mocks.NewPhotoRepositoryMock()
+				templateRepo := mocks.NewTemplateRepositoryMock()
+				folderRepo := mocks.NewFolderRepositoryMock()
+				auditRepo := mocks.NewAuditRepositoryMock()
+				photoStore := mocks.NewPhotoStoreMock()
+				transactor := mocks.NewInMemoryTransactor()
+				generator := mocks.NewMockGenerator()
+				uc := NewComparisonUsecase(
+					repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+					auditRepo, photoStore, transactor, generator,
+				)
+
+				ctx := context.Background()
+
+				templateRepo.On("GetTemplateById", ctx, "0f8d3c8e-4a8b-4c1e-9d3a-6b1f2e7c5a01").Return(domain.Template{
+					Id:   "0f8d3c8e-4a8b-4c1e-9d3a-6b1f2e7c5a01",
+					Name: "Phones",
+					Options: []domain.TemplateOption{
+						{
+							Name:      "Price",
+							Type:      domain.CustomOptionTypeMoney,
+							Currency:  "USD",
+							Weight:    2,
+							Direction: domain.ScoreDirectionLowerIsBetter,
+						},
+					},
+					BuiltIn: true,
+				}, nil)
+				custOptRepo.On("GetCustomOptionsByNames", ctx, []string{"Price"}).
+					Return([]domain.CustomOption{tt.existing}, nil)
+
+				err := uc.CreateComparison(
+					ctx,
+					domain.Comparison{Name: "My phones"},
+					"0f8d3c8e-4a8b-4c1e-9d3a-6b1f2e7c5a01",
+				)
+
+				assert.ErrorIs(t, err, domain.ErrConflict)
+				assert.Equal(t, 0, transactor.Committed)
+				custOptRepo.AssertNotCalled(t, "CreateCustomOption")
+				repo.AssertNotCalled(t, "CreateComparison")
+			})
+		}
+	})
+
+	t.Run("Template not found", func(t *testing.T) {
+		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+
+		templateRepo.On("GetTemplateById", ctx, "0f8d3c8e-4a8b-4c1e-9d3a-6b1f2e7c5a01").
+			Return(nil, fmt.Errorf("template %w", domain.ErrNotFound))
+
+		err := uc.CreateComparison(ctx, domain.Comparison{Name: "My phones"}, "0f8d3c8e-4a8b-4c1e-9d3a-6b1f2e7c5a01")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		assert.NotErrorIs(t, err, domain.ErrNotFound)
+		repo.AssertNotCalled(t, "CreateComparison")
+	})
 }
 
 func TestUpdateComparison(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewComparisonUsecase(repo, generator)
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
 
 		ctx := context.Background()
 
@@ -201,17 +674,41 @@ func TestUpdateComparison(t *testing.T) {
 
 		repo.On("GetComparisonById", ctx, id).Return(returnedComparison, nil)
 		repo.On("UpdateComparison", ctx, changedComparison).Return(nil)
+		generator.On("GenerateId").Return("5435fdgdfg3454dfgd")
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Id == "5435fdgdfg3454dfgd" &&
+				event.EntityId == id &&
+				event.Action == domain.AuditActionUpdate &&
+				assert.ObjectsAreEqual([]domain.FieldChange{{
+					Field:  "custom_option_ids",
+					Before: []string{"43294320fdsfnj13213", "3240312rnwjnj49329"},
+					After:  []string{"23491239dqwe14sddsf", "74329fdsfsdwe13123q"},
+				}}, event.Changes)
+		})).Return(nil)
 
 		err := uc.UpdateComparison(ctx, id, inputComparison)
 
 		assert.NoError(t, err)
 		repo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
 	})
 
 	t.Run("Error", func(t *testing.T) {
 		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewComparisonUsecase(repo, generator)
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
 
 		ctx := context.Background()
 
@@ -246,36 +743,549 @@ func TestUpdateComparison(t *testing.T) {
 	})
 }
 
+func TestCloneComparison(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+		id := "34543dfsdfj32432jewr"
+
+		source := domain.Comparison{
+			Id:              id,
+			Name:            "Laptops 2025",
+			CustomOptionIds: []string{"432230ewrew3424rwe"},
+			OptionWeights: []domain.OptionWeight{
+				{CustomOptionId: "432230ewrew3424rwe", Weight: 2, Direction: domain.ScoreDirectionLowerIsBetter},
+			},
+		}
+
+		repo.On("GetComparisonById", ctx, id).Return(source, nil)
+		generator.On("GenerateId").Return("8d1c2ab0clone").Once()
+		generator.On("GenerateId").Return("1b2c3d4eobject").Once()
+		generator.On("GenerateId").Return("5e6f7a8bphoto").Once()
+		generator.On("GenerateId").Return("5435fdgdfg3454dfgd")
+		objRepo.On("GetObjectsByComparisonId", ctx, id).Return([]domain.Object{
+			{Id: "231934sadas9123deqw", Name: "MacBook Air", Rating: 9, ComparisonId: id},
+		}, nil)
+		custOptObjRepo.On("GetObjectCustomOptionsByObjectIds", ctx, []string{"231934sadas9123deqw"}).
+			Return([]domain.ObjectCustomOption{
+				{ObjectId: "231934sadas9123deqw", CustomOptionId: "432230ewrew3424rwe", Value: "1200"},
+			}, nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{"231934sadas9123deqw"}).Return([]domain.Photo{
+			{Id: "12312sadas123sad", ObjectId: "231934sadas9123deqw", Key: "12312sadas123sad.png", IsCover: true},
+		}, nil)
+		photoStore.On("Get", ctx, "12312sadas123sad_thumb.png").
+			Return(mocks.NewPhotoContent("thumb"), domain.PhotoInfo{Size: 5, ContentType: "image/png"}, nil)
+		photoStore.On("Get", ctx, "12312sadas123sad_medium.png").Return(nil, domain.PhotoInfo{}, domain.ErrNotFound)
+		photoStore.On("Get", ctx, "12312sadas123sad.png").
+			Return(mocks.NewPhotoContent("original"), domain.PhotoInfo{Size: 8, ContentType: "image/png"}, nil)
+		photoStore.On("Put", ctx, "5e6f7a8bphoto_thumb.png", mock.Anything, int64(5), "image/png").Return(nil)
+		photoStore.On("Put", ctx, "5e6f7a8bphoto.png", mock.Anything, int64(8), "image/png").Return(nil)
+		repo.On("CreateComparison", ctx, mock.MatchedBy(func(c domain.Comparison) bool {
+			return c.Id == "8d1c2ab0clone" &&
+				c.Name == "Laptops 2026" &&
+				assert.ObjectsAreEqual(source.CustomOptionIds, c.CustomOptionIds) &&
+				assert.ObjectsAreEqual(source.OptionWeights, c.OptionWeights)
+		})).Return(nil)
+		objRepo.On("CreateObjects", ctx, mock.MatchedBy(func(objects []domain.Object) bool {
+			return len(objects) == 1 &&
+				objects[0].Id == "1b2c3d4eobject" &&
+				objects[0].Name == "MacBook Air" &&
+				objects[0].ComparisonId == "8d1c2ab0clone"
+		})).Return(nil)
+		custOptObjRepo.On("AddObjectCustomOptions", ctx, []domain.ObjectCustomOption{
+			{ObjectId: "1b2c3d4eobject", CustomOptionId: "432230ewrew3424rwe", Value: "1200"},
+		}).Return(nil)
+		photoRepo.On("CreatePhotos", ctx, mock.MatchedBy(func(photos []domain.Photo) bool {
+			return len(photos) == 1 &&
+				photos[0].Id == "5e6f7a8bphoto" &&
+				photos[0].ObjectId == "1b2c3d4eobject" &&
+				photos[0].Key == "5e6f7a8bphoto.png" &&
+				photos[0].IsCover
+		})).Return(nil)
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Entity == domain.AuditEntityComparison &&
+				event.EntityId == "8d1c2ab0clone" &&
+				event.Action == domain.AuditActionCreate
+		})).Return(nil).Once()
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Entity == domain.AuditEntityObject &&
+				event.EntityId == "1b2c3d4eobject" &&
+				slices.Contains(event.Changes, domain.FieldChange{Field: "custom_options.432230ewrew3424rwe", After: "1200"})
+		})).Return(nil).Once()
+
+		cloneId, err := uc.CloneComparison(ctx, id, domain.CloneOptions{
+			Name:           "Laptops 2026",
+			IncludeObjects: true,
+			IncludePhotos:  true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "8d1c2ab0clone", cloneId)
+		assert.Equal(t, 1, transactor.Committed)
+		repo.AssertExpectations(t)
+		objRepo.AssertExpectations(t)
+		custOptObjRepo.AssertExpectations(t)
+		photoRepo.AssertExpectations(t)
+		photoStore.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Without objects", func(t *testing.T) {
+		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+		id := "34543dfsdfj32432jewr"
+
+		repo.On("GetComparisonById", ctx, id).Return(domain.Comparison{Id: id, Name: "Laptops 2025"}, nil)
+		generator.On("GenerateId").Return("8d1c2ab0clone")
+		repo.On("CreateComparison", ctx, mock.MatchedBy(func(c domain.Comparison) bool {
+			return c.Id == "8d1c2ab0clone" && c.Name == "Laptops 2026"
+		})).Return(nil)
+		auditRepo.On("AddAuditEvent", ctx, mock.Anything).Return(nil).Once()
+
+		cloneId, err := uc.CloneComparison(ctx, id, domain.CloneOptions{Name: "Laptops 2026"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "8d1c2ab0clone", cloneId)
+		repo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+		objRepo.AssertNotCalled(t, "GetObjectsByComparisonId")
+		objRepo.AssertNotCalled(t, "CreateObjects")
+	})
+
+	t.Run("Photos without objects", func(t *testing.T) {
+		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+
+		cloneId, err := uc.CloneComparison(ctx, "34543dfsdfj32432jewr", domain.CloneOptions{
+			Name:          "Laptops 2026",
+			IncludePhotos: true,
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		assert.Empty(t, cloneId)
+		repo.AssertNotCalled(t, "GetComparisonById")
+	})
+
+	t.Run("Error removes copied photos", func(t *testing.T) {
+		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+		id := "34543dfsdfj32432jewr"
+
+		repo.On("GetComparisonById", ctx, id).Return(domain.Comparison{Id: id, Name: "Laptops 2025"}, nil)
+		generator.On("GenerateId").Return("8d1c2ab0clone").Once()
+		generator.On("GenerateId").Return("1b2c3d4eobject").Once()
+		generator.On("GenerateId").Return("5e6f7a8bphoto").Once()
+		objRepo.On("GetObjectsByComparisonId", ctx, id).Return([]domain.Object{
+			{Id: "231934sadas9123deqw", Name: "MacBook Air", ComparisonId: id},
+		}, nil)
+		custOptObjRepo.On("GetObjectCustomOptionsByObjectIds", ctx, []string{"231934sadas9123deqw"}).
+			Return([]domain.ObjectCustomOption{}, nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{"231934sadas9123deqw"}).Return([]domain.Photo{
+			{Id: "12312sadas123sad", ObjectId: "231934sadas9123deqw", Key: "12312sadas123sad.png"},
+		}, nil)
+		photoStore.On("Get", ctx, mock.Anything).
+			Return(mocks.NewPhotoContent("photo"), domain.PhotoInfo{Size: 5, ContentType: "image/png"}, nil)
+		photoStore.On("Put", ctx, mock.Anything, mock.Anything, int64(5), "image/png").Return(nil)
+		repo.On("CreateComparison", ctx, mock.Anything).Return(domain.ErrAlreadyExists)
+		photoStore.On("Delete", ctx, "5e6f7a8bphoto_thumb.png").Return(nil)
+		photoStore.On("Delete", ctx, "5e6f7a8bphoto_medium.png").Return(nil)
+		photoStore.On("Delete", ctx, "5e6f7a8bphoto.png").Return(nil)
+
+		cloneId, err := uc.CloneComparison(ctx, id, domain.CloneOptions{
+			Name:           "Laptops 2025",
+			IncludeObjects: true,
+			IncludePhotos:  true,
+		})
+
+		assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+		assert.Empty(t, cloneId)
+		assert.Equal(t, 1, transactor.RolledBack)
+		photoStore.AssertExpectations(t)
+		objRepo.AssertNotCalled(t, "CreateObjects")
+		auditRepo.AssertNotCalled(t, "AddAuditEvent")
+	})
+
+	t.Run("Failed removal of copied photos is reported", func(t *testing.T) {
+		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+		id := "34543dfsdfj32432jewr"
+
+		repo.On("GetComparisonById", ctx, id).Return(domain.Comparison{Id: id, Name: "Laptops 2025"}, nil)
+		generator.On("GenerateId").Return("8d1c2ab0clone").Once()
+		generator.On("GenerateId").Return("1b2c3d4eobject").Once()
+		generator.On("GenerateId").Return("5e6f7a8bphoto").Once()
+		objRepo.On("GetObjectsByComparisonId", ctx, id).Return([]domain.Object{
+			{Id: "231934sadas9123deqw", Name: "MacBook Air", ComparisonId: id},
+		}, nil)
+		custOptObjRepo.On("GetObjectCustomOptionsByObjectIds", ctx, []string{"231934sadas9123deqw"}).
+			Return([]domain.ObjectCustomOption{}, nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{"231934sadas9123deqw"}).Return([]domain.Photo{
+			{Id: "12312sadas123sad", ObjectId: "231934sadas9123deqw", Key: "12312sadas123sad.png"},
+		}, nil)
+		photoStore.On("Get", ctx, mock.Anything).
+			Return(mocks.NewPhotoContent("photo"), domain.PhotoInfo{Size: 5, ContentType: "image/png"}, nil)
+		photoStore.On("Put", ctx, mock.Anything, mock.Anything, int64(5), "image/png").Return(nil)
+		repo.On("CreateComparison", ctx, mock.Anything).Return(domain.ErrAlreadyExists)
+		photoStore.On("Delete", ctx, "5e6f7a8bphoto_thumb.png").Return(nil)
+		photoStore.On("Delete", ctx, "5e6f7a8bphoto_medium.png").Return(fmt.Errorf("storage is unavailable"))
+		photoStore.On("Delete", ctx, "5e6f7a8bphoto.png").Return(nil)
+
+		cloneId, err := uc.CloneComparison(ctx, id, domain.CloneOptions{
+			Name:           "Laptops 2025",
+			IncludeObjects: true,
+			IncludePhotos:  true,
+		})
+
+		assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+		assert.ErrorContains(t, err, "failed to remove copied photo file '5e6f7a8bphoto_medium.png'")
+		assert.Empty(t, cloneId)
+		// the rest of files are removed anyway
+		photoStore.AssertExpectations(t)
+	})
+}
+
 func TestDeleteComparison(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewComparisonUsecase(repo, generator)
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
 
 		ctx := context.Background()
 		id := "34543dfsdfj32432jewr"
 
-		repo.On("DeleteComparison", ctx, id).Return(nil)
+		returnedObjects := []domain.Object{
+			{Id: "231934sadas9123deqw", Name: "BMW X5", ComparisonId: id},
+			{Id: "9123deqw231934sadas", Name: "Audi Q7", ComparisonId: id},
+		}
+
+		var comparisonDeletedAt, objectsDeletedAt time.Time
+
+		repo.On("GetComparisonById", ctx, id).Return(domain.Comparison{Id: id, Name: "Cars"}, nil)
+		objRepo.On("GetObjectsByComparisonId", ctx, id).Return(returnedObjects, nil)
+		repo.On("DeleteComparison", ctx, id, mock.AnythingOfType("time.Time")).
+			Run(func(args mock.Arguments) { comparisonDeletedAt = args.Get(2).(time.Time) }).
+			Return(nil)
+		generator.On("GenerateId").Return("5435fdgdfg3454dfgd")
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Entity == domain.AuditEntityComparison &&
+				event.Action == domain.AuditActionDelete &&
+				assert.ObjectsAreEqual([]domain.FieldChange{{Field: "name", Before: "Cars"}}, event.Changes)
+		})).Return(nil).Once()
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Entity == domain.AuditEntityObject && event.Action == domain.AuditActionDelete
+		})).Return(nil).Twice()
+		objRepo.On("DeleteObjectsByComparisonId", ctx, id, mock.AnythingOfType("time.Time")).
+			Run(func(args mock.Arguments) { objectsDeletedAt = args.Get(2).(time.Time) }).
+			Return(nil)
 
 		err := uc.DeleteComparison(ctx, id)
 
 		assert.NoError(t, err)
+		assert.False(t, comparisonDeletedAt.IsZero())
+		assert.Equal(t, comparisonDeletedAt, objectsDeletedAt)
+		assert.Equal(t, 1, transactor.Committed)
 		repo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+		objRepo.AssertExpectations(t)
+	})
+
+	t.Run("Without objects", func(t *testing.T) {
+		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+		id := "34543dfsdfj32432jewr"
+
+		repo.On("GetComparisonById", ctx, id).Return(domain.Comparison{Id: id, Name: "Cars"}, nil)
+		objRepo.On("GetObjectsByComparisonId", ctx, id).Return([]domain.Object{}, nil)
+		repo.On("DeleteComparison", ctx, id, mock.AnythingOfType("time.Time")).Return(nil)
+		generator.On("GenerateId").Return("5435fdgdfg3454dfgd")
+		auditRepo.On("AddAuditEvent", ctx, mock.Anything).Return(nil)
+
+		err := uc.DeleteComparison(ctx, id)
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		objRepo.AssertNotCalled(t, "DeleteObjectsByComparisonId")
 	})
 
 	t.Run("Error", func(t *testing.T) {
 		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewComparisonUsecase(repo, generator)
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
 
 		ctx := context.Background()
 		id := "92133easd123srewr132"
 
-		repo.On("DeleteComparison", ctx, id).Return(assert.AnError)
+		repo.On("GetComparisonById", ctx, id).Return(domain.Comparison{Id: id, Name: "Cars"}, nil)
+		objRepo.On("GetObjectsByComparisonId", ctx, id).Return([]domain.Object{{Id: "231934sadas9123deqw"}}, nil)
+		repo.On("DeleteComparison", ctx, id, mock.AnythingOfType("time.Time")).Return(assert.AnError)
 
 		err := uc.DeleteComparison(ctx, id)
 
 		assert.Error(t, err)
+		assert.Equal(t, 1, transactor.RolledBack)
+		repo.AssertExpectations(t)
+		auditRepo.AssertNotCalled(t, "AddAuditEvent")
+		objRepo.AssertNotCalled(t, "DeleteObjectsByComparisonId")
+	})
+}
+
+func TestRestoreComparison(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+		id := "34543dfsdfj32432jewr"
+		deletedComparison := domain.Comparison{Id: id, Name: "Cars", DeletedAt: time.Now()}
+
+		repo.On("GetDeletedComparisonById", ctx, id).Return(deletedComparison, nil)
+		objRepo.On("GetObjectsDeletedWithComparison", ctx, id).Return([]domain.Object{
+			{Id: "231934sadas9123deqw", Name: "BMW X5", ComparisonId: id},
+		}, nil)
+		repo.On("RestoreComparison", ctx, deletedComparison).Return(nil)
+		objRepo.On("RestoreObjectsByComparisonId", ctx, id).Return(nil)
+		generator.On("GenerateId").Return("5435fdgdfg3454dfgd")
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Entity == domain.AuditEntityComparison &&
+				event.Action == domain.AuditActionRestore &&
+				assert.ObjectsAreEqual([]domain.FieldChange{{Field: "name", After: "Cars"}}, event.Changes)
+		})).Return(nil).Once()
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Entity == domain.AuditEntityObject &&
+				event.EntityId == "231934sadas9123deqw" &&
+				event.Action == domain.AuditActionRestore
+		})).Return(nil).Once()
+
+		err := uc.RestoreComparison(ctx, id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, transactor.Committed)
 		repo.AssertExpectations(t)
+		objRepo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Folder is gone", func(t *testing.T) {
+		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+		id := "34543dfsdfj32432jewr"
+		deletedComparison := domain.Comparison{Id: id, Name: "Cars", FolderId: "9a8b7c6d5e4f3a2b", DeletedAt: time.Now()}
+
+		repo.On("GetDeletedComparisonById", ctx, id).Return(deletedComparison, nil)
+		objRepo.On("GetObjectsDeletedWithComparison", ctx, id).Return([]domain.Object{}, nil)
+		folderRepo.On("GetFolderById", ctx, "9a8b7c6d5e4f3a2b").Return(nil, fmt.Errorf("folder %w", domain.ErrNotFound))
+		repo.On("RestoreComparison", ctx, mock.MatchedBy(func(comparison domain.Comparison) bool {
+			return comparison.Id == id && comparison.FolderId == ""
+		})).Return(nil)
+		generator.On("GenerateId").Return("5435fdgdfg3454dfgd")
+		auditRepo.On("AddAuditEvent", ctx, mock.Anything).Return(nil)
+
+		err := uc.RestoreComparison(ctx, id)
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Name is taken", func(t *testing.T) {
+		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+		id := "34543dfsdfj32432jewr"
+		deletedComparison := domain.Comparison{Id: id, Name: "Cars", DeletedAt: time.Now()}
+
+		repo.On("GetDeletedComparisonById", ctx, id).Return(deletedComparison, nil)
+		objRepo.On("GetObjectsDeletedWithComparison", ctx, id).Return([]domain.Object{}, nil)
+		repo.On("RestoreComparison", ctx, deletedComparison).Return(domain.ErrAlreadyExists)
+
+		err := uc.RestoreComparison(ctx, id)
+
+		assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+		assert.Equal(t, 1, transactor.RolledBack)
+		objRepo.AssertNotCalled(t, "RestoreObjectsByComparisonId")
+		auditRepo.AssertNotCalled(t, "AddAuditEvent")
+	})
+
+	t.Run("Not in trash", func(t *testing.T) {
+		repo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		templateRepo := mocks.NewTemplateRepositoryMock()
+		folderRepo := mocks.NewFolderRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewComparisonUsecase(
+			repo, objRepo, custOptObjRepo, custOptRepo, photoRepo, templateRepo, folderRepo,
+			auditRepo, photoStore, transactor, generator,
+		)
+
+		ctx := context.Background()
+		id := "34543dfsdfj32432jewr"
+
+		repo.On("GetDeletedComparisonById", ctx, id).Return(nil, domain.ErrNotFound)
+
+		err := uc.RestoreComparison(ctx, id)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		repo.AssertNotCalled(t, "RestoreComparison")
 	})
 }
@@ -3,44 +3,86 @@ package customoption
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/Unlites/comparison_center/backend/internal/domain"
 )
 
 type CustomOptionUsecase struct {
-	repo      CustomOptionRepository
-	generator IdGenerator
+	repo           CustomOptionRepository
+	comparisonRepo ComparisonRepository
+	objRepo        ObjectRepository
+	custOptObjRepo ObjectCustomOptionRepository
+	auditRepo      AuditRepository
+	transactor     Transactor
+	generator      IdGenerator
 }
 
 type CustomOptionRepository interface {
-	GetCustomOptions(ctx context.Context, filter domain.CustomOptionFilter) ([]domain.CustomOption, error)
+	GetCustomOptions(ctx context.Context, filter domain.CustomOptionFilter) ([]domain.CustomOption, domain.PageInfo, error)
 	GetCustomOptionById(ctx context.Context, id string) (domain.CustomOption, error)
 	UpdateCustomOption(ctx context.Context, customOption domain.CustomOption) error
 	CreateCustomOption(ctx context.Context, customOption domain.CustomOption) error
 	DeleteCustomOption(ctx context.Context, id string) error
 }
 
+type ComparisonRepository interface {
+	GetComparisonsByCustomOptionId(ctx context.Context, customOptionId string) ([]domain.Comparison, error)
+}
+
+type ObjectRepository interface {
+	UpdateObjectsOptionValues(ctx context.Context, objects []domain.Object) error
+}
+
+type ObjectCustomOptionRepository interface {
+	GetObjectCustomOptionsByCustomOptionId(ctx context.Context, customOptionId string) ([]domain.ObjectCustomOption, error)
+	GetObjectCustomOptionsByObjectIds(ctx context.Context, objectIds []string) ([]domain.ObjectCustomOption, error)
+	DeleteObjectCustomOptionsByCustomOptionId(ctx context.Context, customOptionId string) error
+}
+
+type AuditRepository interface {
+	AddAuditEvent(ctx context.Context, event domain.AuditEvent) error
+}
+
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type IdGenerator interface {
 	GenerateId() string
 }
 
 func NewCustomOptionUsecase(
 	repo CustomOptionRepository,
+	comparisonRepo ComparisonRepository,
+	objRepo ObjectRepository,
+	custOptObjRepo ObjectCustomOptionRepository,
+	auditRepo AuditRepository,
+	transactor Transactor,
 	generator IdGenerator,
 ) *CustomOptionUsecase {
-	return &CustomOptionUsecase{repo: repo, generator: generator}
+	return &CustomOptionUsecase{
+		repo:           repo,
+		comparisonRepo: comparisonRepo,
+		objRepo:        objRepo,
+		custOptObjRepo: custOptObjRepo,
+		auditRepo:      auditRepo,
+		transactor:     transactor,
+		generator:      generator,
+	}
 }
 
 func (uc *CustomOptionUsecase) GetCustomOptions(
 	ctx context.Context,
 	filter domain.CustomOptionFilter,
-) ([]domain.CustomOption, error) {
-	customOptions, err := uc.repo.GetCustomOptions(ctx, filter)
+) ([]domain.CustomOption, domain.PageInfo, error) {
+	customOptions, pageInfo, err := uc.repo.GetCustomOptions(ctx, filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get custom options - %w", err)
+		return nil, domain.PageInfo{}, fmt.Errorf("failed to get custom options - %w", err)
 	}
 
-	return customOptions, nil
+	return customOptions, pageInfo, nil
 }
 
 func (uc *CustomOptionUsecase) GetCustomOptionById(
@@ -65,10 +107,65 @@ func (uc *CustomOptionUsecase) UpdateCustomOption(
 		return fmt.Errorf("failed to get existing custom option - %w", err)
 	}
 
+	// type and currency are fixed once values may have been stored for them
 	customOption.Id = existingCustomOption.Id
+	customOption.Type = existingCustomOption.Type
+	customOption.Currency = existingCustomOption.Currency
 
-	if err := uc.repo.UpdateCustomOption(ctx, customOption); err != nil {
-		return fmt.Errorf("failed to update custom option - %w", err)
+	if err := customOption.Validate(); err != nil {
+		return fmt.Errorf("invalid custom option - %w", err)
+	}
+
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.checkRemovedEnumValues(ctx, existingCustomOption, customOption); err != nil {
+			return err
+		}
+
+		if err := uc.repo.UpdateCustomOption(ctx, customOption); err != nil {
+			return fmt.Errorf("failed to update custom option - %w", err)
+		}
+
+		return uc.recordChange(ctx, customOption.Id, existingCustomOption.AuditFields(), customOption.AuditFields())
+	})
+}
+
+// checkRemovedEnumValues makes sure values removed from the enum are not set to any object,
+// otherwise domain.ErrConflict listing them is returned.
+func (uc *CustomOptionUsecase) checkRemovedEnumValues(
+	ctx context.Context,
+	existing, updated domain.CustomOption,
+) error {
+	if existing.Type != domain.CustomOptionTypeEnum {
+		return nil
+	}
+
+	removed := slices.DeleteFunc(slices.Clone(existing.EnumValues), func(value string) bool {
+		return slices.Contains(updated.EnumValues, value)
+	})
+	if len(removed) == 0 {
+		return nil
+	}
+
+	objCustomOptions, err := uc.custOptObjRepo.GetObjectCustomOptionsByCustomOptionId(ctx, existing.Id)
+	if err != nil {
+		return fmt.Errorf("failed to get values of custom option - %w", err)
+	}
+
+	inUse := make([]string, 0)
+	for _, value := range removed {
+		if slices.ContainsFunc(objCustomOptions, func(oco domain.ObjectCustomOption) bool {
+			return oco.Value == value
+		}) {
+			inUse = append(inUse, value)
+		}
+	}
+
+	if len(inUse) != 0 {
+		return fmt.Errorf(
+			"enum values '%s' are set to objects and can not be removed - %w",
+			strings.Join(inUse, "', '"),
+			domain.ErrConflict,
+		)
 	}
 
 	return nil
@@ -78,17 +175,119 @@ func (uc *CustomOptionUsecase) CreateCustomOption(
 	ctx context.Context,
 	customOption domain.CustomOption,
 ) error {
+	if customOption.Type == "" {
+		customOption.Type = domain.CustomOptionTypeText
+	}
+
+	if err := customOption.Validate(); err != nil {
+		return fmt.Errorf("invalid custom option - %w", err)
+	}
+
 	customOption.Id = uc.generator.GenerateId()
-	if err := uc.repo.CreateCustomOption(ctx, customOption); err != nil {
-		return fmt.Errorf("failed to create custom option - %w", err)
+
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.repo.CreateCustomOption(ctx, customOption); err != nil {
+			return fmt.Errorf("failed to create custom option - %w", err)
+		}
+
+		return uc.recordChange(ctx, customOption.Id, nil, customOption.AuditFields())
+	})
+}
+
+// DeleteCustomOption deletes the custom option and values of it set to objects.
+// Custom option that is still used by comparisons can not be deleted,
+// in that case *domain.DependentsError listing these comparisons is returned.
+func (uc *CustomOptionUsecase) DeleteCustomOption(ctx context.Context, id string) error {
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		customOption, err := uc.repo.GetCustomOptionById(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get custom option - %w", err)
+		}
+
+		comparisons, err := uc.comparisonRepo.GetComparisonsByCustomOptionId(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get comparisons - %w", err)
+		}
+
+		if len(comparisons) != 0 {
+			dependents := make([]domain.Dependent, len(comparisons))
+			for i, c := range comparisons {
+				dependents[i] = domain.Dependent{Type: "comparison", Id: c.Id, Name: c.Name}
+			}
+
+			return fmt.Errorf("failed to delete custom option - %w", &domain.DependentsError{
+				Entity:     "custom option",
+				Dependents: dependents,
+			})
+		}
+
+		if err := uc.repo.DeleteCustomOption(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete custom option - %w", err)
+		}
+
+		if err := uc.deleteObjectCustomOptions(ctx, id); err != nil {
+			return err
+		}
+
+		return uc.recordChange(ctx, customOption.Id, customOption.AuditFields(), nil)
+	})
+}
+
+// deleteObjectCustomOptions deletes values of the custom option set to objects
+// and updates values of custom options kept in these objects for the full-text search.
+func (uc *CustomOptionUsecase) deleteObjectCustomOptions(ctx context.Context, customOptionId string) error {
+	deleted, err := uc.custOptObjRepo.GetObjectCustomOptionsByCustomOptionId(ctx, customOptionId)
+	if err != nil {
+		return fmt.Errorf("failed to get object custom options - %w", err)
+	}
+
+	if err := uc.custOptObjRepo.DeleteObjectCustomOptionsByCustomOptionId(ctx, customOptionId); err != nil {
+		return fmt.Errorf("failed to delete object custom options - %w", err)
+	}
+
+	if len(deleted) == 0 {
+		return nil
+	}
+
+	objectIds := make([]string, len(deleted))
+	for i, oco := range deleted {
+		objectIds[i] = oco.ObjectId
+	}
+
+	remaining, err := uc.custOptObjRepo.GetObjectCustomOptionsByObjectIds(ctx, objectIds)
+	if err != nil {
+		return fmt.Errorf("failed to get object custom options - %w", err)
+	}
+
+	optionsByObjectId := make(map[string][]domain.ObjectCustomOption, len(objectIds))
+	for _, oco := range remaining {
+		optionsByObjectId[oco.ObjectId] = append(optionsByObjectId[oco.ObjectId], oco)
+	}
+
+	objects := make([]domain.Object, len(objectIds))
+	for i, objectId := range objectIds {
+		objects[i] = domain.Object{Id: objectId, ObjectCustomOptions: optionsByObjectId[objectId]}
+	}
+
+	if err := uc.objRepo.UpdateObjectsOptionValues(ctx, objects); err != nil {
+		return fmt.Errorf("failed to update option values of objects - %w", err)
 	}
 
 	return nil
 }
 
-func (uc *CustomOptionUsecase) DeleteCustomOption(ctx context.Context, id string) error {
-	if err := uc.repo.DeleteCustomOption(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete custom option - %w", err)
+// recordChange adds the change of the custom option to the audit log,
+// updates that changed nothing are not recorded.
+func (uc *CustomOptionUsecase) recordChange(ctx context.Context, id string, before, after []domain.AuditField) error {
+	event := domain.NewAuditEvent(domain.AuditEntityCustomOption, id, before, after)
+	if event.Action == domain.AuditActionUpdate && len(event.Changes) == 0 {
+		return nil
+	}
+
+	event.Id = uc.generator.GenerateId()
+
+	if err := uc.auditRepo.AddAuditEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to add audit event - %w", err)
 	}
 
 	return nil
@@ -7,13 +7,19 @@ import (
 	"github.com/Unlites/comparison_center/backend/internal/domain"
 	"github.com/Unlites/comparison_center/backend/internal/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestCustomOptions(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewCustomOptionUsecase(repo, generator)
+		uc := NewCustomOptionUsecase(repo, comparisonRepo, objRepo, custOptObjRepo, auditRepo, transactor, generator)
 
 		ctx := context.Background()
 
@@ -33,19 +39,27 @@ func TestCustomOptions(t *testing.T) {
 			},
 		}
 
-		repo.On("GetCustomOptions", ctx, filter).Return(returnedCustomOptions, nil)
+		returnedPageInfo := domain.PageInfo{Total: 2}
 
-		customOptions, err := uc.GetCustomOptions(ctx, filter)
+		repo.On("GetCustomOptions", ctx, filter).Return(returnedCustomOptions, returnedPageInfo, nil)
+
+		customOptions, pageInfo, err := uc.GetCustomOptions(ctx, filter)
 
 		assert.NoError(t, err)
 		assert.Equal(t, customOptions, returnedCustomOptions)
+		assert.Equal(t, returnedPageInfo, pageInfo)
 		repo.AssertExpectations(t)
 	})
 
 	t.Run("Error", func(t *testing.T) {
 		repo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewCustomOptionUsecase(repo, generator)
+		uc := NewCustomOptionUsecase(repo, comparisonRepo, objRepo, custOptObjRepo, auditRepo, transactor, generator)
 
 		ctx := context.Background()
 
@@ -54,9 +68,9 @@ func TestCustomOptions(t *testing.T) {
 			Offset: 0,
 		}
 
-		repo.On("GetCustomOptions", ctx, filter).Return(nil, assert.AnError)
+		repo.On("GetCustomOptions", ctx, filter).Return(nil, nil, assert.AnError)
 
-		customOptions, err := uc.GetCustomOptions(ctx, filter)
+		customOptions, _, err := uc.GetCustomOptions(ctx, filter)
 
 		assert.Nil(t, customOptions)
 		assert.Error(t, err)
@@ -68,34 +82,56 @@ func TestCustomOptions(t *testing.T) {
 func TestCreateCustomOption(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewCustomOptionUsecase(repo, generator)
+		uc := NewCustomOptionUsecase(repo, comparisonRepo, objRepo, custOptObjRepo, auditRepo, transactor, generator)
 
 		ctx := context.Background()
 
 		inputCustomOption := domain.CustomOption{
 			Id:   "190324fdsjfn123213",
 			Name: "Speed",
+			Type: domain.CustomOptionTypeInteger,
 		}
 
 		repo.On("CreateCustomOption", ctx, inputCustomOption).Return(nil)
 		generator.On("GenerateId").Return("190324fdsjfn123213")
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Entity == domain.AuditEntityCustomOption &&
+				event.EntityId == "190324fdsjfn123213" &&
+				event.Action == domain.AuditActionCreate &&
+				assert.ObjectsAreEqual([]domain.FieldChange{
+					{Field: "name", After: "Speed"},
+					{Field: "type", After: "integer"},
+				}, event.Changes)
+		})).Return(nil)
 
 		err := uc.CreateCustomOption(ctx, inputCustomOption)
 
 		assert.NoError(t, err)
+		auditRepo.AssertExpectations(t)
 	})
 
 	t.Run("Error", func(t *testing.T) {
 		repo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewCustomOptionUsecase(repo, generator)
+		uc := NewCustomOptionUsecase(repo, comparisonRepo, objRepo, custOptObjRepo, auditRepo, transactor, generator)
 
 		ctx := context.Background()
 
 		inputCustomOption := domain.CustomOption{
 			Id:   "190324fdsjfn123213",
 			Name: "Speed",
+			Type: domain.CustomOptionTypeInteger,
 		}
 
 		repo.On("CreateCustomOption", ctx, inputCustomOption).Return(assert.AnError)
@@ -105,49 +141,274 @@ func TestCreateCustomOption(t *testing.T) {
 
 		assert.Error(t, err)
 	})
+
+	t.Run("Invalid type settings", func(t *testing.T) {
+		repo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewCustomOptionUsecase(repo, comparisonRepo, objRepo, custOptObjRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		inputCustomOption := domain.CustomOption{
+			Name: "Color",
+			Type: domain.CustomOptionTypeEnum,
+		}
+
+		err := uc.CreateCustomOption(ctx, inputCustomOption)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		repo.AssertNotCalled(t, "CreateCustomOption")
+	})
+}
+
+func TestUpdateCustomOption(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewCustomOptionUsecase(repo, comparisonRepo, objRepo, custOptObjRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		id := "190324fdsjfn123213"
+
+		repo.On("GetCustomOptionById", ctx, id).Return(domain.CustomOption{
+			Id: id, Name: "Speed", Type: domain.CustomOptionTypeInteger,
+		}, nil)
+		repo.On("UpdateCustomOption", ctx, domain.CustomOption{
+			Id: id, Name: "Max speed", Type: domain.CustomOptionTypeInteger,
+		}).Return(nil)
+		generator.On("GenerateId").Return("5435fdgdfg3454dfgd")
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Id == "5435fdgdfg3454dfgd" &&
+				event.Action == domain.AuditActionUpdate &&
+				assert.ObjectsAreEqual([]domain.FieldChange{
+					{Field: "name", Before: "Speed", After: "Max speed"},
+				}, event.Changes)
+		})).Return(nil)
+
+		err := uc.UpdateCustomOption(ctx, id, domain.CustomOption{Name: "Max speed", Type: domain.CustomOptionTypeText})
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Nothing changed", func(t *testing.T) {
+		repo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewCustomOptionUsecase(repo, comparisonRepo, objRepo, custOptObjRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		customOption := domain.CustomOption{Id: "190324fdsjfn123213", Name: "Speed", Type: domain.CustomOptionTypeText}
+
+		repo.On("GetCustomOptionById", ctx, customOption.Id).Return(customOption, nil)
+		repo.On("UpdateCustomOption", ctx, customOption).Return(nil)
+
+		err := uc.UpdateCustomOption(ctx, customOption.Id, domain.CustomOption{Name: "Speed"})
+
+		assert.NoError(t, err)
+		auditRepo.AssertNotCalled(t, "AddAuditEvent")
+	})
+
+	t.Run("Removed enum values are not used", func(t *testing.T) {
+		repo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewCustomOptionUsecase(repo, comparisonRepo, objRepo, custOptObjRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		id := "190324fdsjfn123213"
+
+		repo.On("GetCustomOptionById", ctx, id).Return(domain.CustomOption{
+			Id: id, Name: "Color", Type: domain.CustomOptionTypeEnum, EnumValues: []string{"red", "green", "blue"},
+		}, nil)
+		custOptObjRepo.On("GetObjectCustomOptionsByCustomOptionId", ctx, id).Return([]domain.ObjectCustomOption{
+			{ObjectId: "2314sdfsdf123123", CustomOptionId: id, Value: "red"},
+		}, nil)
+		repo.On("UpdateCustomOption", ctx, domain.CustomOption{
+			Id: id, Name: "Color", Type: domain.CustomOptionTypeEnum, EnumValues: []string{"red", "blue"},
+		}).Return(nil)
+		generator.On("GenerateId").Return("5435fdgdfg3454dfgd")
+		auditRepo.On("AddAuditEvent", ctx, mock.Anything).Return(nil)
+
+		err := uc.UpdateCustomOption(ctx, id, domain.CustomOption{Name: "Color", EnumValues: []string{"red", "blue"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, transactor.Committed)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Removed enum values are in use", func(t *testing.T) {
+		repo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewCustomOptionUsecase(repo, comparisonRepo, objRepo, custOptObjRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		id := "190324fdsjfn123213"
+
+		repo.On("GetCustomOptionById", ctx, id).Return(domain.CustomOption{
+			Id: id, Name: "Color", Type: domain.CustomOptionTypeEnum, EnumValues: []string{"red", "green", "blue"},
+		}, nil)
+		custOptObjRepo.On("GetObjectCustomOptionsByCustomOptionId", ctx, id).Return([]domain.ObjectCustomOption{
+			{ObjectId: "2314sdfsdf123123", CustomOptionId: id, Value: "red"},
+			{ObjectId: "9123fdsf2314sdfs", CustomOptionId: id, Value: "green"},
+		}, nil)
+
+		err := uc.UpdateCustomOption(ctx, id, domain.CustomOption{Name: "Color", EnumValues: []string{"red", "blue"}})
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.ErrorContains(t, err, "'green'")
+		assert.Equal(t, 1, transactor.RolledBack)
+		repo.AssertNotCalled(t, "UpdateCustomOption")
+		auditRepo.AssertNotCalled(t, "AddAuditEvent")
+	})
 }
 
 func TestDeleteCustomOption(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewCustomOptionUsecase(repo, generator)
+		uc := NewCustomOptionUsecase(repo, comparisonRepo, objRepo, custOptObjRepo, auditRepo, transactor, generator)
 
 		ctx := context.Background()
 
 		id := "190324fdsjfn123213"
 
+		repo.On("GetCustomOptionById", ctx, id).Return(domain.CustomOption{
+			Id: id, Name: "Speed", Type: domain.CustomOptionTypeInteger,
+		}, nil)
+		comparisonRepo.On("GetComparisonsByCustomOptionId", ctx, id).Return([]domain.Comparison{}, nil)
 		repo.On("DeleteCustomOption", ctx, id).Return(nil)
+		custOptObjRepo.On("GetObjectCustomOptionsByCustomOptionId", ctx, id).Return([]domain.ObjectCustomOption{
+			{ObjectId: "231934sadas9123deqw", CustomOptionId: id, Value: "250"},
+		}, nil)
+		custOptObjRepo.On("DeleteObjectCustomOptionsByCustomOptionId", ctx, id).Return(nil)
+		custOptObjRepo.On("GetObjectCustomOptionsByObjectIds", ctx, []string{"231934sadas9123deqw"}).
+			Return([]domain.ObjectCustomOption{
+				{ObjectId: "231934sadas9123deqw", CustomOptionId: "432230ewrew3424rwe", Value: "600"},
+			}, nil)
+		objRepo.On("UpdateObjectsOptionValues", ctx, []domain.Object{{
+			Id: "231934sadas9123deqw",
+			ObjectCustomOptions: []domain.ObjectCustomOption{
+				{ObjectId: "231934sadas9123deqw", CustomOptionId: "432230ewrew3424rwe", Value: "600"},
+			},
+		}}).Return(nil)
+		generator.On("GenerateId").Return("5435fdgdfg3454dfgd")
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.EntityId == id && event.Action == domain.AuditActionDelete
+		})).Return(nil)
 
 		err := uc.DeleteCustomOption(ctx, id)
 
 		assert.NoError(t, err)
+		auditRepo.AssertExpectations(t)
 
 		repo.AssertExpectations(t)
+		comparisonRepo.AssertExpectations(t)
+		custOptObjRepo.AssertExpectations(t)
+		objRepo.AssertExpectations(t)
+	})
+
+	t.Run("Used by comparisons", func(t *testing.T) {
+		repo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewCustomOptionUsecase(repo, comparisonRepo, objRepo, custOptObjRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		id := "190324fdsjfn123213"
+
+		repo.On("GetCustomOptionById", ctx, id).Return(domain.CustomOption{Id: id, Name: "Speed"}, nil)
+		comparisonRepo.On("GetComparisonsByCustomOptionId", ctx, id).Return([]domain.Comparison{
+			{Id: "85434230werhuhi123912304", Name: "Cars", CustomOptionIds: []string{id}},
+		}, nil)
+
+		err := uc.DeleteCustomOption(ctx, id)
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+
+		var dependentsErr *domain.DependentsError
+		assert.ErrorAs(t, err, &dependentsErr)
+		assert.Equal(t, []domain.Dependent{
+			{Type: "comparison", Id: "85434230werhuhi123912304", Name: "Cars"},
+		}, dependentsErr.Dependents)
+
+		repo.AssertNotCalled(t, "DeleteCustomOption")
+		custOptObjRepo.AssertNotCalled(t, "DeleteObjectCustomOptionsByCustomOptionId")
 	})
 
 	t.Run("Error", func(t *testing.T) {
 		repo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewCustomOptionUsecase(repo, generator)
+		uc := NewCustomOptionUsecase(repo, comparisonRepo, objRepo, custOptObjRepo, auditRepo, transactor, generator)
 
 		ctx := context.Background()
 
 		id := "190324fdsjfn123213"
 
+		repo.On("GetCustomOptionById", ctx, id).Return(domain.CustomOption{Id: id, Name: "Speed"}, nil)
+		comparisonRepo.On("GetComparisonsByCustomOptionId", ctx, id).Return([]domain.Comparison{}, nil)
 		repo.On("DeleteCustomOption", ctx, id).Return(assert.AnError)
 
 		err := uc.DeleteCustomOption(ctx, id)
 
 		assert.Error(t, err)
+		custOptObjRepo.AssertNotCalled(t, "DeleteObjectCustomOptionsByCustomOptionId")
 	})
 }
 
 func TestGetCustomOptionById(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewCustomOptionUsecase(repo, generator)
+		uc := NewCustomOptionUsecase(repo, comparisonRepo, objRepo, custOptObjRepo, auditRepo, transactor, generator)
 
 		ctx := context.Background()
 
@@ -170,8 +431,13 @@ func TestGetCustomOptionById(t *testing.T) {
 
 	t.Run("Error", func(t *testing.T) {
 		repo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewCustomOptionUsecase(repo, generator)
+		uc := NewCustomOptionUsecase(repo, comparisonRepo, objRepo, custOptObjRepo, auditRepo, transactor, generator)
 
 		ctx := context.Background()
 
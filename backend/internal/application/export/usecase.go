@@ -0,0 +1,412 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// documentVersion is increased on incompatible changes of the JSON export document.
+const documentVersion = 1
+
+const sheetName = "Objects"
+
+// utf8BOM is written by spreadsheet editors at the beginning of CSV files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+var contentTypes = map[domain.ExportFormat]string{
+	domain.ExportFormatCSV:  "text/csv; charset=utf-8",
+	domain.ExportFormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	domain.ExportFormatJSON: "application/json; charset=utf-8",
+}
+
+// ExportUsecase encodes comparison matrices to files and reads tables of objects from
+// files of the same formats, so exported comparisons can be imported back.
+type ExportUsecase struct{}
+
+func NewExportUsecase() *ExportUsecase {
+	return &ExportUsecase{}
+}
+
+// document holds everything needed to recreate the comparison, so it can be imported back.
+type document struct {
+	Version       int                `json:"version"`
+	ExportedAt    time.Time          `json:"exported_at"`
+	Comparison    documentComparison `json:"comparison"`
+	CustomOptions []documentOption   `json:"custom_options"`
+	Objects       []documentObject   `json:"objects"`
+}
+
+type documentComparison struct {
+	Id              string                 `json:"id"`
+	Name            string                 `json:"name"`
+	CreatedAt       time.Time              `json:"created_at"`
+	CustomOptionIds []string               `json:"custom_option_ids"`
+	OptionWeights   []documentOptionWeight `json:"option_weights"`
+	Tags            []string               `json:"tags"`
+	FolderId        string                 `json:"folder_id"`
+}
+
+type documentOptionWeight struct {
+	CustomOptionId string  `json:"custom_option_id"`
+	Weight         float64 `json:"weight"`
+	Direction      string  `json:"direction"`
+}
+
+type documentOption struct {
+	Id         string   `json:"id"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	EnumValues []string `json:"enum_values,omitempty"`
+	Currency   string   `json:"currency,omitempty"`
+}
+
+type documentObject struct {
+	Id            string                `json:"id"`
+	Name          string                `json:"name"`
+	Rating        int                   `json:"rating"`
+	Advs          string                `json:"advs"`
+	Disadvs       string                `json:"disadvs"`
+	CreatedAt     time.Time             `json:"created_at"`
+	CustomOptions []documentOptionValue `json:"custom_options"`
+}
+
+type documentOptionValue struct {
+	CustomOptionId string `json:"custom_option_id"`
+	Value          string `json:"value"`
+}
+
+// ExportMatrix encodes the matrix to the file of the format.
+func (uc *ExportUsecase) ExportMatrix(
+	matrix domain.ComparisonMatrix,
+	format domain.ExportFormat,
+) (domain.ExportFile, error) {
+	var (
+		content []byte
+		err     error
+	)
+
+	switch format {
+	case domain.ExportFormatCSV:
+		content, err = encodeCSV(matrix)
+	case domain.ExportFormatXLSX:
+		content, err = encodeXLSX(matrix)
+	case domain.ExportFormatJSON:
+		content, err = encodeJSON(matrix)
+	default:
+		return domain.ExportFile{}, fmt.Errorf("unsupported export format '%s' - %w", format, domain.ErrInvalidValue)
+	}
+	if err != nil {
+		return domain.ExportFile{}, fmt.Errorf("failed to encode %s - %w", format, err)
+	}
+
+	return domain.ExportFile{
+		Name:        matrix.Comparison.Name,
+		Format:      format,
+		ContentType: contentTypes[format],
+		Content:     content,
+	}, nil
+}
+
+// ReadTable reads the table of objects from the file of the format. Tables of JSON documents
+// carry definitions of their custom options, so options can be created with their types.
+// Malformed files are reported with domain.ErrInvalidValue.
+func (uc *ExportUsecase) ReadTable(format domain.ExportFormat, r io.Reader) (domain.ImportTable, error) {
+	var (
+		table domain.ImportTable
+		err   error
+	)
+
+	switch format {
+	case domain.ExportFormatCSV:
+		table, err = readCSVTable(r)
+	case domain.ExportFormatXLSX:
+		table, err = readXLSXTable(r)
+	case domain.ExportFormatJSON:
+		table, err = readJSONTable(r)
+	default:
+		return domain.ImportTable{}, fmt.Errorf("unsupported import format '%s' - %w", format, domain.ErrInvalidValue)
+	}
+	if err != nil {
+		return domain.ImportTable{}, fmt.Errorf("failed to read %s - %s - %w", format, err, domain.ErrInvalidValue)
+	}
+
+	return table, nil
+}
+
+// header names columns of tabular exports: fields of the object followed by custom options.
+func header(matrix domain.ComparisonMatrix) []string {
+	header := []string{"name", "rating", "advs", "disadvs", "created_at"}
+	for _, co := range matrix.CustomOptions {
+		header = append(header, co.Name)
+	}
+
+	return header
+}
+
+func encodeCSV(matrix domain.ComparisonMatrix) ([]byte, error) {
+	var buf bytes.Buffer
+
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(header(matrix)); err != nil {
+		return nil, fmt.Errorf("failed to write header - %w", err)
+	}
+
+	for _, row := range matrix.Rows {
+		record := []string{
+			row.Object.Name,
+			strconv.Itoa(row.Object.Rating),
+			row.Object.Advs,
+			row.Object.Disadvs,
+			row.Object.CreatedAt.Format(time.RFC3339),
+		}
+
+		if err := writer.Write(append(record, row.Values...)); err != nil {
+			return nil, fmt.Errorf("failed to write row - %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("failed to flush - %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func encodeXLSX(matrix domain.ComparisonMatrix) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName(f.GetSheetName(0), sheetName); err != nil {
+		return nil, fmt.Errorf("failed to set sheet name - %w", err)
+	}
+
+	names := header(matrix)
+	cells := make([]any, len(names))
+	for i, name := range names {
+		cells[i] = name
+	}
+
+	if err := f.SetSheetRow(sheetName, "A1", &cells); err != nil {
+		return nil, fmt.Errorf("failed to write header - %w", err)
+	}
+
+	for i, row := range matrix.Rows {
+		cells := []any{
+			row.Object.Name,
+			row.Object.Rating,
+			row.Object.Advs,
+			row.Object.Disadvs,
+			row.Object.CreatedAt,
+		}
+
+		for j, value := range row.Values {
+			cells = append(cells, xlsxValue(matrix.CustomOptions[j], value))
+		}
+
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cell name - %w", err)
+		}
+
+		if err := f.SetSheetRow(sheetName, cell, &cells); err != nil {
+			return nil, fmt.Errorf("failed to write row - %w", err)
+		}
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write workbook - %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// xlsxValue keeps numbers and booleans typed, so they can be sorted and summed in a spreadsheet.
+// Money stays text to keep its currency, as well as values that can not be parsed.
+func xlsxValue(co domain.CustomOption, value string) any {
+	if value == "" || co.Type == domain.CustomOptionTypeMoney || co.Type == domain.CustomOptionTypeDate {
+		return value
+	}
+
+	typed, err := co.ParseValue(value)
+	if err != nil {
+		return value
+	}
+
+	return typed
+}
+
+func encodeJSON(matrix domain.ComparisonMatrix) ([]byte, error) {
+	customOptions := make([]documentOption, len(matrix.CustomOptions))
+	customOptionIds := make([]string, len(matrix.CustomOptions))
+	for i, co := range matrix.CustomOptions {
+		customOptions[i] = documentOption{
+			Id:         co.Id,
+			Name:       co.Name,
+			Type:       string(co.Type),
+			EnumValues: co.EnumValues,
+			Currency:   co.Currency,
+		}
+		customOptionIds[i] = co.Id
+	}
+
+	// options deleted since they were added to the comparison are left out, as in the matrix
+	optionWeights := make([]documentOptionWeight, 0, len(matrix.Comparison.OptionWeights))
+	for _, ow := range matrix.Comparison.OptionWeights {
+		if slices.Contains(customOptionIds, ow.CustomOptionId) {
+			optionWeights = append(optionWeights, documentOptionWeight{
+				CustomOptionId: ow.CustomOptionId,
+				Weight:         ow.Weight,
+				Direction:      string(ow.Direction),
+			})
+		}
+	}
+
+	tags := matrix.Comparison.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	objects := make([]documentObject, len(matrix.Rows))
+	for i, row := range matrix.Rows {
+		values := make([]documentOptionValue, 0, len(row.Object.ObjectCustomOptions))
+		for _, oco := range row.Object.ObjectCustomOptions {
+			if slices.Contains(customOptionIds, oco.CustomOptionId) {
+				values = append(values, documentOptionValue{
+					CustomOptionId: oco.CustomOptionId,
+					Value:          oco.Value,
+				})
+			}
+		}
+
+		objects[i] = documentObject{
+			Id:            row.Object.Id,
+			Name:          row.Object.Name,
+			Rating:        row.Object.Rating,
+			Advs:          row.Object.Advs,
+			Disadvs:       row.Object.Disadvs,
+			CreatedAt:     row.Object.CreatedAt,
+			CustomOptions: values,
+		}
+	}
+
+	content, err := json.MarshalIndent(document{
+		Version:    documentVersion,
+		ExportedAt: time.Now().UTC(),
+		Comparison: documentComparison{
+			Id:              matrix.Comparison.Id,
+			Name:            matrix.Comparison.Name,
+			CreatedAt:       matrix.Comparison.CreatedAt,
+			CustomOptionIds: customOptionIds,
+			OptionWeights:   optionWeights,
+			Tags:            tags,
+			FolderId:        matrix.Comparison.FolderId,
+		},
+		CustomOptions: customOptions,
+		Objects:       objects,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal document - %w", err)
+	}
+
+	return content, nil
+}
+
+func readCSVTable(r io.Reader) (domain.ImportTable, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return domain.ImportTable{}, err
+	}
+
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, utf8BOM)))
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return domain.ImportTable{}, err
+	}
+
+	return toImportTable(records)
+}
+
+// readXLSXTable reads the first sheet of the workbook. Cells are read as they are
+// displayed by spreadsheet editors, e.g. with the number format applied.
+func readXLSXTable(r io.Reader) (domain.ImportTable, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return domain.ImportTable{}, err
+	}
+	defer f.Close()
+
+	rows, err := f.GetRows(f.GetSheetName(0))
+	if err != nil {
+		return domain.ImportTable{}, err
+	}
+
+	return toImportTable(rows)
+}
+
+// readJSONTable reads objects of the document made by the JSON export. Columns of custom
+// options are named after them, as in other formats, and their definitions are kept
+// with the table.
+func readJSONTable(r io.Reader) (domain.ImportTable, error) {
+	var doc document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return domain.ImportTable{}, err
+	}
+
+	if doc.Version != documentVersion {
+		return domain.ImportTable{}, fmt.Errorf("unsupported document version %d", doc.Version)
+	}
+
+	header := []string{"name", "rating", "advs", "disadvs"}
+	columns := make(map[string]int, len(doc.CustomOptions))
+	customOptions := make([]domain.CustomOption, len(doc.CustomOptions))
+	for i, co := range doc.CustomOptions {
+		columns[co.Id] = len(header)
+		header = append(header, co.Name)
+
+		customOptions[i] = domain.CustomOption{
+			Name:       co.Name,
+			Type:       domain.CustomOptionType(co.Type),
+			EnumValues: co.EnumValues,
+			Currency:   co.Currency,
+		}
+	}
+
+	rows := make([][]string, len(doc.Objects))
+	for i, obj := range doc.Objects {
+		rows[i] = make([]string, len(header))
+		rows[i][0] = obj.Name
+		rows[i][1] = strconv.Itoa(obj.Rating)
+		rows[i][2] = obj.Advs
+		rows[i][3] = obj.Disadvs
+
+		for _, value := range obj.CustomOptions {
+			if idx, ok := columns[value.CustomOptionId]; ok {
+				rows[i][idx] = value.Value
+			}
+		}
+	}
+
+	return domain.ImportTable{Header: header, Rows: rows, CustomOptions: customOptions}, nil
+}
+
+func toImportTable(records [][]string) (domain.ImportTable, error) {
+	if len(records) == 0 {
+		return domain.ImportTable{}, fmt.Errorf("file is empty")
+	}
+
+	return domain.ImportTable{Header: records[0], Rows: records[1:]}, nil
+}
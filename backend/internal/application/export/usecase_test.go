@@ -0,0 +1,207 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+func testMatrix() domain.ComparisonMatrix {
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	return domain.ComparisonMatrix{
+		Comparison: domain.Comparison{
+			Id:              "85434230werhuhi123912304",
+			Name:            "Cars",
+			CustomOptionIds: []string{"432230ewrew3424rwe", "52342rwerew23123", "deleted3424rwe"},
+			OptionWeights: []domain.OptionWeight{
+				{CustomOptionId: "432230ewrew3424rwe", Weight: 2, Direction: domain.ScoreDirectionHigherIsBetter},
+				{CustomOptionId: "deleted3424rwe", Weight: 1, Direction: domain.ScoreDirectionLowerIsBetter},
+			},
+		},
+		CustomOptions: []domain.CustomOption{
+			{Id: "432230ewrew3424rwe", Name: "Power", Type: domain.CustomOptionTypeInteger},
+			{Id: "52342rwerew23123", Name: "Price", Type: domain.CustomOptionTypeMoney, Currency: "USD"},
+		},
+		Rows: []domain.ComparisonMatrixRow{
+			{
+				Object: domain.Object{
+					Id:        "231934sadas9123deqw",
+					Name:      "BMW X5",
+					Rating:    8,
+					Advs:      "Fast, comfortable",
+					CreatedAt: createdAt,
+					ObjectCustomOptions: []domain.ObjectCustomOption{
+						{CustomOptionId: "432230ewrew3424rwe", Value: "600"},
+						{CustomOptionId: "52342rwerew23123", Value: "80000 USD"},
+						{CustomOptionId: "deleted3424rwe", Value: "old"},
+					},
+				},
+				Values: []string{"600", "80000 USD"},
+			},
+			{
+				Object: domain.Object{
+					Id:        "9123deqw231934sadas",
+					Name:      "Audi Q7",
+					Rating:    7,
+					CreatedAt: createdAt,
+					ObjectCustomOptions: []domain.ObjectCustomOption{
+						{CustomOptionId: "432230ewrew3424rwe", Value: "400"},
+					},
+				},
+				Values: []string{"400", ""},
+			},
+		},
+	}
+}
+
+func TestExportMatrix(t *testing.T) {
+	t.Run("CSV", func(t *testing.T) {
+		uc := NewExportUsecase()
+
+		file, err := uc.ExportMatrix(testMatrix(), domain.ExportFormatCSV)
+		assert.NoError(t, err)
+		assert.Equal(t, "Cars", file.Name)
+		assert.Equal(t, domain.ExportFormatCSV, file.Format)
+		assert.Equal(t, "text/csv; charset=utf-8", file.ContentType)
+
+		records, err := csv.NewReader(bytes.NewReader(file.Content)).ReadAll()
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"name", "rating", "advs", "disadvs", "created_at", "Power", "Price"},
+			{"BMW X5", "8", "Fast, comfortable", "", "2024-03-01T12:00:00Z", "600", "80000 USD"},
+			{"Audi Q7", "7", "", "", "2024-03-01T12:00:00Z", "400", ""},
+		}, records)
+	})
+
+	t.Run("XLSX", func(t *testing.T) {
+		uc := NewExportUsecase()
+
+		file, err := uc.ExportMatrix(testMatrix(), domain.ExportFormatXLSX)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ExportFormatXLSX, file.Format)
+
+		f, err := excelize.OpenReader(bytes.NewReader(file.Content))
+		assert.NoError(t, err)
+		defer f.Close()
+
+		assert.Equal(t, sheetName, f.GetSheetName(0))
+
+		rows, err := f.GetRows(sheetName)
+		assert.NoError(t, err)
+		assert.Len(t, rows, 3)
+		assert.Equal(t, []string{"name", "rating", "advs", "disadvs", "created_at", "Power", "Price"}, rows[0])
+
+		// numbers are stored typed, money is kept as text with its currency
+		powerType, err := f.GetCellType(sheetName, "F2")
+		assert.NoError(t, err)
+		assert.NotEqual(t, excelize.CellTypeSharedString, powerType)
+
+		price, err := f.GetCellValue(sheetName, "G2")
+		assert.NoError(t, err)
+		assert.Equal(t, "80000 USD", price)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		uc := NewExportUsecase()
+
+		file, err := uc.ExportMatrix(testMatrix(), domain.ExportFormatJSON)
+		assert.NoError(t, err)
+		assert.Equal(t, "application/json; charset=utf-8", file.ContentType)
+
+		var doc document
+		assert.NoError(t, json.Unmarshal(file.Content, &doc))
+
+		assert.Equal(t, documentVersion, doc.Version)
+		assert.Equal(t, []string{"432230ewrew3424rwe", "52342rwerew23123"}, doc.Comparison.CustomOptionIds)
+		assert.Equal(t, []documentOptionWeight{
+			{CustomOptionId: "432230ewrew3424rwe", Weight: 2, Direction: "higher_is_better"},
+		}, doc.Comparison.OptionWeights)
+		assert.Equal(t, []string{}, doc.Comparison.Tags)
+		assert.Equal(t, []documentOption{
+			{Id: "432230ewrew3424rwe", Name: "Power", Type: "integer"},
+			{Id: "52342rwerew23123", Name: "Price", Type: "money", Currency: "USD"},
+		}, doc.CustomOptions)
+		assert.Len(t, doc.Objects, 2)
+		assert.Equal(t, []documentOptionValue{
+			{CustomOptionId: "432230ewrew3424rwe", Value: "600"},
+			{CustomOptionId: "52342rwerew23123", Value: "80000 USD"},
+		}, doc.Objects[0].CustomOptions)
+	})
+
+	t.Run("Unsupported format", func(t *testing.T) {
+		uc := NewExportUsecase()
+
+		_, err := uc.ExportMatrix(testMatrix(), domain.ExportFormat("pdf"))
+		assert.True(t, errors.Is(err, domain.ErrInvalidValue))
+	})
+}
+
+func TestReadTable(t *testing.T) {
+	t.Run("CSV with BOM", func(t *testing.T) {
+		uc := NewExportUsecase()
+
+		content := "\xEF\xBB\xBFname,rating,Power\nBMW X5,8,600\nAudi Q7,7\n"
+
+		table, err := uc.ReadTable(domain.ExportFormatCSV, strings.NewReader(content))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"name", "rating", "Power"}, table.Header)
+		assert.Equal(t, [][]string{{"BMW X5", "8", "600"}, {"Audi Q7", "7"}}, table.Rows)
+		assert.Empty(t, table.CustomOptions)
+	})
+
+	t.Run("XLSX round trip", func(t *testing.T) {
+		uc := NewExportUsecase()
+
+		file, err := uc.ExportMatrix(testMatrix(), domain.ExportFormatXLSX)
+		assert.NoError(t, err)
+
+		table, err := uc.ReadTable(domain.ExportFormatXLSX, bytes.NewReader(file.Content))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"name", "rating", "advs", "disadvs", "created_at", "Power", "Price"}, table.Header)
+		assert.Len(t, table.Rows, 2)
+		assert.Equal(t, "600", table.Rows[0][5])
+		assert.Equal(t, "80000 USD", table.Rows[0][6])
+	})
+
+	t.Run("JSON round trip keeps option types", func(t *testing.T) {
+		uc := NewExportUsecase()
+
+		file, err := uc.ExportMatrix(testMatrix(), domain.ExportFormatJSON)
+		assert.NoError(t, err)
+
+		table, err := uc.ReadTable(domain.ExportFormatJSON, bytes.NewReader(file.Content))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"name", "rating", "advs", "disadvs", "Power", "Price"}, table.Header)
+		assert.Equal(t, [][]string{
+			{"BMW X5", "8", "Fast, comfortable", "", "600", "80000 USD"},
+			{"Audi Q7", "7", "", "", "400", ""},
+		}, table.Rows)
+		assert.Equal(t, []domain.CustomOption{
+			{Name: "Power", Type: domain.CustomOptionTypeInteger},
+			{Name: "Price", Type: domain.CustomOptionTypeMoney, Currency: "USD"},
+		}, table.CustomOptions)
+	})
+
+	t.Run("Unsupported JSON version", func(t *testing.T) {
+		uc := NewExportUsecase()
+
+		_, err := uc.ReadTable(domain.ExportFormatJSON, strings.NewReader(`{"version": 2}`))
+		assert.True(t, errors.Is(err, domain.ErrInvalidValue))
+	})
+
+	t.Run("Empty file", func(t *testing.T) {
+		uc := NewExportUsecase()
+
+		_, err := uc.ReadTable(domain.ExportFormatCSV, strings.NewReader(""))
+		assert.True(t, errors.Is(err, domain.ErrInvalidValue))
+	})
+}
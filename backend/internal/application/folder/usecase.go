@@ -0,0 +1,181 @@
+package folder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+)
+
+type FolderUsecase struct {
+	repo           FolderRepository
+	comparisonRepo ComparisonRepository
+	transactor     Transactor
+	generator      IdGenerator
+}
+
+type FolderRepository interface {
+	GetFolders(ctx context.Context) ([]domain.Folder, error)
+	GetFolderById(ctx context.Context, id string) (domain.Folder, error)
+	GetFoldersByParentId(ctx context.Context, parentId string) ([]domain.Folder, error)
+	GetFolderDescendantIds(ctx context.Context, id string) ([]string, error)
+	CreateFolder(ctx context.Context, folder domain.Folder) error
+	UpdateFolder(ctx context.Context, folder domain.Folder) error
+	DeleteFolder(ctx context.Context, id string) error
+}
+
+type ComparisonRepository interface {
+	GetComparisonsByFolderId(ctx context.Context, folderId string) ([]domain.Comparison, error)
+}
+
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type IdGenerator interface {
+	GenerateId() string
+}
+
+func NewFolderUsecase(
+	repo FolderRepository,
+	comparisonRepo ComparisonRepository,
+	transactor Transactor,
+	generator IdGenerator,
+) *FolderUsecase {
+	return &FolderUsecase{
+		repo:           repo,
+		comparisonRepo: comparisonRepo,
+		transactor:     transactor,
+		generator:      generator,
+	}
+}
+
+func (uc *FolderUsecase) GetFolders(ctx context.Context) ([]domain.Folder, error) {
+	folders, err := uc.repo.GetFolders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folders - %w", err)
+	}
+
+	return folders, nil
+}
+
+func (uc *FolderUsecase) GetFolderById(ctx context.Context, id string) (domain.Folder, error) {
+	folder, err := uc.repo.GetFolderById(ctx, id)
+	if err != nil {
+		return domain.Folder{}, fmt.Errorf("failed to get folder - %w", err)
+	}
+
+	return folder, nil
+}
+
+func (uc *FolderUsecase) CreateFolder(ctx context.Context, folder domain.Folder) (string, error) {
+	if err := uc.checkParent(ctx, folder.ParentId); err != nil {
+		return "", err
+	}
+
+	folder.Id = uc.generator.GenerateId()
+	folder.CreatedAt = time.Now()
+
+	if err := uc.repo.CreateFolder(ctx, folder); err != nil {
+		return "", fmt.Errorf("failed to create folder - %w", err)
+	}
+
+	return folder.Id, nil
+}
+
+// UpdateFolder renames the folder or moves it with its subfolders and comparisons
+// into another folder. The new parent is checked within one transaction with the update.
+func (uc *FolderUsecase) UpdateFolder(ctx context.Context, id string, folder domain.Folder) error {
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		existingFolder, err := uc.repo.GetFolderById(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get existing folder - %w", err)
+		}
+
+		folder.Id = existingFolder.Id
+		folder.CreatedAt = existingFolder.CreatedAt
+
+		if folder.ParentId != existingFolder.ParentId {
+			descendantIds, err := uc.repo.GetFolderDescendantIds(ctx, folder.Id)
+			if err != nil {
+				return fmt.Errorf("failed to get subfolders - %w", err)
+			}
+
+			if err := folder.ValidateParent(descendantIds); err != nil {
+				return fmt.Errorf("invalid folder - %w", err)
+			}
+
+			if err := uc.checkParent(ctx, folder.ParentId); err != nil {
+				return err
+			}
+		}
+
+		if err := uc.repo.UpdateFolder(ctx, folder); err != nil {
+			return fmt.Errorf("failed to update folder - %w", err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteFolder deletes the empty folder. Folder that still has subfolders or
+// comparisons can not be deleted, in that case *domain.DependentsError listing
+// them is returned.
+func (uc *FolderUsecase) DeleteFolder(ctx context.Context, id string) error {
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := uc.repo.GetFolderById(ctx, id); err != nil {
+			return fmt.Errorf("failed to get folder - %w", err)
+		}
+
+		subfolders, err := uc.repo.GetFoldersByParentId(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get subfolders - %w", err)
+		}
+
+		comparisons, err := uc.comparisonRepo.GetComparisonsByFolderId(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get comparisons - %w", err)
+		}
+
+		if len(subfolders) != 0 || len(comparisons) != 0 {
+			dependents := make([]domain.Dependent, 0, len(subfolders)+len(comparisons))
+			for _, f := range subfolders {
+				dependents = append(dependents, domain.Dependent{Type: "folder", Id: f.Id, Name: f.Name})
+			}
+
+			for _, c := range comparisons {
+				dependents = append(dependents, domain.Dependent{Type: "comparison", Id: c.Id, Name: c.Name})
+			}
+
+			return fmt.Errorf("failed to delete folder - %w", &domain.DependentsError{
+				Entity:     "folder",
+				Dependents: dependents,
+			})
+		}
+
+		if err := uc.repo.DeleteFolder(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete folder - %w", err)
+		}
+
+		return nil
+	})
+}
+
+// checkParent makes sure the parent folder exists, empty parent stands for the root.
+func (uc *FolderUsecase) checkParent(ctx context.Context, parentId string) error {
+	if parentId == "" {
+		return nil
+	}
+
+	if _, err := uc.repo.GetFolderById(ctx, parentId); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("parent folder '%s' not found - %w", parentId, domain.ErrInvalidValue)
+		}
+
+		return fmt.Errorf("failed to get parent folder - %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package folder
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/Unlites/comparison_center/backend/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateFolder(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := mocks.NewFolderRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewFolderUsecase(repo, comparisonRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		repo.On("GetFolderById", ctx, "3f0a2b3c4d5e6f70").Return(domain.Folder{Id: "3f0a2b3c4d5e6f70", Name: "Travel"}, nil)
+		generator.On("GenerateId").Return("9a8b7c6d5e4f3a2b")
+		repo.On("CreateFolder", ctx, mock.MatchedBy(func(folder domain.Folder) bool {
+			return folder.Id == "9a8b7c6d5e4f3a2b" &&
+				folder.Name == "Hotels" &&
+				folder.ParentId == "3f0a2b3c4d5e6f70" &&
+				!folder.CreatedAt.IsZero()
+		})).Return(nil)
+
+		id, err := uc.CreateFolder(ctx, domain.Folder{Name: "Hotels", ParentId: "3f0a2b3c4d5e6f70"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "9a8b7c6d5e4f3a2b", id)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Parent not found", func(t *testing.T) {
+		repo := mocks.NewFolderRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewFolderUsecase(repo, comparisonRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		repo.On("GetFolderById", ctx, "3f0a2b3c4d5e6f70").Return(nil, fmt.Errorf("folder %w", domain.ErrNotFound))
+
+		_, err := uc.CreateFolder(ctx, domain.Folder{Name: "Hotels", ParentId: "3f0a2b3c4d5e6f70"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		repo.AssertNotCalled(t, "CreateFolder")
+	})
+}
+
+func TestUpdateFolder(t *testing.T) {
+	t.Run("Move", func(t *testing.T) {
+		repo := mocks.NewFolderRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewFolderUsecase(repo, comparisonRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		repo.On("GetFolderById", ctx, "9a8b7c6d5e4f3a2b").Return(domain.Folder{Id: "9a8b7c6d5e4f3a2b", Name: "Hotels"}, nil)
+		repo.On("GetFolderDescendantIds", ctx, "9a8b7c6d5e4f3a2b").Return([]string{"1b2c3d4e5f6a7b8c"}, nil)
+		repo.On("GetFolderById", ctx, "3f0a2b3c4d5e6f70").Return(domain.Folder{Id: "3f0a2b3c4d5e6f70", Name: "Travel"}, nil)
+		repo.On("UpdateFolder", ctx, domain.Folder{
+			Id:       "9a8b7c6d5e4f3a2b",
+			Name:     "Hotels",
+			ParentId: "3f0a2b3c4d5e6f70",
+		}).Return(nil)
+
+		err := uc.UpdateFolder(ctx, "9a8b7c6d5e4f3a2b", domain.Folder{Name: "Hotels", ParentId: "3f0a2b3c4d5e6f70"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, transactor.Committed)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Move into subfolder", func(t *testing.T) {
+		repo := mocks.NewFolderRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewFolderUsecase(repo, comparisonRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		repo.On("GetFolderById", ctx, "9a8b7c6d5e4f3a2b").Return(domain.Folder{Id: "9a8b7c6d5e4f3a2b", Name: "Hotels"}, nil)
+		repo.On("GetFolderDescendantIds", ctx, "9a8b7c6d5e4f3a2b").Return([]string{"1b2c3d4e5f6a7b8c"}, nil)
+
+		err := uc.UpdateFolder(ctx, "9a8b7c6d5e4f3a2b", domain.Folder{Name: "Hotels", ParentId: "1b2c3d4e5f6a7b8c"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		assert.Equal(t, 1, transactor.RolledBack)
+		repo.AssertNotCalled(t, "UpdateFolder")
+	})
+
+	t.Run("Rename", func(t *testing.T) {
+		repo := mocks.NewFolderRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewFolderUsecase(repo, comparisonRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		repo.On("GetFolderById", ctx, "9a8b7c6d5e4f3a2b").
+			Return(domain.Folder{Id: "9a8b7c6d5e4f3a2b", Name: "Hotels", ParentId: "3f0a2b3c4d5e6f70"}, nil)
+		repo.On("UpdateFolder", ctx, domain.Folder{
+			Id:       "9a8b7c6d5e4f3a2b",
+			Name:     "Stays",
+			ParentId: "3f0a2b3c4d5e6f70",
+		}).Return(nil)
+
+		err := uc.UpdateFolder(ctx, "9a8b7c6d5e4f3a2b", domain.Folder{Name: "Stays", ParentId: "3f0a2b3c4d5e6f70"})
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "GetFolderDescendantIds")
+	})
+}
+
+func TestDeleteFolder(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := mocks.NewFolderRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewFolderUsecase(repo, comparisonRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		repo.On("GetFolderById", ctx, "9a8b7c6d5e4f3a2b").Return(domain.Folder{Id: "9a8b7c6d5e4f3a2b", Name: "Hotels"}, nil)
+		repo.On("GetFoldersByParentId", ctx, "9a8b7c6d5e4f3a2b").Return([]domain.Folder{}, nil)
+		comparisonRepo.On("GetComparisonsByFolderId", ctx, "9a8b7c6d5e4f3a2b").Return([]domain.Comparison{}, nil)
+		repo.On("DeleteFolder", ctx, "9a8b7c6d5e4f3a2b").Return(nil)
+
+		err := uc.DeleteFolder(ctx, "9a8b7c6d5e4f3a2b")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, transactor.Committed)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Not empty", func(t *testing.T) {
+		repo := mocks.NewFolderRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewFolderUsecase(repo, comparisonRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		repo.On("GetFolderById", ctx, "9a8b7c6d5e4f3a2b").Return(domain.Folder{Id: "9a8b7c6d5e4f3a2b", Name: "Travel"}, nil)
+		repo.On("GetFoldersByParentId", ctx, "9a8b7c6d5e4f3a2b").
+			Return([]domain.Folder{{Id: "1b2c3d4e5f6a7b8c", Name: "Hotels"}}, nil)
+		comparisonRepo.On("GetComparisonsByFolderId", ctx, "9a8b7c6d5e4f3a2b").
+			Return([]domain.Comparison{{Id: "85434230werhuhi123912304", Name: "Flights"}}, nil)
+
+		err := uc.DeleteFolder(ctx, "9a8b7c6d5e4f3a2b")
+
+		var dependentsErr *domain.DependentsError
+		assert.ErrorAs(t, err, &dependentsErr)
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.Equal(t, []domain.Dependent{
+			{Type: "folder", Id: "1b2c3d4e5f6a7b8c", Name: "Hotels"},
+			{Type: "comparison", Id: "85434230werhuhi123912304", Name: "Flights"},
+		}, dependentsErr.Dependents)
+		assert.Equal(t, 1, transactor.RolledBack)
+		repo.AssertNotCalled(t, "DeleteFolder")
+	})
+}
@@ -0,0 +1,94 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+)
+
+type MatrixUsecase struct {
+	comparisonRepo ComparisonRepository
+	objRepo        ObjectRepository
+	custOptRepo    CustomOptionRepository
+}
+
+type ComparisonRepository interface {
+	GetComparisonById(ctx context.Context, id string) (domain.Comparison, error)
+}
+
+type ObjectRepository interface {
+	GetObjectsWithOptionsByComparisonId(ctx context.Context, comparisonId string) ([]domain.Object, error)
+}
+
+type CustomOptionRepository interface {
+	GetCustomOptionsByIds(ctx context.Context, ids []string) ([]domain.CustomOption, error)
+}
+
+func NewMatrixUsecase(
+	comparisonRepo ComparisonRepository,
+	objRepo ObjectRepository,
+	custOptRepo CustomOptionRepository,
+) *MatrixUsecase {
+	return &MatrixUsecase{
+		comparisonRepo: comparisonRepo,
+		objRepo:        objRepo,
+		custOptRepo:    custOptRepo,
+	}
+}
+
+func (uc *MatrixUsecase) GetComparisonMatrix(
+	ctx context.Context,
+	comparisonId string,
+) (domain.ComparisonMatrix, error) {
+	comparison, err := uc.comparisonRepo.GetComparisonById(ctx, comparisonId)
+	if err != nil {
+		return domain.ComparisonMatrix{}, fmt.Errorf("failed to get comparison - %w", err)
+	}
+
+	objects, err := uc.objRepo.GetObjectsWithOptionsByComparisonId(ctx, comparison.Id)
+	if err != nil {
+		return domain.ComparisonMatrix{}, fmt.Errorf("failed to get objects - %w", err)
+	}
+
+	foundCustomOptions := make([]domain.CustomOption, 0)
+	if len(comparison.CustomOptionIds) != 0 {
+		foundCustomOptions, err = uc.custOptRepo.GetCustomOptionsByIds(ctx, comparison.CustomOptionIds)
+		if err != nil {
+			return domain.ComparisonMatrix{}, fmt.Errorf("failed to get custom options - %w", err)
+		}
+	}
+
+	// columns follow the order of the comparison, options deleted since then are skipped
+	customOptions := make([]domain.CustomOption, 0, len(comparison.CustomOptionIds))
+	for _, id := range comparison.CustomOptionIds {
+		idx := slices.IndexFunc(foundCustomOptions, func(co domain.CustomOption) bool {
+			return co.Id == id
+		})
+		if idx != -1 {
+			customOptions = append(customOptions, foundCustomOptions[idx])
+		}
+	}
+
+	rows := make([]domain.ComparisonMatrixRow, len(objects))
+	for i, obj := range objects {
+		values := make([]string, len(customOptions))
+		for j, co := range customOptions {
+			idx := slices.IndexFunc(obj.ObjectCustomOptions, func(oco domain.ObjectCustomOption) bool {
+				return oco.CustomOptionId == co.Id
+			})
+			if idx != -1 {
+				values[j] = obj.ObjectCustomOptions[idx].Value
+			}
+		}
+
+		rows[i] = domain.ComparisonMatrixRow{Object: obj, Values: values}
+	}
+
+	return domain.ComparisonMatrix{
+		Comparison:    comparison,
+		CustomOptions: customOptions,
+		Rows:          rows,
+	}, nil
+}
@@ -0,0 +1,89 @@
+package matrix
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/Unlites/comparison_center/backend/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetComparisonMatrix(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		uc := NewMatrixUsecase(comparisonRepo, objRepo, custOptRepo)
+
+		ctx := context.Background()
+		id := "85434230werhuhi123912304"
+
+		returnedComparison := domain.Comparison{
+			Id:              id,
+			Name:            "Cars",
+			CustomOptionIds: []string{"432230ewrew3424rwe", "52342rwerew23123", "deleted3424rwe"},
+		}
+
+		returnedCustomOptions := []domain.CustomOption{
+			{Id: "52342rwerew23123", Name: "Release year", Type: domain.CustomOptionTypeInteger},
+			{Id: "432230ewrew3424rwe", Name: "Power", Type: domain.CustomOptionTypeInteger},
+		}
+
+		returnedObjects := []domain.Object{
+			{
+				Id:   "231934sadas9123deqw",
+				Name: "BMW X5",
+				ObjectCustomOptions: []domain.ObjectCustomOption{
+					{CustomOptionId: "52342rwerew23123", Value: "2021"},
+					{CustomOptionId: "432230ewrew3424rwe", Value: "600"},
+				},
+			},
+			{
+				Id:   "9123deqw231934sadas",
+				Name: "Audi Q7",
+				ObjectCustomOptions: []domain.ObjectCustomOption{
+					{CustomOptionId: "432230ewrew3424rwe", Value: "400"},
+				},
+			},
+		}
+
+		comparisonRepo.On("GetComparisonById", ctx, id).Return(returnedComparison, nil)
+		objRepo.On("GetObjectsWithOptionsByComparisonId", ctx, id).Return(returnedObjects, nil)
+		custOptRepo.On("GetCustomOptionsByIds", ctx, returnedComparison.CustomOptionIds).
+			Return(returnedCustomOptions, nil)
+
+		matrix, err := uc.GetComparisonMatrix(ctx, id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, returnedComparison, matrix.Comparison)
+		assert.Equal(t, []domain.CustomOption{returnedCustomOptions[1], returnedCustomOptions[0]}, matrix.CustomOptions)
+		assert.Equal(t, []domain.ComparisonMatrixRow{
+			{Object: returnedObjects[0], Values: []string{"600", "2021"}},
+			{Object: returnedObjects[1], Values: []string{"400", ""}},
+		}, matrix.Rows)
+
+		comparisonRepo.AssertExpectations(t)
+		objRepo.AssertExpectations(t)
+		custOptRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		uc := NewMatrixUsecase(comparisonRepo, objRepo, custOptRepo)
+
+		ctx := context.Background()
+		id := "85434230werhuhi123912304"
+
+		comparisonRepo.On("GetComparisonById", ctx, id).Return(nil, domain.ErrNotFound)
+
+		matrix, err := uc.GetComparisonMatrix(ctx, id)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Empty(t, matrix)
+		objRepo.AssertNotCalled(t, "GetObjectsWithOptionsByComparisonId")
+		custOptRepo.AssertNotCalled(t, "GetCustomOptionsByIds")
+	})
+}
@@ -2,6 +2,7 @@ package object
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
 	"time"
@@ -12,23 +13,46 @@ import (
 type ObjectUsecase struct {
 	objRepo        ObjectRepository
 	custOptObjRepo ObjectCustomOptionRepository
+	custOptRepo    CustomOptionRepository
+	comparisonRepo ComparisonRepository
+	auditRepo      AuditRepository
+	transactor     Transactor
 	generator      IdGenerator
 }
 
 type ObjectRepository interface {
-	GetObjects(ctx context.Context, filter domain.ObjectFilter) ([]domain.Object, error)
+	GetObjects(ctx context.Context, filter domain.ObjectFilter) ([]domain.Object, domain.PageInfo, error)
 	GetObjectById(ctx context.Context, id string) (domain.Object, error)
 	UpdateObject(ctx context.Context, object domain.Object) error
 	CreateObject(ctx context.Context, object domain.Object) error
-	DeleteObject(ctx context.Context, id string) error
+	DeleteObject(ctx context.Context, id string, deletedAt time.Time) error
+	GetDeletedObjectById(ctx context.Context, id string) (domain.Object, error)
+	RestoreObject(ctx context.Context, id string) error
 }
 
 type ObjectCustomOptionRepository interface {
 	GetObjectCustomOptionsByObjectId(ctx context.Context, objectId string) ([]domain.ObjectCustomOption, error)
+	GetObjectCustomOptionsByObjectIds(ctx context.Context, objectIds []string) ([]domain.ObjectCustomOption, error)
 	AddObjectCustomOption(ctx context.Context, objectCustomOption domain.ObjectCustomOption) error
 	UpdateObjectCustomOption(ctx context.Context, objectCustomOption domain.ObjectCustomOption) error
 }
 
+type CustomOptionRepository interface {
+	GetCustomOptionsByIds(ctx context.Context, ids []string) ([]domain.CustomOption, error)
+}
+
+type ComparisonRepository interface {
+	GetComparisonById(ctx context.Context, id string) (domain.Comparison, error)
+}
+
+type AuditRepository interface {
+	AddAuditEvent(ctx context.Context, event domain.AuditEvent) error
+}
+
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type IdGenerator interface {
 	GenerateId() string
 }
@@ -36,11 +60,19 @@ type IdGenerator interface {
 func NewObjectUsecase(
 	objRepo ObjectRepository,
 	custOptObjRepo ObjectCustomOptionRepository,
+	custOptRepo CustomOptionRepository,
+	comparisonRepo ComparisonRepository,
+	auditRepo AuditRepository,
+	transactor Transactor,
 	generator IdGenerator,
 ) *ObjectUsecase {
 	return &ObjectUsecase{
 		objRepo:        objRepo,
 		custOptObjRepo: custOptObjRepo,
+		custOptRepo:    custOptRepo,
+		comparisonRepo: comparisonRepo,
+		auditRepo:      auditRepo,
+		transactor:     transactor,
 		generator:      generator,
 	}
 }
@@ -48,22 +80,62 @@ func NewObjectUsecase(
 func (uc *ObjectUsecase) GetObjects(
 	ctx context.Context,
 	filter domain.ObjectFilter,
-) ([]domain.Object, error) {
-	objects, err := uc.objRepo.GetObjects(ctx, filter)
+) ([]domain.Object, domain.PageInfo, error) {
+	predicates, err := uc.parseOptionPredicates(ctx, filter.OptionPredicates)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get objects - %w", err)
+		return nil, domain.PageInfo{}, fmt.Errorf("failed to parse custom option predicates - %w", err)
 	}
 
-	for i, obj := range objects {
-		options, err := uc.custOptObjRepo.GetObjectCustomOptionsByObjectId(ctx, obj.Id)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get custom options - %w", err)
+	filter.OptionPredicates = predicates
+
+	objects, pageInfo, err := uc.objRepo.GetObjects(ctx, filter)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("failed to get objects - %w", err)
+	}
+
+	if err := uc.attachCustomOptions(ctx, objects); err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+
+	if filter.Query != "" {
+		terms := domain.SearchTerms(filter.Query)
+		for i := range objects {
+			objects[i].Highlight, _ = objects[i].HighlightTerms(terms)
 		}
+	}
+
+	return objects, pageInfo, nil
+}
+
+// attachCustomOptions loads custom options of all objects with a single query.
+func (uc *ObjectUsecase) attachCustomOptions(ctx context.Context, objects []domain.Object) error {
+	if len(objects) == 0 {
+		return nil
+	}
+
+	objectIds := make([]string, len(objects))
+	for i, obj := range objects {
+		objectIds[i] = obj.Id
+	}
+
+	options, err := uc.custOptObjRepo.GetObjectCustomOptionsByObjectIds(ctx, objectIds)
+	if err != nil {
+		return fmt.Errorf("failed to get custom options - %w", err)
+	}
+
+	optionsByObjectId := make(map[string][]domain.ObjectCustomOption, len(objects))
+	for _, option := range options {
+		optionsByObjectId[option.ObjectId] = append(optionsByObjectId[option.ObjectId], option)
+	}
 
-		objects[i].ObjectCustomOptions = options
+	for i, obj := range objects {
+		objects[i].ObjectCustomOptions = optionsByObjectId[obj.Id]
+		if objects[i].ObjectCustomOptions == nil {
+			objects[i].ObjectCustomOptions = make([]domain.ObjectCustomOption, 0)
+		}
 	}
 
-	return objects, nil
+	return nil
 }
 
 func (uc *ObjectUsecase) GetObjectById(
@@ -98,78 +170,242 @@ func (uc *ObjectUsecase) UpdateObject(
 	inputObject.Id = existingObject.Id
 	inputObject.CreatedAt = existingObject.CreatedAt
 	inputObject.ComparisonId = existingObject.ComparisonId
-	inputObject.PhotoPath = existingObject.PhotoPath
 
-	if err := uc.objRepo.UpdateObject(ctx, inputObject); err != nil {
-		return fmt.Errorf("failed to update object - %w", err)
+	if err := uc.parseCustomOptionValues(ctx, inputObject.ObjectCustomOptions); err != nil {
+		return fmt.Errorf("failed to parse custom options - %w", err)
 	}
 
-	existingObjectOptions, err := uc.custOptObjRepo.GetObjectCustomOptionsByObjectId(ctx, existingObject.Id)
-	if err != nil {
-		return fmt.Errorf("failed to get existing custom options - %w", err)
-	}
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		existingObjectOptions, err := uc.custOptObjRepo.GetObjectCustomOptionsByObjectId(ctx, existingObject.Id)
+		if err != nil {
+			return fmt.Errorf("failed to get existing custom options - %w", err)
+		}
 
-	for i := range inputObject.ObjectCustomOptions {
-		inputObject.ObjectCustomOptions[i].ObjectId = existingObject.Id
+		existingObject.ObjectCustomOptions = existingObjectOptions
 
-		if slices.ContainsFunc(existingObjectOptions, func(option domain.ObjectCustomOption) bool {
-			return option.CustomOptionId == inputObject.ObjectCustomOptions[i].CustomOptionId
-		}) {
-			err := uc.custOptObjRepo.UpdateObjectCustomOption(ctx, inputObject.ObjectCustomOptions[i])
-			if err != nil {
-				return fmt.Errorf("failed to update custom option - %w", err)
-			}
-		} else {
-			err := uc.custOptObjRepo.AddObjectCustomOption(ctx, inputObject.ObjectCustomOptions[i])
-			if err != nil {
-				return fmt.Errorf("failed to add custom option - %w", err)
+		// values of custom options missing in the input are kept as they are
+		updatedObject := inputObject
+		updatedObject.ObjectCustomOptions = slices.Clone(existingObjectOptions)
+
+		for i := range inputObject.ObjectCustomOptions {
+			inputObject.ObjectCustomOptions[i].ObjectId = existingObject.Id
+			option := inputObject.ObjectCustomOptions[i]
+
+			idx := slices.IndexFunc(updatedObject.ObjectCustomOptions, func(o domain.ObjectCustomOption) bool {
+				return o.CustomOptionId == option.CustomOptionId
+			})
+			if idx != -1 {
+				if err := uc.custOptObjRepo.UpdateObjectCustomOption(ctx, option); err != nil {
+					return fmt.Errorf("failed to update custom option - %w", err)
+				}
+
+				updatedObject.ObjectCustomOptions[idx] = option
+			} else {
+				if err := uc.custOptObjRepo.AddObjectCustomOption(ctx, option); err != nil {
+					return fmt.Errorf("failed to add custom option - %w", err)
+				}
+
+				updatedObject.ObjectCustomOptions = append(updatedObject.ObjectCustomOptions, option)
 			}
 		}
-	}
 
-	return nil
+		// the object is written with all of its custom options, so their values kept
+		// in the object for the full-text search are synced with a single write
+		if err := uc.objRepo.UpdateObject(ctx, updatedObject); err != nil {
+			return fmt.Errorf("failed to update object - %w", err)
+		}
+
+		return uc.recordChange(ctx, existingObject.Id, existingObject.AuditFields(), updatedObject.AuditFields())
+	})
 }
 
 func (uc *ObjectUsecase) CreateObject(
 	ctx context.Context,
 	object domain.Object,
 ) (string, error) {
+	if err := uc.parseCustomOptionValues(ctx, object.ObjectCustomOptions); err != nil {
+		return "", fmt.Errorf("failed to parse custom options - %w", err)
+	}
+
 	object.Id = uc.generator.GenerateId()
 	object.CreatedAt = time.Now()
-	if err := uc.objRepo.CreateObject(ctx, object); err != nil {
-		return "", fmt.Errorf("failed to create object - %w", err)
-	}
 
-	for i := range object.ObjectCustomOptions {
-		object.ObjectCustomOptions[i].ObjectId = object.Id
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.objRepo.CreateObject(ctx, object); err != nil {
+			return fmt.Errorf("failed to create object - %w", err)
+		}
 
-		err := uc.custOptObjRepo.AddObjectCustomOption(ctx, object.ObjectCustomOptions[i])
-		if err != nil {
-			return "", fmt.Errorf("failed to add custom option - %w", err)
+		for i := range object.ObjectCustomOptions {
+			object.ObjectCustomOptions[i].ObjectId = object.Id
+
+			err := uc.custOptObjRepo.AddObjectCustomOption(ctx, object.ObjectCustomOptions[i])
+			if err != nil {
+				return fmt.Errorf("failed to add custom option - %w", err)
+			}
 		}
+
+		return uc.recordChange(ctx, object.Id, nil, object.AuditFields())
+	})
+	if err != nil {
+		return "", err
 	}
 
 	return object.Id, nil
 }
 
+// DeleteObject moves the object to the trash, its custom option values and photos
+// are kept until the object is removed for good once the retention period is over.
 func (uc *ObjectUsecase) DeleteObject(ctx context.Context, id string) error {
-	if err := uc.objRepo.DeleteObject(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete object - %w", err)
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		object, err := uc.objRepo.GetObjectById(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get object - %w", err)
+		}
+
+		object.ObjectCustomOptions, err = uc.custOptObjRepo.GetObjectCustomOptionsByObjectId(ctx, object.Id)
+		if err != nil {
+			return fmt.Errorf("failed to get custom options - %w", err)
+		}
+
+		if err := uc.objRepo.DeleteObject(ctx, object.Id, time.Now()); err != nil {
+			return fmt.Errorf("failed to delete object - %w", err)
+		}
+
+		return uc.recordChange(ctx, object.Id, object.AuditFields(), nil)
+	})
+}
+
+// RestoreObject takes the object out of the trash. Objects of the comparison
+// that is in the trash itself are restored only along with it.
+func (uc *ObjectUsecase) RestoreObject(ctx context.Context, id string) error {
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		object, err := uc.objRepo.GetDeletedObjectById(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get deleted object - %w", err)
+		}
+
+		if _, err := uc.comparisonRepo.GetComparisonById(ctx, object.ComparisonId); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return fmt.Errorf("comparison of the object is in the trash - %w", domain.ErrConflict)
+			}
+
+			return fmt.Errorf("failed to get comparison - %w", err)
+		}
+
+		object.ObjectCustomOptions, err = uc.custOptObjRepo.GetObjectCustomOptionsByObjectId(ctx, object.Id)
+		if err != nil {
+			return fmt.Errorf("failed to get custom options - %w", err)
+		}
+
+		if err := uc.objRepo.RestoreObject(ctx, object.Id); err != nil {
+			return fmt.Errorf("failed to restore object - %w", err)
+		}
+
+		return uc.addAuditEvent(ctx, domain.NewRestoreAuditEvent(domain.AuditEntityObject, object.Id, object.AuditFields()))
+	})
+}
+
+// recordChange adds the change of the object to the audit log,
+// updates that changed nothing are not recorded.
+func (uc *ObjectUsecase) recordChange(ctx context.Context, id string, before, after []domain.AuditField) error {
+	event := domain.NewAuditEvent(domain.AuditEntityObject, id, before, after)
+	if event.Action == domain.AuditActionUpdate && len(event.Changes) == 0 {
+		return nil
+	}
+
+	return uc.addAuditEvent(ctx, event)
+}
+
+func (uc *ObjectUsecase) addAuditEvent(ctx context.Context, event domain.AuditEvent) error {
+	event.Id = uc.generator.GenerateId()
+
+	if err := uc.auditRepo.AddAuditEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to add audit event - %w", err)
 	}
 
 	return nil
 }
 
-func (uc *ObjectUsecase) SetObjectPhotoPath(ctx context.Context, id, path string) error {
-	object, err := uc.objRepo.GetObjectById(ctx, id)
+// parseOptionPredicates converts values of predicates to types of their custom options,
+// so they are compared with stored values the same way as they are sorted.
+func (uc *ObjectUsecase) parseOptionPredicates(
+	ctx context.Context,
+	predicates []domain.OptionPredicate,
+) ([]domain.OptionPredicate, error) {
+	if len(predicates) == 0 {
+		return predicates, nil
+	}
+
+	ids := make([]string, len(predicates))
+	for i, p := range predicates {
+		ids[i] = p.CustomOptionId
+	}
+
+	customOptions, err := uc.custOptRepo.GetCustomOptionsByIds(ctx, ids)
 	if err != nil {
-		return fmt.Errorf("failed to get object - %w", err)
+		return nil, fmt.Errorf("failed to get custom options - %w", err)
 	}
 
-	object.PhotoPath = path
+	parsed := make([]domain.OptionPredicate, len(predicates))
+	for i, p := range predicates {
+		idx := slices.IndexFunc(customOptions, func(co domain.CustomOption) bool {
+			return co.Id == p.CustomOptionId
+		})
+		if idx == -1 {
+			return nil, fmt.Errorf(
+				"custom option '%s' not found - %w",
+				p.CustomOptionId,
+				domain.ErrInvalidValue,
+			)
+		}
+
+		typedValue, err := customOptions[idx].ParseValue(p.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of '%s' - %w", customOptions[idx].Name, err)
+		}
+
+		parsed[i] = p
+		parsed[i].TypedValue = typedValue
+	}
+
+	return parsed, nil
+}
+
+// parseCustomOptionValues validates raw values against types of their
+// custom options and fills typed and canonical representations of them.
+func (uc *ObjectUsecase) parseCustomOptionValues(
+	ctx context.Context,
+	objectCustomOptions []domain.ObjectCustomOption,
+) error {
+	if len(objectCustomOptions) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(objectCustomOptions))
+	for i, opt := range objectCustomOptions {
+		ids[i] = opt.CustomOptionId
+	}
+
+	customOptions, err := uc.custOptRepo.GetCustomOptionsByIds(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get custom options - %w", err)
+	}
+
+	for i, opt := range objectCustomOptions {
+		idx := slices.IndexFunc(customOptions, func(co domain.CustomOption) bool {
+			return co.Id == opt.CustomOptionId
+		})
+		if idx == -1 {
+			return fmt.Errorf("unknown custom option '%s' - %w", opt.CustomOptionId, domain.ErrInvalidValue)
+		}
+
+		typedValue, err := customOptions[idx].ParseValue(opt.Value)
+		if err != nil {
+			return fmt.Errorf("invalid value of '%s' - %w", customOptions[idx].Name, err)
+		}
 
-	if err := uc.objRepo.UpdateObject(ctx, object); err != nil {
-		return fmt.Errorf("failed to update object - %w", err)
+		objectCustomOptions[i].TypedValue = typedValue
+		objectCustomOptions[i].Value = customOptions[idx].FormatValue(typedValue)
 	}
 
 	return nil
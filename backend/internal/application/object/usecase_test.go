@@ -2,6 +2,7 @@ package object
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -15,8 +16,12 @@ func TestObjects(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		objRepo := mocks.NewObjectRepositoryMock()
 		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewObjectUsecase(objRepo, custOptObjRepo, generator)
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
 		returnedObjects := []domain.Object{
 			{
 				Id:           "231934sadas9123deqw",
@@ -25,7 +30,6 @@ func TestObjects(t *testing.T) {
 				CreatedAt:    time.Now(),
 				Advs:         "Good SUV",
 				Disadvs:      "Hard to find some details",
-				PhotoPath:    "/cars/231934sadas9123deqw.jpg",
 				ComparisonId: "85434230werhuhi123912304",
 			},
 		}
@@ -47,16 +51,20 @@ func TestObjects(t *testing.T) {
 		filter := domain.ObjectFilter{
 			Limit:   2,
 			Offset:  0,
-			OrderBy: "created",
+			OrderBy: []domain.SortKey{{Field: "rating", Desc: true}, {Field: "name"}},
 		}
 
-		objRepo.On("GetObjects", ctx, filter).Return(returnedObjects, nil)
-		custOptObjRepo.On("GetObjectCustomOptionsByObjectId", ctx, returnedObjects[0].Id).Return(returnedOptions, nil)
+		returnedPageInfo := domain.PageInfo{Total: 1}
 
-		objects, err := uc.GetObjects(ctx, filter)
+		objRepo.On("GetObjects", ctx, filter).Return(returnedObjects, returnedPageInfo, nil)
+		custOptObjRepo.On("GetObjectCustomOptionsByObjectIds", ctx, []string{returnedObjects[0].Id}).
+			Return(returnedOptions, nil)
+
+		objects, pageInfo, err := uc.GetObjects(ctx, filter)
 
 		assert.NoError(t, err)
 		assert.Equal(t, returnedObjects, objects)
+		assert.Equal(t, returnedPageInfo, pageInfo)
 		assert.Equal(t, returnedOptions, objects[0].ObjectCustomOptions)
 		objRepo.AssertExpectations(t)
 		custOptObjRepo.AssertExpectations(t)
@@ -65,8 +73,12 @@ func TestObjects(t *testing.T) {
 	t.Run("Error", func(t *testing.T) {
 		objRepo := mocks.NewObjectRepositoryMock()
 		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewObjectUsecase(objRepo, custOptObjRepo, generator)
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
 
 		ctx := context.Background()
 		filter := domain.ObjectFilter{
@@ -74,22 +86,267 @@ func TestObjects(t *testing.T) {
 			Offset: 0,
 		}
 
-		objRepo.On("GetObjects", ctx, filter).Return(nil, assert.AnError)
-		objects, err := uc.GetObjects(ctx, filter)
+		objRepo.On("GetObjects", ctx, filter).Return(nil, nil, assert.AnError)
+		objects, _, err := uc.GetObjects(ctx, filter)
 
 		assert.Error(t, err)
 		assert.Nil(t, objects)
-		custOptObjRepo.AssertNotCalled(t, "GetObjectCustomOptionsByObjectId")
+		custOptObjRepo.AssertNotCalled(t, "GetObjectCustomOptionsByObjectIds")
 		objRepo.AssertExpectations(t)
 	})
+
+	t.Run("Option predicates", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+		filter := domain.ObjectFilter{
+			Limit:   2,
+			OrderBy: []domain.SortKey{{Field: "option:432230ewrew3424rwe", Desc: true}},
+			OptionPredicates: []domain.OptionPredicate{
+				{
+					CustomOptionId: "432230ewrew3424rwe",
+					Operator:       domain.OptionOperatorGte,
+					Value:          "500",
+				},
+			},
+		}
+
+		parsedFilter := filter
+		parsedFilter.OptionPredicates = []domain.OptionPredicate{
+			{
+				CustomOptionId: "432230ewrew3424rwe",
+				Operator:       domain.OptionOperatorGte,
+				Value:          "500",
+				TypedValue:     int64(500),
+			},
+		}
+
+		custOptRepo.On("GetCustomOptionsByIds", ctx, []string{"432230ewrew3424rwe"}).
+			Return([]domain.CustomOption{
+				{
+					Id:   "432230ewrew3424rwe",
+					Name: "Power",
+					Type: domain.CustomOptionTypeInteger,
+				},
+			}, nil)
+		objRepo.On("GetObjects", ctx, parsedFilter).Return([]domain.Object{}, domain.PageInfo{}, nil)
+
+		objects, _, err := uc.GetObjects(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Empty(t, objects)
+		objRepo.AssertExpectations(t)
+		custOptRepo.AssertExpectations(t)
+		custOptObjRepo.AssertNotCalled(t, "GetObjectCustomOptionsByObjectIds")
+	})
+
+	t.Run("Full-text search", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+		filter := domain.ObjectFilter{
+			Limit:   2,
+			Query:   "batteries leather",
+			OrderBy: []domain.SortKey{{Field: domain.ScoreOrdering, Desc: true}},
+		}
+
+		objRepo.On("GetObjects", ctx, filter).Return([]domain.Object{
+			{Id: "231934sadas9123deqw", Name: "BMW X5", Advs: "Big battery, fast charging", Score: 1.5},
+			{Id: "91234sadas9123deqw", Name: "Audi Q7", Score: 0.75},
+		}, domain.PageInfo{Total: 2}, nil)
+		custOptObjRepo.On("GetObjectCustomOptionsByObjectIds", ctx, []string{"231934sadas9123deqw", "91234sadas9123deqw"}).
+			Return([]domain.ObjectCustomOption{
+				{ObjectId: "91234sadas9123deqw", CustomOptionId: "52342rwerew23123", Value: "Leather seats"},
+			}, nil)
+
+		objects, _, err := uc.GetObjects(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Highlight{
+			Field: "advs",
+			Texts: []domain.HighlightText{
+				{Value: "Big "},
+				{Value: "battery", Hit: true},
+				{Value: ", fast charging"},
+			},
+		}, objects[0].Highlight)
+		assert.Equal(t, domain.Highlight{
+			Field:          "option",
+			CustomOptionId: "52342rwerew23123",
+			Texts: []domain.HighlightText{
+				{Value: "Leather", Hit: true},
+				{Value: " seats"},
+			},
+		}, objects[1].Highlight)
+	})
+
+	t.Run("Invalid option predicate value", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+		filter := domain.ObjectFilter{
+			Limit: 2,
+			OptionPredicates: []domain.OptionPredicate{
+				{
+					CustomOptionId: "432230ewrew3424rwe",
+					Operator:       domain.OptionOperatorEq,
+					Value:          "maybe",
+				},
+			},
+		}
+
+		custOptRepo.On("GetCustomOptionsByIds", ctx, []string{"432230ewrew3424rwe"}).
+			Return([]domain.CustomOption{
+				{
+					Id:   "432230ewrew3424rwe",
+					Name: "AWD",
+					Type: domain.CustomOptionTypeBoolean,
+				},
+			}, nil)
+
+		objects, _, err := uc.GetObjects(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		assert.Nil(t, objects)
+		objRepo.AssertNotCalled(t, "GetObjects")
+	})
+}
+
+// listingObjectRepository returns a page of generated objects without recording calls,
+// so it does not affect measurements of GetObjects.
+type listingObjectRepository struct {
+	*mocks.ObjectRepositoryMock
+	objects []domain.Object
+}
+
+func (repo *listingObjectRepository) GetObjects(
+	ctx context.Context,
+	filter domain.ObjectFilter,
+) ([]domain.Object, domain.PageInfo, error) {
+	objects := make([]domain.Object, len(repo.objects))
+	copy(objects, repo.objects)
+
+	return objects, domain.PageInfo{Total: int64(len(objects))}, nil
+}
+
+// countingObjectCustomOptionRepository counts queries of object custom options.
+type countingObjectCustomOptionRepository struct {
+	*mocks.ObjectCustomOptionRepositoryMock
+	calls int
+}
+
+func (repo *countingObjectCustomOptionRepository) GetObjectCustomOptionsByObjectId(
+	ctx context.Context,
+	objectId string,
+) ([]domain.ObjectCustomOption, error) {
+	repo.calls++
+
+	return []domain.ObjectCustomOption{{ObjectId: objectId, CustomOptionId: "432230ewrew3424rwe", Value: "600"}}, nil
+}
+
+func (repo *countingObjectCustomOptionRepository) GetObjectCustomOptionsByObjectIds(
+	ctx context.Context,
+	objectIds []string,
+) ([]domain.ObjectCustomOption, error) {
+	repo.calls++
+
+	options := make([]domain.ObjectCustomOption, len(objectIds))
+	for i, id := range objectIds {
+		options[i] = domain.ObjectCustomOption{ObjectId: id, CustomOptionId: "432230ewrew3424rwe", Value: "600"}
+	}
+
+	return options, nil
+}
+
+func newListingUsecase(pageSize int) (*ObjectUsecase, *countingObjectCustomOptionRepository) {
+	objects := make([]domain.Object, pageSize)
+	for i := range objects {
+		objects[i] = domain.Object{Id: fmt.Sprintf("object%d", i), Name: fmt.Sprintf("Object %d", i)}
+	}
+
+	objRepo := &listingObjectRepository{ObjectRepositoryMock: mocks.NewObjectRepositoryMock(), objects: objects}
+	custOptObjRepo := &countingObjectCustomOptionRepository{
+		ObjectCustomOptionRepositoryMock: mocks.NewObjectCustomOptionRepositoryMock(),
+	}
+
+	uc := NewObjectUsecase(
+		objRepo,
+		custOptObjRepo,
+		mocks.NewCustomOptionRepositoryMock(),
+		mocks.NewComparisonRepositoryMock(),
+		mocks.NewAuditRepositoryMock(),
+		mocks.NewInMemoryTransactor(),
+		mocks.NewMockGenerator(),
+	)
+
+	return uc, custOptObjRepo
+}
+
+func TestGetObjectsQueriesCustomOptionsOnce(t *testing.T) {
+	uc, custOptObjRepo := newListingUsecase(100)
+
+	objects, _, err := uc.GetObjects(context.Background(), domain.ObjectFilter{Limit: 100})
+
+	assert.NoError(t, err)
+	assert.Len(t, objects, 100)
+	assert.Equal(t, 1, custOptObjRepo.calls)
+	for _, obj := range objects {
+		assert.Equal(t, []domain.ObjectCustomOption{
+			{ObjectId: obj.Id, CustomOptionId: "432230ewrew3424rwe", Value: "600"},
+		}, obj.ObjectCustomOptions)
+	}
+}
+
+func BenchmarkGetObjects(b *testing.B) {
+	uc, custOptObjRepo := newListingUsecase(100)
+	ctx := context.Background()
+	filter := domain.ObjectFilter{Limit: 100}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := uc.GetObjects(ctx, filter); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+
+	if queriesPerOp := float64(custOptObjRepo.calls) / float64(b.N); queriesPerOp > 1 {
+		b.Fatalf("expected 1 custom options query per listing, got %.0f", queriesPerOp)
+	}
+	b.ReportMetric(float64(custOptObjRepo.calls)/float64(b.N), "queries/op")
 }
 
 func TestGetObjectById(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		objRepo := mocks.NewObjectRepositoryMock()
 		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewObjectUsecase(objRepo, custOptObjRepo, generator)
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
 
 		returnedObject := domain.Object{
 			Id:           "231934sadas9123deqw",
@@ -98,7 +355,6 @@ func TestGetObjectById(t *testing.T) {
 			CreatedAt:    time.Now(),
 			Advs:         "Good SUV",
 			Disadvs:      "Hard to find some details",
-			PhotoPath:    "/cars/231934sadas9123deqw.jpg",
 			ComparisonId: "85434230werhuhi123912304",
 		}
 
@@ -136,8 +392,12 @@ func TestGetObjectById(t *testing.T) {
 	t.Run("Error", func(t *testing.T) {
 		objRepo := mocks.NewObjectRepositoryMock()
 		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewObjectUsecase(objRepo, custOptObjRepo, generator)
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
 
 		ctx := context.Background()
 		id := "213213ewrwe9423432"
@@ -157,8 +417,12 @@ func TestCreateObject(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		objRepo := mocks.NewObjectRepositoryMock()
 		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewObjectUsecase(objRepo, custOptObjRepo, generator)
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
 
 		inputObject := domain.Object{
 			Name:         "BMW X5",
@@ -166,7 +430,6 @@ func TestCreateObject(t *testing.T) {
 			CreatedAt:    time.Now(),
 			Advs:         "Good SUV",
 			Disadvs:      "Hard to find some details",
-			PhotoPath:    "/cars/231934sadas9123deqw.jpg",
 			ComparisonId: "85434230werhuhi123912304",
 		}
 
@@ -177,23 +440,34 @@ func TestCreateObject(t *testing.T) {
 				object.Rating == inputObject.Rating &&
 				object.Advs == inputObject.Advs &&
 				object.Disadvs == inputObject.Disadvs &&
-				object.PhotoPath == inputObject.PhotoPath &&
 				object.ComparisonId == inputObject.ComparisonId
 		})).Return(nil)
 		generator.On("GenerateId").Return("231934sadas9123deqw")
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Entity == domain.AuditEntityObject &&
+				event.EntityId == "231934sadas9123deqw" &&
+				event.Action == domain.AuditActionCreate &&
+				len(event.Changes) == 5
+		})).Return(nil)
 
 		id, err := uc.CreateObject(ctx, inputObject)
 
 		assert.Equal(t, "231934sadas9123deqw", id)
 		assert.NoError(t, err)
+		assert.Equal(t, 1, transactor.Committed)
 		objRepo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
 	})
 
 	t.Run("Error", func(t *testing.T) {
 		objRepo := mocks.NewObjectRepositoryMock()
 		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewObjectUsecase(objRepo, custOptObjRepo, generator)
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
 
 		inputObject := domain.Object{
 			Name:         "BMW X5",
@@ -201,7 +475,6 @@ func TestCreateObject(t *testing.T) {
 			CreatedAt:    time.Now(),
 			Advs:         "Good SUV",
 			Disadvs:      "Hard to find some details",
-			PhotoPath:    "/cars/231934sadas9123deqw.jpg",
 			ComparisonId: "85434230werhuhi123912304",
 		}
 
@@ -212,7 +485,6 @@ func TestCreateObject(t *testing.T) {
 				object.Rating == inputObject.Rating &&
 				object.Advs == inputObject.Advs &&
 				object.Disadvs == inputObject.Disadvs &&
-				object.PhotoPath == inputObject.PhotoPath &&
 				object.ComparisonId == inputObject.ComparisonId
 		})).Return(assert.AnError)
 		generator.On("GenerateId").Return("231934sadas9123deqw")
@@ -221,16 +493,169 @@ func TestCreateObject(t *testing.T) {
 
 		assert.Empty(t, id)
 		assert.Error(t, err)
+		assert.Equal(t, 1, transactor.RolledBack)
 		objRepo.AssertExpectations(t)
 	})
+
+	t.Run("Custom option failure rolls back", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
+
+		inputObject := domain.Object{
+			Name:         "BMW X5",
+			Rating:       8,
+			ComparisonId: "85434230werhuhi123912304",
+			ObjectCustomOptions: []domain.ObjectCustomOption{
+				{CustomOptionId: "432230ewrew3424rwe", Value: "600"},
+				{CustomOptionId: "52342rwerew23123", Value: "2021"},
+			},
+		}
+
+		ctx := context.Background()
+
+		custOptRepo.On("GetCustomOptionsByIds", ctx, []string{"432230ewrew3424rwe", "52342rwerew23123"}).
+			Return([]domain.CustomOption{
+				{Id: "432230ewrew3424rwe", Name: "Power", Type: domain.CustomOptionTypeInteger},
+				{Id: "52342rwerew23123", Name: "Release year", Type: domain.CustomOptionTypeInteger},
+			}, nil)
+		generator.On("GenerateId").Return("231934sadas9123deqw")
+		objRepo.On("CreateObject", ctx, mock.Anything).Return(nil)
+		custOptObjRepo.On("AddObjectCustomOption", ctx, mock.MatchedBy(func(oco domain.ObjectCustomOption) bool {
+			return oco.CustomOptionId == "432230ewrew3424rwe"
+		})).Return(nil)
+		custOptObjRepo.On("AddObjectCustomOption", ctx, mock.MatchedBy(func(oco domain.ObjectCustomOption) bool {
+			return oco.CustomOptionId == "52342rwerew23123"
+		})).Return(assert.AnError)
+
+		id, err := uc.CreateObject(ctx, inputObject)
+
+		assert.Empty(t, id)
+		assert.Error(t, err)
+		assert.Equal(t, 0, transactor.Committed)
+		assert.Equal(t, 1, transactor.RolledBack)
+		objRepo.AssertExpectations(t)
+		custOptObjRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid custom option value", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
+
+		inputObject := domain.Object{
+			Name:         "BMW X5",
+			Rating:       8,
+			ComparisonId: "85434230werhuhi123912304",
+			ObjectCustomOptions: []domain.ObjectCustomOption{
+				{
+					CustomOptionId: "432230ewrew3424rwe",
+					Value:          "six hundred",
+				},
+			},
+		}
+
+		ctx := context.Background()
+
+		custOptRepo.On("GetCustomOptionsByIds", ctx, []string{"432230ewrew3424rwe"}).
+			Return([]domain.CustomOption{
+				{
+					Id:   "432230ewrew3424rwe",
+					Name: "Power",
+					Type: domain.CustomOptionTypeInteger,
+				},
+			}, nil)
+
+		id, err := uc.CreateObject(ctx, inputObject)
+
+		assert.Empty(t, id)
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		objRepo.AssertNotCalled(t, "CreateObject")
+		custOptObjRepo.AssertNotCalled(t, "AddObjectCustomOption")
+	})
+
+	t.Run("Non-finite custom option value", func(t *testing.T) {
+		decimal := domain.CustomOption{
+			Id:   "432230ewrew3424rwe",
+			Name: "Fuel consumption",
+			Type: domain.CustomOptionTypeDecimal,
+		}
+		money := domain.CustomOption{
+			Id:       "432230ewrew3424rwe",
+			Name:     "Price",
+			Type:     domain.CustomOptionTypeMoney,
+			Currency: "USD",
+		}
+
+		tests := []struct {
+			name   string
+			option domain.CustomOption
+			value  string
+		}{
+			{name: "Decimal NaN", option: decimal, value: "NaN"},
+			{name: "Decimal Inf", option: decimal, value: "Inf"},
+			{name: "Decimal +Inf", option: decimal, value: "+Inf"},
+			{name: "Decimal -Inf", option: decimal, value: "-Inf"},
+			{name: "Money NaN", option: money, value: "NaN"},
+			{name: "Money NaN with currency", option: money, value: "NaN USD"},
+			{name: "Money Inf with leading currency", option: money, value: "USD Inf"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				objRepo := mocks.NewObjectRepositoryMock()
+				custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+				custOptRepo := mocks.NewCustomOptionRepositoryMock()
+				comparisonRepo := mocks.NewComparisonRepositoryMock()
+				auditRepo := mocks.NewAuditRepositoryMock()
+				transactor := mocks.NewInMemoryTransactor()
+				generator := mocks.NewMockGenerator()
+				uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
+
+				inputObject := domain.Object{
+					Name:         "BMW X5",
+					Rating:       8,
+					ComparisonId: "85434230werhuhi123912304",
+					ObjectCustomOptions: []domain.ObjectCustomOption{
+						{CustomOptionId: tt.option.Id, Value: tt.value},
+					},
+				}
+
+				ctx := context.Background()
+
+				custOptRepo.On("GetCustomOptionsByIds", ctx, []string{tt.option.Id}).
+					Return([]domain.CustomOption{tt.option}, nil)
+
+				id, err := uc.CreateObject(ctx, inputObject)
+
+				assert.Empty(t, id)
+				assert.ErrorIs(t, err, domain.ErrInvalidValue)
+				objRepo.AssertNotCalled(t, "CreateObject")
+			})
+		}
+	})
 }
 
 func TestUpdateObject(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		objRepo := mocks.NewObjectRepositoryMock()
 		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewObjectUsecase(objRepo, custOptObjRepo, generator)
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
 
 		returnedOnGetObject := domain.Object{
 			Id:           "231934sadas9123deqw",
@@ -239,7 +664,6 @@ func TestUpdateObject(t *testing.T) {
 			CreatedAt:    time.Now(),
 			Advs:         "Good SUV",
 			Disadvs:      "Hard to find some details",
-			PhotoPath:    "/cars/231934sadas9123deqw.jpg",
 			ComparisonId: "85434230werhuhi123912304",
 			ObjectCustomOptions: []domain.ObjectCustomOption{
 				{
@@ -256,7 +680,6 @@ func TestUpdateObject(t *testing.T) {
 			CreatedAt:    time.Now(),
 			Advs:         "Very good SUV",
 			Disadvs:      "Easy to find some details",
-			PhotoPath:    "/cars/231934sadas9123deqw.jpg",
 			ComparisonId: "85434230werhuhi123912304",
 			ObjectCustomOptions: []domain.ObjectCustomOption{
 				{
@@ -268,7 +691,9 @@ func TestUpdateObject(t *testing.T) {
 
 		changedObject := inputObject
 		changedObject.Id = returnedOnGetObject.Id
+		changedObject.CreatedAt = returnedOnGetObject.CreatedAt
 		changedObject.ObjectCustomOptions[0].ObjectId = returnedOnGetObject.Id
+		changedObject.ObjectCustomOptions[0].TypedValue = int64(800)
 
 		id := "231934sadas9123deqw"
 
@@ -277,23 +702,89 @@ func TestUpdateObject(t *testing.T) {
 		objRepo.On("GetObjectById", ctx, id).Return(returnedOnGetObject, nil)
 		objRepo.On("UpdateObject", ctx, changedObject).Return(nil)
 
+		custOptRepo.On("GetCustomOptionsByIds", ctx, []string{"432230ewrew3424rwe"}).
+			Return([]domain.CustomOption{
+				{
+					Id:   "432230ewrew3424rwe",
+					Name: "Power",
+					Type: domain.CustomOptionTypeInteger,
+				},
+			}, nil)
+
 		custOptObjRepo.On("GetObjectCustomOptionsByObjectId", ctx, returnedOnGetObject.Id).
 			Return(returnedOnGetObject.ObjectCustomOptions, nil)
 
-		custOptObjRepo.On("UpdateObjectCustomOption", ctx, inputObject.ObjectCustomOptions[0]).Return(nil)
+		custOptObjRepo.On("UpdateObjectCustomOption", ctx, changedObject.ObjectCustomOptions[0]).Return(nil)
+
+		generator.On("GenerateId").Return("5435fdgdfg3454dfgd")
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Id == "5435fdgdfg3454dfgd" &&
+				event.EntityId == id &&
+				event.Action == domain.AuditActionUpdate &&
+				assert.ObjectsAreEqual([]domain.FieldChange{
+					{Field: "rating", Before: 8, After: 9},
+					{Field: "advs", Before: "Good SUV", After: "Very good SUV"},
+					{Field: "disadvs", Before: "Hard to find some details", After: "Easy to find some details"},
+					{Field: "custom_options.432230ewrew3424rwe", Before: "600", After: "800"},
+				}, event.Changes)
+		})).Return(nil)
 
 		err := uc.UpdateObject(ctx, id, inputObject)
 
 		assert.NoError(t, err)
 		objRepo.AssertExpectations(t)
 		custOptObjRepo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Nothing changed", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
+
+		existingObject := domain.Object{
+			Id:           "231934sadas9123deqw",
+			Name:         "BMW X5",
+			Rating:       8,
+			ComparisonId: "85434230werhuhi123912304",
+		}
+
+		ctx := context.Background()
+
+		existingOptions := []domain.ObjectCustomOption{
+			{ObjectId: existingObject.Id, CustomOptionId: "432230ewrew3424rwe", Value: "600"},
+		}
+
+		// options missing in the input are written along with the object
+		updatedObject := existingObject
+		updatedObject.ObjectCustomOptions = existingOptions
+
+		objRepo.On("GetObjectById", ctx, existingObject.Id).Return(existingObject, nil)
+		objRepo.On("UpdateObject", ctx, updatedObject).Return(nil)
+		custOptObjRepo.On("GetObjectCustomOptionsByObjectId", ctx, existingObject.Id).
+			Return(existingOptions, nil)
+
+		err := uc.UpdateObject(ctx, existingObject.Id, domain.Object{Name: "BMW X5", Rating: 8})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, transactor.Committed)
+		auditRepo.AssertNotCalled(t, "AddAuditEvent")
 	})
 
 	t.Run("Error", func(t *testing.T) {
 		objRepo := mocks.NewObjectRepositoryMock()
 		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewObjectUsecase(objRepo, custOptObjRepo, generator)
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
 
 		inputObject := domain.Object{
 			Name:         "BMW X5",
@@ -301,7 +792,6 @@ func TestUpdateObject(t *testing.T) {
 			CreatedAt:    time.Now(),
 			Advs:         "Very good SUV",
 			Disadvs:      "Easy to find some details",
-			PhotoPath:    "/cars/231934sadas9123deqw.jpg",
 			ComparisonId: "85434230werhuhi123912304",
 			ObjectCustomOptions: []domain.ObjectCustomOption{
 				{
@@ -325,102 +815,174 @@ func TestUpdateObject(t *testing.T) {
 		objRepo.AssertNotCalled(t, "UpdateObjectCustomOption")
 		custOptObjRepo.AssertExpectations(t)
 	})
+
+	t.Run("Unknown custom option", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
+
+		inputObject := domain.Object{
+			Name:   "BMW X5",
+			Rating: 9,
+			ObjectCustomOptions: []domain.ObjectCustomOption{
+				{
+					CustomOptionId: "deleted3424rwe",
+					Value:          "800",
+				},
+			},
+		}
+
+		id := "231934sadas9123deqw"
+
+		ctx := context.Background()
+
+		objRepo.On("GetObjectById", ctx, id).Return(domain.Object{
+			Id:           id,
+			Name:         "BMW X5",
+			ComparisonId: "85434230werhuhi123912304",
+		}, nil)
+		custOptRepo.On("GetCustomOptionsByIds", ctx, []string{"deleted3424rwe"}).Return([]domain.CustomOption{}, nil)
+
+		err := uc.UpdateObject(ctx, id, inputObject)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		assert.NotErrorIs(t, err, domain.ErrNotFound)
+		objRepo.AssertNotCalled(t, "UpdateObject")
+		assert.Equal(t, 0, transactor.Committed)
+	})
 }
 
 func TestDeleteObject(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		objRepo := mocks.NewObjectRepositoryMock()
 		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewObjectUsecase(objRepo, custOptObjRepo, generator)
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
 
 		ctx := context.Background()
 		id := "34543dfsdfj32432jewr"
 
-		objRepo.On("DeleteObject", ctx, id).Return(nil)
+		returnedObject := domain.Object{Id: id, Name: "BMW X5"}
+
+		objRepo.On("GetObjectById", ctx, id).Return(returnedObject, nil)
+		custOptObjRepo.On("GetObjectCustomOptionsByObjectId", ctx, id).
+			Return([]domain.ObjectCustomOption{{ObjectId: id, CustomOptionId: "432230ewrew3424rwe", Value: "600"}}, nil)
+		objRepo.On("DeleteObject", ctx, id, mock.AnythingOfType("time.Time")).Return(nil)
+		generator.On("GenerateId").Return("5435fdgdfg3454dfgd")
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Action == domain.AuditActionDelete &&
+				assert.ObjectsAreEqual([]domain.FieldChange{
+					{Field: "name", Before: "BMW X5"},
+					{Field: "custom_options.432230ewrew3424rwe", Before: "600"},
+				}, event.Changes)
+		})).Return(nil)
 
 		err := uc.DeleteObject(ctx, id)
 
 		assert.NoError(t, err)
+		assert.Equal(t, 1, transactor.Committed)
+		auditRepo.AssertExpectations(t)
 		objRepo.AssertExpectations(t)
+		custOptObjRepo.AssertExpectations(t)
 	})
 
 	t.Run("Error", func(t *testing.T) {
 		objRepo := mocks.NewObjectRepositoryMock()
 		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewObjectUsecase(objRepo, custOptObjRepo, generator)
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
 
 		ctx := context.Background()
 		id := "92133easd123srewr132"
 
-		objRepo.On("DeleteObject", ctx, id).Return(assert.AnError)
+		objRepo.On("GetObjectById", ctx, id).Return(domain.Object{Id: id}, nil)
+		custOptObjRepo.On("GetObjectCustomOptionsByObjectId", ctx, id).Return([]domain.ObjectCustomOption{}, nil)
+		objRepo.On("DeleteObject", ctx, id, mock.AnythingOfType("time.Time")).Return(assert.AnError)
 
 		err := uc.DeleteObject(ctx, id)
 
 		assert.Error(t, err)
+		assert.Equal(t, 1, transactor.RolledBack)
 		objRepo.AssertExpectations(t)
+		auditRepo.AssertNotCalled(t, "AddAuditEvent")
 	})
 }
 
-func TestSetObjectPhotoPath(t *testing.T) {
+func TestRestoreObject(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		objRepo := mocks.NewObjectRepositoryMock()
 		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewObjectUsecase(objRepo, custOptObjRepo, generator)
-
-		object := domain.Object{
-			Id:           "231934sadas9123deqw",
-			Name:         "BMW X5",
-			Rating:       8,
-			CreatedAt:    time.Now(),
-			Advs:         "Good SUV",
-			Disadvs:      "Hard to find some details",
-			ComparisonId: "85434230werhuhi123912304",
-			ObjectCustomOptions: []domain.ObjectCustomOption{
-				{
-					ObjectId:       "231934sadas9123deqw",
-					CustomOptionId: "432230ewrew3424rwe",
-					Value:          "600",
-				},
-			},
-		}
-
-		id := "92133easd123srewr132"
-		path := "/photos/4324123sfnjsadn1239213.jpg"
-
-		changedObject := object
-		changedObject.PhotoPath = path
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
 
 		ctx := context.Background()
+		id := "34543dfsdfj32432jewr"
 
-		objRepo.On("GetObjectById", ctx, id).Return(object, nil)
-		objRepo.On("UpdateObject", ctx, changedObject).Return(nil)
+		deletedObject := domain.Object{Id: id, Name: "BMW X5", ComparisonId: "85434230werhuhi123912304", DeletedAt: time.Now()}
+
+		objRepo.On("GetDeletedObjectById", ctx, id).Return(deletedObject, nil)
+		comparisonRepo.On("GetComparisonById", ctx, "85434230werhuhi123912304").
+			Return(domain.Comparison{Id: "85434230werhuhi123912304"}, nil)
+		custOptObjRepo.On("GetObjectCustomOptionsByObjectId", ctx, id).
+			Return([]domain.ObjectCustomOption{{ObjectId: id, CustomOptionId: "432230ewrew3424rwe", Value: "600"}}, nil)
+		objRepo.On("RestoreObject", ctx, id).Return(nil)
+		generator.On("GenerateId").Return("5435fdgdfg3454dfgd")
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Action == domain.AuditActionRestore &&
+				assert.ObjectsAreEqual([]domain.FieldChange{
+					{Field: "name", After: "BMW X5"},
+					{Field: "comparison_id", After: "85434230werhuhi123912304"},
+					{Field: "custom_options.432230ewrew3424rwe", After: "600"},
+				}, event.Changes)
+		})).Return(nil)
 
-		err := uc.SetObjectPhotoPath(ctx, id, path)
+		err := uc.RestoreObject(ctx, id)
 
 		assert.NoError(t, err)
+		assert.Equal(t, 1, transactor.Committed)
 		objRepo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
 	})
 
-	t.Run("Error", func(t *testing.T) {
+	t.Run("Comparison is in trash", func(t *testing.T) {
 		objRepo := mocks.NewObjectRepositoryMock()
 		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
 		generator := mocks.NewMockGenerator()
-		uc := NewObjectUsecase(objRepo, custOptObjRepo, generator)
-
-		id := "231934sadas9123deqw"
-		path := "/photos/4324123sfnjsadn1239213.jpg"
+		uc := NewObjectUsecase(objRepo, custOptObjRepo, custOptRepo, comparisonRepo, auditRepo, transactor, generator)
 
 		ctx := context.Background()
+		id := "34543dfsdfj32432jewr"
 
-		objRepo.On("GetObjectById", ctx, id).Return(nil, assert.AnError)
+		deletedObject := domain.Object{Id: id, Name: "BMW X5", ComparisonId: "85434230werhuhi123912304", DeletedAt: time.Now()}
 
-		err := uc.SetObjectPhotoPath(ctx, id, path)
+		objRepo.On("GetDeletedObjectById", ctx, id).Return(deletedObject, nil)
+		comparisonRepo.On("GetComparisonById", ctx, "85434230werhuhi123912304").Return(nil, domain.ErrNotFound)
 
-		assert.Error(t, err)
-		custOptObjRepo.AssertNotCalled(t, "GetObjectCustomOptionsByObjectId")
-		objRepo.AssertNotCalled(t, "UpdateObject")
+		err := uc.RestoreObject(ctx, id)
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		objRepo.AssertNotCalled(t, "RestoreObject")
+		auditRepo.AssertNotCalled(t, "AddAuditEvent")
 	})
 }
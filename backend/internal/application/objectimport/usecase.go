@@ -0,0 +1,375 @@
+package objectimport
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+)
+
+type ImportUsecase struct {
+	comparisonRepo ComparisonRepository
+	objRepo        ObjectRepository
+	custOptObjRepo ObjectCustomOptionRepository
+	custOptRepo    CustomOptionRepository
+	auditRepo      AuditRepository
+	transactor     Transactor
+	generator      IdGenerator
+}
+
+type ComparisonRepository interface {
+	GetComparisonById(ctx context.Context, id string) (domain.Comparison, error)
+	UpdateComparison(ctx context.Context, comparison domain.Comparison) error
+}
+
+type ObjectRepository interface {
+	CreateObject(ctx context.Context, object domain.Object) error
+}
+
+type ObjectCustomOptionRepository interface {
+	AddObjectCustomOption(ctx context.Context, objectCustomOption domain.ObjectCustomOption) error
+}
+
+type CustomOptionRepository interface {
+	GetCustomOptionsByNames(ctx context.Context, names []string) ([]domain.CustomOption, error)
+	CreateCustomOption(ctx context.Context, customOption domain.CustomOption) error
+}
+
+type AuditRepository interface {
+	AddAuditEvent(ctx context.Context, event domain.AuditEvent) error
+}
+
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type IdGenerator interface {
+	GenerateId() string
+}
+
+func NewImportUsecase(
+	comparisonRepo ComparisonRepository,
+	objRepo ObjectRepository,
+	custOptObjRepo ObjectCustomOptionRepository,
+	custOptRepo CustomOptionRepository,
+	auditRepo AuditRepository,
+	transactor Transactor,
+	generator IdGenerator,
+) *ImportUsecase {
+	return &ImportUsecase{
+		comparisonRepo: comparisonRepo,
+		objRepo:        objRepo,
+		custOptObjRepo: custOptObjRepo,
+		custOptRepo:    custOptRepo,
+		auditRepo:      auditRepo,
+		transactor:     transactor,
+		generator:      generator,
+	}
+}
+
+// ImportObjects creates an object of the comparison for every row of the table. Rows are
+// validated first and nothing is written if any of them is invalid, in that case the report
+// is returned together with domain.ErrInvalidValue. Custom options used by the table are
+// added to the comparison, missing ones are created when allowed, with types given by
+// the table or as text options.
+func (uc *ImportUsecase) ImportObjects(
+	ctx context.Context,
+	comparisonId string,
+	table domain.ImportTable,
+	opts domain.ImportOptions,
+) (domain.ImportReport, error) {
+	report := domain.ImportReport{DryRun: opts.DryRun}
+
+	if len(table.Rows) > domain.MaxImportRows {
+		return report, fmt.Errorf(
+			"no more than %d rows can be imported at once - %w",
+			domain.MaxImportRows,
+			domain.ErrInvalidValue,
+		)
+	}
+
+	columns, err := domain.NewImportColumns(table.Header)
+	if err != nil {
+		return report, fmt.Errorf("invalid header - %w", err)
+	}
+
+	comparison, err := uc.comparisonRepo.GetComparisonById(ctx, comparisonId)
+	if err != nil {
+		return report, fmt.Errorf("failed to get comparison - %w", err)
+	}
+
+	customOptions, created, err := uc.resolveCustomOptions(ctx, columns.CustomOptions, table, opts.CreateCustomOptions)
+	if err != nil {
+		return report, err
+	}
+
+	for _, co := range created {
+		report.CreatedCustomOptions = append(report.CreatedCustomOptions, co.Name)
+	}
+
+	objects := make([]domain.Object, 0, len(table.Rows))
+	for i, row := range table.Rows {
+		if isEmptyRow(row) {
+			continue
+		}
+
+		object, errs := toObject(columns, customOptions, row)
+		object.ComparisonId = comparison.Id
+
+		report.Rows = append(report.Rows, domain.ImportRowResult{
+			Row:    i + 2,
+			Name:   object.Name,
+			Errors: errs,
+		})
+
+		if len(errs) != 0 {
+			report.Invalid++
+			continue
+		}
+
+		objects = append(objects, object)
+	}
+
+	report.Total = len(report.Rows)
+
+	if report.Total == 0 {
+		return report, fmt.Errorf("no rows to import - %w", domain.ErrInvalidValue)
+	}
+
+	if report.Invalid != 0 {
+		return report, fmt.Errorf(
+			"%d of %d rows are invalid, nothing is imported - %w",
+			report.Invalid,
+			report.Total,
+			domain.ErrInvalidValue,
+		)
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	// options of the table are shown among options of the comparison after import
+	comparisonBefore := comparison.AuditFields()
+	comparison.CustomOptionIds = slices.Clone(comparison.CustomOptionIds)
+	newComparisonOptions := false
+	for _, name := range columns.CustomOptions {
+		if !slices.Contains(comparison.CustomOptionIds, customOptions[name].Id) {
+			comparison.CustomOptionIds = append(comparison.CustomOptionIds, customOptions[name].Id)
+			newComparisonOptions = true
+		}
+	}
+
+	for i := range objects {
+		objects[i].Id = uc.generator.GenerateId()
+		objects[i].CreatedAt = time.Now()
+
+		for j := range objects[i].ObjectCustomOptions {
+			objects[i].ObjectCustomOptions[j].ObjectId = objects[i].Id
+		}
+	}
+
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, co := range created {
+			if err := uc.custOptRepo.CreateCustomOption(ctx, co); err != nil {
+				return fmt.Errorf("failed to create custom option - %w", err)
+			}
+
+			if err := uc.recordChange(ctx, domain.AuditEntityCustomOption, co.Id, nil, co.AuditFields()); err != nil {
+				return err
+			}
+		}
+
+		if newComparisonOptions {
+			if err := uc.comparisonRepo.UpdateComparison(ctx, comparison); err != nil {
+				return fmt.Errorf("failed to add custom options to comparison - %w", err)
+			}
+
+			err := uc.recordChange(ctx, domain.AuditEntityComparison, comparison.Id, comparisonBefore, comparison.AuditFields())
+			if err != nil {
+				return err
+			}
+		}
+
+		for i := range objects {
+			if err := uc.objRepo.CreateObject(ctx, objects[i]); err != nil {
+				return fmt.Errorf("failed to create object of row %d - %w", report.Rows[i].Row, err)
+			}
+
+			for _, oco := range objects[i].ObjectCustomOptions {
+				if err := uc.custOptObjRepo.AddObjectCustomOption(ctx, oco); err != nil {
+					return fmt.Errorf("failed to add custom option of row %d - %w", report.Rows[i].Row, err)
+				}
+			}
+
+			err := uc.recordChange(ctx, domain.AuditEntityObject, objects[i].Id, nil, objects[i].AuditFields())
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	for i, object := range objects {
+		report.Rows[i].ObjectId = object.Id
+	}
+
+	report.Imported = len(objects)
+
+	return report, nil
+}
+
+// resolveCustomOptions finds custom options by names of columns. Options that do not exist
+// are returned as created ones if creation is allowed, they are stored only on import.
+// Created options get the type of the definition given by the table, if there is one.
+func (uc *ImportUsecase) resolveCustomOptions(
+	ctx context.Context,
+	names []string,
+	table domain.ImportTable,
+	create bool,
+) (map[string]domain.CustomOption, []domain.CustomOption, error) {
+	customOptions := make(map[string]domain.CustomOption, len(names))
+	if len(names) == 0 {
+		return customOptions, nil, nil
+	}
+
+	found, err := uc.custOptRepo.GetCustomOptionsByNames(ctx, names)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get custom options - %w", err)
+	}
+
+	for _, co := range found {
+		customOptions[co.Name] = co
+	}
+
+	missing := make([]string, 0)
+	for _, name := range names {
+		if _, ok := customOptions[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) != 0 && !create {
+		return nil, nil, fmt.Errorf(
+			"custom options '%s' do not exist, allow to create them or rename the columns - %w",
+			strings.Join(missing, "', '"),
+			domain.ErrInvalidValue,
+		)
+	}
+
+	created := make([]domain.CustomOption, len(missing))
+	for i, name := range missing {
+		co := domain.CustomOption{Name: name, Type: domain.CustomOptionTypeText}
+
+		idx := slices.IndexFunc(table.CustomOptions, func(def domain.CustomOption) bool {
+			return def.Name == name
+		})
+		if idx != -1 {
+			co.Type = table.CustomOptions[idx].Type
+			co.EnumValues = table.CustomOptions[idx].EnumValues
+			co.Currency = table.CustomOptions[idx].Currency
+
+			if err := co.Validate(); err != nil {
+				return nil, nil, fmt.Errorf("invalid custom option '%s' - %w", name, err)
+			}
+		}
+
+		co.Id = uc.generator.GenerateId()
+		created[i] = co
+		customOptions[name] = co
+	}
+
+	return customOptions, created, nil
+}
+
+// toObject converts the row to an object, empty cells of custom options leave them unset.
+// All problems of the row are returned, so they can be fixed at once.
+func toObject(
+	columns domain.ImportColumns,
+	customOptions map[string]domain.CustomOption,
+	row []string,
+) (domain.Object, []string) {
+	errs := make([]string, 0)
+
+	object := domain.Object{
+		Name:                columns.Field(row, "name"),
+		Advs:                columns.Field(row, "advs"),
+		Disadvs:             columns.Field(row, "disadvs"),
+		ObjectCustomOptions: make([]domain.ObjectCustomOption, 0, len(columns.CustomOptions)),
+	}
+
+	ratingParsed := true
+	if rating := columns.Field(row, "rating"); rating != "" {
+		parsed, err := strconv.Atoi(rating)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("rating: '%s' is not an integer", rating))
+			ratingParsed = false
+		}
+
+		object.Rating = parsed
+	}
+
+	for _, name := range columns.CustomOptions {
+		value := columns.Option(row, name)
+		if value == "" {
+			continue
+		}
+
+		co := customOptions[name]
+
+		typedValue, err := co.ParseValue(value)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid value of '%s' - %s", name, err))
+			continue
+		}
+
+		object.ObjectCustomOptions = append(object.ObjectCustomOptions, domain.ObjectCustomOption{
+			CustomOptionId: co.Id,
+			Value:          co.FormatValue(typedValue),
+			TypedValue:     typedValue,
+		})
+	}
+
+	// rating that is not an integer is reported already, the rest of fields are still checked
+	validated := object
+	if !ratingParsed {
+		validated.Rating = 1
+	}
+
+	if err := validated.Validate(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	return object, errs
+}
+
+func isEmptyRow(row []string) bool {
+	return !slices.ContainsFunc(row, func(cell string) bool {
+		return strings.TrimSpace(cell) != ""
+	})
+}
+
+// recordChange adds the change of the entity to the audit log.
+func (uc *ImportUsecase) recordChange(
+	ctx context.Context,
+	entity domain.AuditEntity,
+	id string,
+	before, after []domain.AuditField,
+) error {
+	event := domain.NewAuditEvent(entity, id, before, after)
+	event.Id = uc.generator.GenerateId()
+
+	if err := uc.auditRepo.AddAuditEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to add audit event - %w", err)
+	}
+
+	return nil
+}
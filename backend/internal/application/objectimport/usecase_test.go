@@ -0,0 +1,371 @@
+package objectimport
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/Unlites/comparison_center/backend/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var importComparison = domain.Comparison{
+	Id:              "85434230werhuhi123912304",
+	Name:            "Cars",
+	CustomOptionIds: []string{"432230ewrew3424rwe"},
+}
+
+var importCustomOptions = []domain.CustomOption{
+	{Id: "432230ewrew3424rwe", Name: "Power", Type: domain.CustomOptionTypeInteger},
+	{Id: "52342rwerew23123", Name: "Release year", Type: domain.CustomOptionTypeInteger},
+}
+
+func TestImportObjects(t *testing.T) {
+	table := domain.ImportTable{
+		Header: []string{"Name", "Rating", "Advs", "Disadvs", "created_at", "Power", "Release year"},
+		Rows: [][]string{
+			{"BMW X5", "8", "Fast", "", "2024-01-02T03:04:05Z", "600", "2021"},
+			{"", "", "", "", "", "", ""},
+			{"Audi Q7", "7", "", "Expensive", "", " 400 ", ""},
+		},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewImportUsecase(comparisonRepo, objRepo, custOptObjRepo, custOptRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		comparisonRepo.On("GetComparisonById", ctx, importComparison.Id).Return(importComparison, nil)
+		custOptRepo.On("GetCustomOptionsByNames", ctx, []string{"Power", "Release year"}).
+			Return(importCustomOptions, nil)
+		comparisonRepo.On("UpdateComparison", ctx, mock.MatchedBy(func(c domain.Comparison) bool {
+			return assert.ObjectsAreEqual([]string{"432230ewrew3424rwe", "52342rwerew23123"}, c.CustomOptionIds)
+		})).Return(nil)
+		generator.On("GenerateId").Return("231934sadas9123deqw").Once()
+		generator.On("GenerateId").Return("9123deqw231934sadas").Once()
+		generator.On("GenerateId").Return("5435fdgdfg3454dfgd")
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Entity == domain.AuditEntityComparison &&
+				event.EntityId == importComparison.Id &&
+				event.Action == domain.AuditActionUpdate
+		})).Return(nil).Once()
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Entity == domain.AuditEntityObject &&
+				event.EntityId == "231934sadas9123deqw" &&
+				event.Action == domain.AuditActionCreate &&
+				slices.ContainsFunc(event.Changes, func(change domain.FieldChange) bool {
+					return change.Field == "custom_options.432230ewrew3424rwe" && change.After == "600"
+				})
+		})).Return(nil).Once()
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Entity == domain.AuditEntityObject &&
+				event.EntityId == "9123deqw231934sadas" &&
+				event.Action == domain.AuditActionCreate
+		})).Return(nil).Once()
+		objRepo.On("CreateObject", ctx, mock.MatchedBy(func(object domain.Object) bool {
+			return object.Id == "231934sadas9123deqw" &&
+				object.Name == "BMW X5" &&
+				object.Rating == 8 &&
+				object.Advs == "Fast" &&
+				object.ComparisonId == importComparison.Id
+		})).Return(nil)
+		objRepo.On("CreateObject", ctx, mock.MatchedBy(func(object domain.Object) bool {
+			return object.Id == "9123deqw231934sadas" && object.Name == "Audi Q7" && object.Disadvs == "Expensive"
+		})).Return(nil)
+		custOptObjRepo.On("AddObjectCustomOption", ctx, domain.ObjectCustomOption{
+			ObjectId: "231934sadas9123deqw", CustomOptionId: "432230ewrew3424rwe", Value: "600", TypedValue: int64(600),
+		}).Return(nil)
+		custOptObjRepo.On("AddObjectCustomOption", ctx, domain.ObjectCustomOption{
+			ObjectId: "231934sadas9123deqw", CustomOptionId: "52342rwerew23123", Value: "2021", TypedValue: int64(2021),
+		}).Return(nil)
+		custOptObjRepo.On("AddObjectCustomOption", ctx, domain.ObjectCustomOption{
+			ObjectId: "9123deqw231934sadas", CustomOptionId: "432230ewrew3424rwe", Value: "400", TypedValue: int64(400),
+		}).Return(nil)
+
+		report, err := uc.ImportObjects(ctx, importComparison.Id, table, domain.ImportOptions{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Total)
+		assert.Equal(t, 2, report.Imported)
+		assert.Equal(t, []domain.ImportRowResult{
+			{Row: 2, Name: "BMW X5", ObjectId: "231934sadas9123deqw", Errors: []string{}},
+			{Row: 4, Name: "Audi Q7", ObjectId: "9123deqw231934sadas", Errors: []string{}},
+		}, report.Rows)
+		assert.Equal(t, 1, transactor.Committed)
+		comparisonRepo.AssertExpectations(t)
+		objRepo.AssertExpectations(t)
+		custOptObjRepo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewImportUsecase(comparisonRepo, objRepo, custOptObjRepo, custOptRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		comparisonRepo.On("GetComparisonById", ctx, importComparison.Id).Return(importComparison, nil)
+		custOptRepo.On("GetCustomOptionsByNames", ctx, []string{"Power", "Release year"}).
+			Return(importCustomOptions, nil)
+
+		report, err := uc.ImportObjects(ctx, importComparison.Id, table, domain.ImportOptions{DryRun: true})
+
+		assert.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 2, report.Total)
+		assert.Zero(t, report.Imported)
+		assert.Zero(t, transactor.Committed)
+		comparisonRepo.AssertNotCalled(t, "UpdateComparison", mock.Anything, mock.Anything)
+		objRepo.AssertNotCalled(t, "CreateObject", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid rows", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewImportUsecase(comparisonRepo, objRepo, custOptObjRepo, custOptRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		invalidTable := domain.ImportTable{
+			Header: []string{"name", "rating", "Power"},
+			Rows: [][]string{
+				{"BMW X5", "8", "600"},
+				{"", "eight", "many"},
+				{"Audi Q7", "11"},
+			},
+		}
+
+		comparisonRepo.On("GetComparisonById", ctx, importComparison.Id).Return(importComparison, nil)
+		custOptRepo.On("GetCustomOptionsByNames", ctx, []string{"Power"}).
+			Return(importCustomOptions[:1], nil)
+
+		report, err := uc.ImportObjects(ctx, importComparison.Id, invalidTable, domain.ImportOptions{})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		assert.Equal(t, 3, report.Total)
+		assert.Equal(t, 2, report.Invalid)
+		assert.Zero(t, report.Imported)
+		assert.Empty(t, report.Rows[0].Errors)
+		assert.Len(t, report.Rows[1].Errors, 3)
+		assert.Equal(t, []string{"rating: must be between 1 and 10 - invalid value"}, report.Rows[2].Errors)
+		objRepo.AssertNotCalled(t, "CreateObject", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Missing custom options", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewImportUsecase(comparisonRepo, objRepo, custOptObjRepo, custOptRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		comparisonRepo.On("GetComparisonById", ctx, importComparison.Id).Return(importComparison, nil)
+		custOptRepo.On("GetCustomOptionsByNames", ctx, []string{"Power", "Release year"}).
+			Return(importCustomOptions[:1], nil)
+
+		_, err := uc.ImportObjects(ctx, importComparison.Id, table, domain.ImportOptions{})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		objRepo.AssertNotCalled(t, "CreateObject", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Create missing custom options", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewImportUsecase(comparisonRepo, objRepo, custOptObjRepo, custOptRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		createTable := domain.ImportTable{
+			Header: []string{"name", "rating", "Color"},
+			Rows:   [][]string{{"BMW X5", "8", "Black"}},
+		}
+
+		comparisonRepo.On("GetComparisonById", ctx, importComparison.Id).Return(importComparison, nil)
+		custOptRepo.On("GetCustomOptionsByNames", ctx, []string{"Color"}).Return(nil, nil)
+		generator.On("GenerateId").Return("76575ytrytr6575ytr").Once()
+		generator.On("GenerateId").Return("231934sadas9123deqw").Once()
+		generator.On("GenerateId").Return("5435fdgdfg3454dfgd")
+		custOptRepo.On("CreateCustomOption", ctx, domain.CustomOption{
+			Id: "76575ytrytr6575ytr", Name: "Color", Type: domain.CustomOptionTypeText,
+		}).Return(nil)
+		auditRepo.On("AddAuditEvent", ctx, mock.MatchedBy(func(event domain.AuditEvent) bool {
+			return event.Entity == domain.AuditEntityCustomOption &&
+				event.EntityId == "76575ytrytr6575ytr" &&
+				event.Action == domain.AuditActionCreate
+		})).Return(nil).Once()
+		auditRepo.On("AddAuditEvent", ctx, mock.Anything).Return(nil)
+		comparisonRepo.On("UpdateComparison", ctx, mock.MatchedBy(func(c domain.Comparison) bool {
+			return assert.ObjectsAreEqual([]string{"432230ewrew3424rwe", "76575ytrytr6575ytr"}, c.CustomOptionIds)
+		})).Return(nil)
+		objRepo.On("CreateObject", ctx, mock.Anything).Return(nil)
+		custOptObjRepo.On("AddObjectCustomOption", ctx, domain.ObjectCustomOption{
+			ObjectId: "231934sadas9123deqw", CustomOptionId: "76575ytrytr6575ytr", Value: "Black", TypedValue: "Black",
+		}).Return(nil)
+
+		report, err := uc.ImportObjects(ctx, importComparison.Id, createTable, domain.ImportOptions{
+			CreateCustomOptions: true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Color"}, report.CreatedCustomOptions)
+		assert.Equal(t, 1, report.Imported)
+		custOptRepo.AssertExpectations(t)
+		comparisonRepo.AssertExpectations(t)
+		custOptObjRepo.AssertExpectations(t)
+		auditRepo.AssertNumberOfCalls(t, "AddAuditEvent", 3)
+	})
+
+	t.Run("Create missing custom options with types of the table", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewImportUsecase(comparisonRepo, objRepo, custOptObjRepo, custOptRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		typedTable := domain.ImportTable{
+			Header: []string{"name", "rating", "Price"},
+			Rows:   [][]string{{"BMW X5", "8", "90000.00 USD"}},
+			CustomOptions: []domain.CustomOption{
+				{Name: "Price", Type: domain.CustomOptionTypeMoney, Currency: "USD"},
+			},
+		}
+
+		comparisonRepo.On("GetComparisonById", ctx, importComparison.Id).Return(importComparison, nil)
+		custOptRepo.On("GetCustomOptionsByNames", ctx, []string{"Price"}).Return(nil, nil)
+		generator.On("GenerateId").Return("76575ytrytr6575ytr").Once()
+		generator.On("GenerateId").Return("231934sadas9123deqw").Once()
+		generator.On("GenerateId").Return("5435fdgdfg3454dfgd")
+		custOptRepo.On("CreateCustomOption", ctx, domain.CustomOption{
+			Id: "76575ytrytr6575ytr", Name: "Price", Type: domain.CustomOptionTypeMoney, Currency: "USD",
+		}).Return(nil)
+		comparisonRepo.On("UpdateComparison", ctx, mock.Anything).Return(nil)
+		objRepo.On("CreateObject", ctx, mock.Anything).Return(nil)
+		custOptObjRepo.On("AddObjectCustomOption", ctx, domain.ObjectCustomOption{
+			ObjectId:       "231934sadas9123deqw",
+			CustomOptionId: "76575ytrytr6575ytr",
+			Value:          "90000.00 USD",
+			TypedValue:     float64(90000),
+		}).Return(nil)
+		auditRepo.On("AddAuditEvent", ctx, mock.Anything).Return(nil)
+
+		report, err := uc.ImportObjects(ctx, importComparison.Id, typedTable, domain.ImportOptions{
+			CreateCustomOptions: true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Imported)
+		custOptRepo.AssertExpectations(t)
+		custOptObjRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid custom option of the table", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewImportUsecase(comparisonRepo, objRepo, custOptObjRepo, custOptRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		typedTable := domain.ImportTable{
+			Header:        []string{"name", "rating", "Price"},
+			Rows:          [][]string{{"BMW X5", "8", "90000"}},
+			CustomOptions: []domain.CustomOption{{Name: "Price", Type: domain.CustomOptionTypeMoney}},
+		}
+
+		comparisonRepo.On("GetComparisonById", ctx, importComparison.Id).Return(importComparison, nil)
+		custOptRepo.On("GetCustomOptionsByNames", ctx, []string{"Price"}).Return(nil, nil)
+
+		_, err := uc.ImportObjects(ctx, importComparison.Id, typedTable, domain.ImportOptions{
+			CreateCustomOptions: true,
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		custOptRepo.AssertNotCalled(t, "CreateCustomOption", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error rolls back", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewImportUsecase(comparisonRepo, objRepo, custOptObjRepo, custOptRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		comparisonRepo.On("GetComparisonById", ctx, importComparison.Id).Return(importComparison, nil)
+		custOptRepo.On("GetCustomOptionsByNames", ctx, []string{"Power", "Release year"}).
+			Return(importCustomOptions, nil)
+		comparisonRepo.On("UpdateComparison", ctx, mock.Anything).Return(nil)
+		auditRepo.On("AddAuditEvent", ctx, mock.Anything).Return(nil)
+		generator.On("GenerateId").Return("231934sadas9123deqw")
+		objRepo.On("CreateObject", ctx, mock.Anything).Return(assert.AnError)
+
+		report, err := uc.ImportObjects(ctx, importComparison.Id, table, domain.ImportOptions{})
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Zero(t, report.Imported)
+		assert.Empty(t, report.Rows[0].ObjectId)
+		assert.Equal(t, 1, transactor.RolledBack)
+	})
+
+	t.Run("Comparison not found", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		auditRepo := mocks.NewAuditRepositoryMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewImportUsecase(comparisonRepo, objRepo, custOptObjRepo, custOptRepo, auditRepo, transactor, generator)
+
+		ctx := context.Background()
+
+		comparisonRepo.On("GetComparisonById", ctx, importComparison.Id).Return(nil, domain.ErrNotFound)
+
+		_, err := uc.ImportObjects(ctx, importComparison.Id, table, domain.ImportOptions{})
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
@@ -0,0 +1,445 @@
+package photo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+)
+
+type PhotoUsecase struct {
+	objRepo    ObjectRepository
+	photoRepo  PhotoRepository
+	photoStore PhotoStore
+	processor  PhotoProcessor
+	transactor Transactor
+	generator  IdGenerator
+}
+
+type ObjectRepository interface {
+	GetObjectById(ctx context.Context, id string) (domain.Object, error)
+}
+
+type PhotoRepository interface {
+	GetPhotosByObjectIds(ctx context.Context, objectIds []string) ([]domain.Photo, error)
+	GetPhotoById(ctx context.Context, id string) (domain.Photo, error)
+	CreatePhoto(ctx context.Context, photo domain.Photo) error
+	UpdatePhotos(ctx context.Context, photos []domain.Photo) error
+	DeletePhoto(ctx context.Context, id string) error
+}
+
+type PhotoStore interface {
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadSeekCloser, domain.PhotoInfo, error)
+	Stat(ctx context.Context, key string) (domain.PhotoInfo, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type PhotoProcessor interface {
+	Process(ctx context.Context, content io.Reader) ([]domain.PhotoVariant, error)
+}
+
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type IdGenerator interface {
+	GenerateId() string
+}
+
+func NewPhotoUsecase(
+	objRepo ObjectRepository,
+	photoRepo PhotoRepository,
+	photoStore PhotoStore,
+	processor PhotoProcessor,
+	transactor Transactor,
+	generator IdGenerator,
+) *PhotoUsecase {
+	return &PhotoUsecase{
+		objRepo:    objRepo,
+		photoRepo:  photoRepo,
+		photoStore: photoStore,
+		processor:  processor,
+		transactor: transactor,
+		generator:  generator,
+	}
+}
+
+// GetObjectPhotos gives photos of the object gallery in their order.
+func (uc *PhotoUsecase) GetObjectPhotos(ctx context.Context, objectId string) ([]domain.Photo, error) {
+	if _, err := uc.objRepo.GetObjectById(ctx, objectId); err != nil {
+		return nil, fmt.Errorf("failed to get object - %w", err)
+	}
+
+	photos, err := uc.photoRepo.GetPhotosByObjectIds(ctx, []string{objectId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get photos - %w", err)
+	}
+
+	return photos, nil
+}
+
+// AddObjectPhoto stores all sizes of the photo and appends it to the end of the object gallery.
+// The first photo of the gallery always becomes its cover.
+func (uc *PhotoUsecase) AddObjectPhoto(
+	ctx context.Context,
+	objectId string,
+	content io.Reader,
+	cover bool,
+) (domain.Photo, error) {
+	photo, stored, err := uc.storePhoto(ctx, objectId, content)
+	if err != nil {
+		return domain.Photo{}, err
+	}
+
+	photo.IsCover = cover
+
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		photos, err := uc.photoRepo.GetPhotosByObjectIds(ctx, []string{objectId})
+		if err != nil {
+			return fmt.Errorf("failed to get photos - %w", err)
+		}
+
+		if len(photos) >= domain.MaxObjectPhotos {
+			return fmt.Errorf(
+				"object can not have more than %d photos - %w",
+				domain.MaxObjectPhotos, domain.ErrInvalidValue,
+			)
+		}
+
+		if len(photos) == 0 {
+			photo.IsCover = true
+		} else {
+			photo.Position = photos[len(photos)-1].Position + 1
+
+			if photo.IsCover {
+				if err := uc.photoRepo.UpdatePhotos(ctx, setCover(photos, "")); err != nil {
+					return fmt.Errorf("failed to update photos - %w", err)
+				}
+			}
+		}
+
+		if err := uc.photoRepo.CreatePhoto(ctx, photo); err != nil {
+			return fmt.Errorf("failed to create photo - %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		uc.deletePhotos(ctx, stored)
+		return domain.Photo{}, err
+	}
+
+	return photo, nil
+}
+
+// ReplaceObjectCoverPhoto stores all sizes of the photo and makes it the cover of the object
+// gallery in place of the previous cover, which is removed with its files. Photo becomes
+// the first one of the gallery if it has no photos yet.
+func (uc *PhotoUsecase) ReplaceObjectCoverPhoto(
+	ctx context.Context,
+	objectId string,
+	content io.Reader,
+) (domain.Photo, error) {
+	photo, stored, err := uc.storePhoto(ctx, objectId, content)
+	if err != nil {
+		return domain.Photo{}, err
+	}
+
+	photo.IsCover = true
+
+	var previous domain.Photo
+
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		photos, err := uc.photoRepo.GetPhotosByObjectIds(ctx, []string{objectId})
+		if err != nil {
+			return fmt.Errorf("failed to get photos - %w", err)
+		}
+
+		for _, p := range photos {
+			if p.IsCover {
+				previous = p
+			}
+		}
+
+		if previous.Id != "" {
+			photo.Position = previous.Position
+
+			if err := uc.photoRepo.DeletePhoto(ctx, previous.Id); err != nil {
+				return fmt.Errorf("failed to delete previous cover - %w", err)
+			}
+		}
+
+		if err := uc.photoRepo.CreatePhoto(ctx, photo); err != nil {
+			return fmt.Errorf("failed to create photo - %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		uc.deletePhotos(ctx, stored)
+		return domain.Photo{}, err
+	}
+
+	if previous.Id == "" {
+		return photo, nil
+	}
+
+	// files are not a part of the transaction, so the previous cover is removed only after commit
+	for _, key := range previous.Keys() {
+		if err := uc.photoStore.Delete(ctx, key); err != nil {
+			return domain.Photo{}, fmt.Errorf("failed to delete previous cover - %w", err)
+		}
+	}
+
+	return photo, nil
+}
+
+// storePhoto processes the photo and puts all of its sizes to the photo store. The photo
+// is returned with keys of stored files, so they can be removed if it is not saved.
+func (uc *PhotoUsecase) storePhoto(
+	ctx context.Context,
+	objectId string,
+	content io.Reader,
+) (domain.Photo, []string, error) {
+	if _, err := uc.objRepo.GetObjectById(ctx, objectId); err != nil {
+		return domain.Photo{}, nil, fmt.Errorf("failed to get object - %w", err)
+	}
+
+	variants, err := uc.processor.Process(ctx, content)
+	if err != nil {
+		return domain.Photo{}, nil, fmt.Errorf("failed to process photo - %w", err)
+	}
+
+	photo := domain.Photo{
+		Id:        uc.generator.GenerateId(),
+		ObjectId:  objectId,
+		CreatedAt: time.Now(),
+	}
+	photo.Key = photo.Id + variants[0].Ext
+
+	stored := make([]string, 0, len(variants))
+	for _, variant := range variants {
+		key := domain.PhotoVariantKey(photo.Key, variant.Size)
+
+		err := uc.photoStore.Put(
+			ctx,
+			key,
+			bytes.NewReader(variant.Content),
+			int64(len(variant.Content)),
+			variant.ContentType,
+		)
+		if err != nil {
+			uc.deletePhotos(ctx, stored)
+			return domain.Photo{}, nil, fmt.Errorf("failed to put %s photo - %w", variant.Size, err)
+		}
+
+		stored = append(stored, key)
+
+		if variant.Size == domain.PhotoSizeOriginal {
+			photo.ContentType = variant.ContentType
+			photo.Size = int64(len(variant.Content))
+			photo.Width, photo.Height = variant.Width, variant.Height
+		}
+	}
+
+	return photo, stored, nil
+}
+
+// SetObjectCoverPhoto makes the photo the cover of the object gallery instead of the previous one.
+func (uc *PhotoUsecase) SetObjectCoverPhoto(ctx context.Context, objectId, photoId string) error {
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		photos, err := uc.GetObjectPhotos(ctx, objectId)
+		if err != nil {
+			return err
+		}
+
+		if _, err := findPhoto(photos, photoId); err != nil {
+			return err
+		}
+
+		if err := uc.photoRepo.UpdatePhotos(ctx, setCover(photos, photoId)); err != nil {
+			return fmt.Errorf("failed to update photos - %w", err)
+		}
+
+		return nil
+	})
+}
+
+// ReorderObjectPhotos places photos of the object gallery in the order of given ids.
+func (uc *PhotoUsecase) ReorderObjectPhotos(ctx context.Context, objectId string, photoIds []string) error {
+	return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		photos, err := uc.GetObjectPhotos(ctx, objectId)
+		if err != nil {
+			return err
+		}
+
+		if err := domain.ValidatePhotoOrder(photos, photoIds); err != nil {
+			return fmt.Errorf("failed to validate order - %w", err)
+		}
+
+		positions := make(map[string]int, len(photoIds))
+		for i, id := range photoIds {
+			positions[id] = i
+		}
+
+		for i := range photos {
+			photos[i].Position = positions[photos[i].Id]
+		}
+
+		if err := uc.photoRepo.UpdatePhotos(ctx, photos); err != nil {
+			return fmt.Errorf("failed to update photos - %w", err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteObjectPhoto removes the photo from the object gallery. When the cover is
+// removed, the first of remaining photos becomes the cover.
+func (uc *PhotoUsecase) DeleteObjectPhoto(ctx context.Context, objectId, photoId string) error {
+	var photo domain.Photo
+
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		photos, err := uc.GetObjectPhotos(ctx, objectId)
+		if err != nil {
+			return err
+		}
+
+		photo, err = findPhoto(photos, photoId)
+		if err != nil {
+			return err
+		}
+
+		if err := uc.photoRepo.DeletePhoto(ctx, photo.Id); err != nil {
+			return fmt.Errorf("failed to delete photo - %w", err)
+		}
+
+		if !photo.IsCover || len(photos) == 1 {
+			return nil
+		}
+
+		remaining := make([]domain.Photo, 0, len(photos)-1)
+		for _, p := range photos {
+			if p.Id != photo.Id {
+				remaining = append(remaining, p)
+			}
+		}
+
+		if err := uc.photoRepo.UpdatePhotos(ctx, setCover(remaining, remaining[0].Id)); err != nil {
+			return fmt.Errorf("failed to update photos - %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// files are not a part of the transaction, so the photo is removed only after commit
+	for _, key := range photo.Keys() {
+		if err := uc.photoStore.Delete(ctx, key); err != nil {
+			return fmt.Errorf("failed to delete photo - %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetObjectPhotoContent opens the photo of the object gallery in the given size, caller must close it.
+// Photos of objects in the trash are not served, as the rest of their galleries.
+func (uc *PhotoUsecase) GetObjectPhotoContent(
+	ctx context.Context,
+	objectId, photoId string,
+	size domain.PhotoSize,
+) (io.ReadSeekCloser, domain.PhotoInfo, error) {
+	if _, err := uc.objRepo.GetObjectById(ctx, objectId); err != nil {
+		return nil, domain.PhotoInfo{}, fmt.Errorf("failed to get object - %w", err)
+	}
+
+	photo, err := uc.photoRepo.GetPhotoById(ctx, photoId)
+	if err != nil {
+		return nil, domain.PhotoInfo{}, fmt.Errorf("failed to get photo - %w", err)
+	}
+
+	if photo.ObjectId != objectId {
+		return nil, domain.PhotoInfo{}, fmt.Errorf("photo of the object %w", domain.ErrNotFound)
+	}
+
+	return uc.openPhoto(ctx, photo, size)
+}
+
+// GetObjectCoverContent opens the cover of the object gallery in the given size, caller must close it.
+func (uc *PhotoUsecase) GetObjectCoverContent(
+	ctx context.Context,
+	objectId string,
+	size domain.PhotoSize,
+) (io.ReadSeekCloser, domain.PhotoInfo, error) {
+	photos, err := uc.GetObjectPhotos(ctx, objectId)
+	if err != nil {
+		return nil, domain.PhotoInfo{}, err
+	}
+
+	for _, photo := range photos {
+		if photo.IsCover {
+			return uc.openPhoto(ctx, photo, size)
+		}
+	}
+
+	return nil, domain.PhotoInfo{}, fmt.Errorf("object has no photo - %w", domain.ErrNotFound)
+}
+
+// openPhoto opens the photo in the given size. Photos uploaded before sizes
+// were introduced have only the original, it is given for any size.
+func (uc *PhotoUsecase) openPhoto(
+	ctx context.Context,
+	photo domain.Photo,
+	size domain.PhotoSize,
+) (io.ReadSeekCloser, domain.PhotoInfo, error) {
+	content, info, err := uc.photoStore.Get(ctx, domain.PhotoVariantKey(photo.Key, size))
+	if errors.Is(err, domain.ErrNotFound) && size != domain.PhotoSizeOriginal {
+		content, info, err = uc.photoStore.Get(ctx, photo.Key)
+	}
+
+	if err != nil {
+		return nil, domain.PhotoInfo{}, fmt.Errorf("failed to get photo content - %w", err)
+	}
+
+	return content, info, nil
+}
+
+// deletePhotos cleans up photos stored by the failed upload, the upload error is
+// more important for the caller, so cleanup errors are not reported.
+func (uc *PhotoUsecase) deletePhotos(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		uc.photoStore.Delete(ctx, key)
+	}
+}
+
+func findPhoto(photos []domain.Photo, id string) (domain.Photo, error) {
+	for _, photo := range photos {
+		if photo.Id == id {
+			return photo, nil
+		}
+	}
+
+	return domain.Photo{}, fmt.Errorf("photo of the object %w", domain.ErrNotFound)
+}
+
+// setCover marks the photo with the id as the cover and gives photos whose cover flag is changed.
+func setCover(photos []domain.Photo, id string) []domain.Photo {
+	changed := make([]domain.Photo, 0, 2)
+	for _, photo := range photos {
+		isCover := photo.Id == id
+		if photo.IsCover != isCover {
+			photo.IsCover = isCover
+			changed = append(changed, photo)
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,680 @@
+package photo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/Unlites/comparison_center/backend/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var processedVariants = []domain.PhotoVariant{
+	{Size: domain.PhotoSizeThumb, Content: []byte("thumb"), ContentType: "image/png", Ext: ".png", Width: 320, Height: 240},
+	{Size: domain.PhotoSizeMedium, Content: []byte("medium"), ContentType: "image/png", Ext: ".png", Width: 1280, Height: 960},
+	{Size: domain.PhotoSizeOriginal, Content: []byte("original"), ContentType: "image/png", Ext: ".png", Width: 2000, Height: 1500},
+}
+
+func galleryPhotos(objectId string) []domain.Photo {
+	return []domain.Photo{
+		{Id: "12312sadas123sad", ObjectId: objectId, Key: "12312sadas123sad.jpg", Position: 0, IsCover: true},
+		{Id: "65765fdgdf567fdg", ObjectId: objectId, Key: "65765fdgdf567fdg.jpg", Position: 1},
+		{Id: "98789hjkhj789hjk", ObjectId: objectId, Key: "98789hjkhj789hjk.jpg", Position: 2},
+	}
+}
+
+func TestGetObjectPhotos(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		photos := galleryPhotos(objectId)
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{objectId}).Return(photos, nil)
+
+		result, err := uc.GetObjectPhotos(ctx, objectId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, photos, result)
+	})
+
+	t.Run("Object not found", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(nil, domain.ErrNotFound)
+
+		_, err := uc.GetObjectPhotos(ctx, objectId)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		photoRepo.AssertNotCalled(t, "GetPhotosByObjectIds")
+	})
+}
+
+func TestAddObjectPhoto(t *testing.T) {
+	t.Run("First photo becomes cover", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		content := strings.NewReader("photo")
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		processor.On("Process", ctx, content).Return(processedVariants, nil)
+		generator.On("GenerateId").Return("4324123sfnjsadn1239213")
+		photoStore.On("Put", ctx, "4324123sfnjsadn1239213_thumb.png", mock.Anything, int64(5), "image/png").Return(nil)
+		photoStore.On("Put", ctx, "4324123sfnjsadn1239213_medium.png", mock.Anything, int64(6), "image/png").Return(nil)
+		photoStore.On("Put", ctx, "4324123sfnjsadn1239213.png", mock.Anything, int64(8), "image/png").Return(nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{objectId}).Return([]domain.Photo{}, nil)
+		photoRepo.On("CreatePhoto", ctx, mock.MatchedBy(func(photo domain.Photo) bool {
+			return photo.Id == "4324123sfnjsadn1239213" &&
+				photo.ObjectId == objectId &&
+				photo.Key == "4324123sfnjsadn1239213.png" &&
+				photo.ContentType == "image/png" &&
+				photo.Size == 8 &&
+				photo.Width == 2000 &&
+				photo.Height == 1500 &&
+				photo.Position == 0 &&
+				photo.IsCover
+		})).Return(nil)
+
+		photo, err := uc.AddObjectPhoto(ctx, objectId, content, false)
+
+		assert.NoError(t, err)
+		assert.True(t, photo.IsCover)
+		assert.Equal(t, 1, transactor.Committed)
+		photoStore.AssertExpectations(t)
+		photoRepo.AssertExpectations(t)
+		photoRepo.AssertNotCalled(t, "UpdatePhotos")
+	})
+
+	t.Run("Cover replaces previous cover", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		content := strings.NewReader("photo")
+		photos := galleryPhotos(objectId)
+
+		previousCover := photos[0]
+		previousCover.IsCover = false
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		processor.On("Process", ctx, content).Return(processedVariants, nil)
+		generator.On("GenerateId").Return("4324123sfnjsadn1239213")
+		photoStore.On("Put", ctx, mock.Anything, mock.Anything, mock.Anything, "image/png").Return(nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{objectId}).Return(photos, nil)
+		photoRepo.On("UpdatePhotos", ctx, []domain.Photo{previousCover}).Return(nil)
+		photoRepo.On("CreatePhoto", ctx, mock.MatchedBy(func(photo domain.Photo) bool {
+			return photo.Position == 3 && photo.IsCover
+		})).Return(nil)
+
+		_, err := uc.AddObjectPhoto(ctx, objectId, content, true)
+
+		assert.NoError(t, err)
+		photoRepo.AssertExpectations(t)
+	})
+
+	t.Run("Full gallery removes stored sizes", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		content := strings.NewReader("photo")
+
+		photos := make([]domain.Photo, domain.MaxObjectPhotos)
+		for i := range photos {
+			photos[i] = domain.Photo{Id: string(rune('a' + i)), ObjectId: objectId, Position: i}
+		}
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		processor.On("Process", ctx, content).Return(processedVariants, nil)
+		generator.On("GenerateId").Return("4324123sfnjsadn1239213")
+		photoStore.On("Put", ctx, mock.Anything, mock.Anything, mock.Anything, "image/png").Return(nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{objectId}).Return(photos, nil)
+		photoStore.On("Delete", ctx, "4324123sfnjsadn1239213_thumb.png").Return(nil)
+		photoStore.On("Delete", ctx, "4324123sfnjsadn1239213_medium.png").Return(nil)
+		photoStore.On("Delete", ctx, "4324123sfnjsadn1239213.png").Return(nil)
+
+		_, err := uc.AddObjectPhoto(ctx, objectId, content, false)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		assert.Equal(t, 1, transactor.RolledBack)
+		photoStore.AssertExpectations(t)
+		photoRepo.AssertNotCalled(t, "CreatePhoto")
+	})
+
+	t.Run("Invalid photo", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		content := strings.NewReader("not a photo")
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		processor.On("Process", ctx, content).Return(nil, domain.ErrInvalidValue)
+
+		_, err := uc.AddObjectPhoto(ctx, objectId, content, false)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		photoStore.AssertNotCalled(t, "Put")
+		photoRepo.AssertNotCalled(t, "CreatePhoto")
+	})
+
+	t.Run("Put error removes stored sizes", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		content := strings.NewReader("photo")
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		processor.On("Process", ctx, content).Return(processedVariants, nil)
+		generator.On("GenerateId").Return("4324123sfnjsadn1239213")
+		photoStore.On("Put", ctx, "4324123sfnjsadn1239213_thumb.png", mock.Anything, int64(5), "image/png").Return(nil)
+		photoStore.On("Put", ctx, "4324123sfnjsadn1239213_medium.png", mock.Anything, int64(6), "image/png").
+			Return(assert.AnError)
+		photoStore.On("Delete", ctx, "4324123sfnjsadn1239213_thumb.png").Return(nil)
+
+		_, err := uc.AddObjectPhoto(ctx, objectId, content, false)
+
+		assert.Error(t, err)
+		photoStore.AssertExpectations(t)
+		photoRepo.AssertNotCalled(t, "CreatePhoto")
+	})
+}
+
+func TestReplaceObjectCoverPhoto(t *testing.T) {
+	t.Run("Upload cover twice", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		firstContent := strings.NewReader("first")
+		secondContent := strings.NewReader("second")
+
+		gallery := make([]domain.Photo, 0)
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		processor.On("Process", ctx, firstContent).Return(processedVariants, nil)
+		processor.On("Process", ctx, secondContent).Return(processedVariants, nil)
+		generator.On("GenerateId").Return("4324123sfnjsadn1239213").Once()
+		generator.On("GenerateId").Return("9123fdsfsdf23423dsf").Once()
+		photoStore.On("Put", ctx, mock.Anything, mock.Anything, mock.Anything, "image/png").Return(nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{objectId}).Return([]domain.Photo{}, nil).Once()
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{objectId}).Return([]domain.Photo{
+			{
+				Id:       "4324123sfnjsadn1239213",
+				ObjectId: objectId,
+				Key:      "4324123sfnjsadn1239213.png",
+				IsCover:  true,
+			},
+		}, nil).Once()
+		photoRepo.On("CreatePhoto", ctx, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			gallery = append(gallery, args.Get(1).(domain.Photo))
+		})
+		photoRepo.On("DeletePhoto", ctx, "4324123sfnjsadn1239213").Return(nil).Run(func(args mock.Arguments) {
+			gallery = slices.DeleteFunc(gallery, func(p domain.Photo) bool {
+				return p.Id == args.String(1)
+			})
+		})
+		photoStore.On("Delete", ctx, "4324123sfnjsadn1239213_thumb.png").Return(nil)
+		photoStore.On("Delete", ctx, "4324123sfnjsadn1239213_medium.png").Return(nil)
+		photoStore.On("Delete", ctx, "4324123sfnjsadn1239213.png").Return(nil)
+
+		first, err := uc.ReplaceObjectCoverPhoto(ctx, objectId, firstContent)
+		assert.NoError(t, err)
+		assert.True(t, first.IsCover)
+		photoStore.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+
+		second, err := uc.ReplaceObjectCoverPhoto(ctx, objectId, secondContent)
+		assert.NoError(t, err)
+		assert.True(t, second.IsCover)
+
+		assert.Equal(t, 2, transactor.Committed)
+		assert.Len(t, gallery, 1)
+		assert.Equal(t, "9123fdsfsdf23423dsf", gallery[0].Id)
+		assert.True(t, gallery[0].IsCover)
+		photoStore.AssertExpectations(t)
+		photoRepo.AssertNotCalled(t, "UpdatePhotos")
+	})
+
+	t.Run("Cover keeps its place in gallery", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		content := strings.NewReader("photo")
+		photos := galleryPhotos(objectId)
+		photos[0].IsCover = false
+		photos[1].IsCover = true
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		processor.On("Process", ctx, content).Return(processedVariants, nil)
+		generator.On("GenerateId").Return("4324123sfnjsadn1239213")
+		photoStore.On("Put", ctx, mock.Anything, mock.Anything, mock.Anything, "image/png").Return(nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{objectId}).Return(photos, nil)
+		photoRepo.On("DeletePhoto", ctx, photos[1].Id).Return(nil)
+		photoRepo.On("CreatePhoto", ctx, mock.MatchedBy(func(photo domain.Photo) bool {
+			return photo.Position == 1 && photo.IsCover
+		})).Return(nil)
+		photoStore.On("Delete", ctx, mock.Anything).Return(nil)
+
+		_, err := uc.ReplaceObjectCoverPhoto(ctx, objectId, content)
+
+		assert.NoError(t, err)
+		photoRepo.AssertExpectations(t)
+		photoStore.AssertNumberOfCalls(t, "Delete", 3)
+	})
+
+	t.Run("Error keeps previous cover", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		content := strings.NewReader("photo")
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		processor.On("Process", ctx, content).Return(processedVariants, nil)
+		generator.On("GenerateId").Return("4324123sfnjsadn1239213")
+		photoStore.On("Put", ctx, mock.Anything, mock.Anything, mock.Anything, "image/png").Return(nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{objectId}).Return(galleryPhotos(objectId), nil)
+		photoRepo.On("DeletePhoto", ctx, "12312sadas123sad").Return(nil)
+		photoRepo.On("CreatePhoto", ctx, mock.Anything).Return(fmt.Errorf("connection lost"))
+		photoStore.On("Delete", ctx, "4324123sfnjsadn1239213_thumb.png").Return(nil)
+		photoStore.On("Delete", ctx, "4324123sfnjsadn1239213_medium.png").Return(nil)
+		photoStore.On("Delete", ctx, "4324123sfnjsadn1239213.png").Return(nil)
+
+		_, err := uc.ReplaceObjectCoverPhoto(ctx, objectId, content)
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, transactor.RolledBack)
+		// only files of the new photo are removed
+		photoStore.AssertExpectations(t)
+		photoStore.AssertNumberOfCalls(t, "Delete", 3)
+	})
+}
+
+func TestSetObjectCoverPhoto(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		photos := galleryPhotos(objectId)
+
+		previousCover, newCover := photos[0], photos[2]
+		previousCover.IsCover, newCover.IsCover = false, true
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{objectId}).Return(photos, nil)
+		photoRepo.On("UpdatePhotos", ctx, []domain.Photo{previousCover, newCover}).Return(nil)
+
+		err := uc.SetObjectCoverPhoto(ctx, objectId, newCover.Id)
+
+		assert.NoError(t, err)
+		photoRepo.AssertExpectations(t)
+	})
+
+	t.Run("Photo of another object", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{objectId}).Return(galleryPhotos(objectId), nil)
+
+		err := uc.SetObjectCoverPhoto(ctx, objectId, "4324123sfnjsadn1239213")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		photoRepo.AssertNotCalled(t, "UpdatePhotos")
+	})
+}
+
+func TestReorderObjectPhotos(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		photos := galleryPhotos(objectId)
+
+		reordered := galleryPhotos(objectId)
+		reordered[0].Position, reordered[1].Position, reordered[2].Position = 2, 0, 1
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{objectId}).Return(photos, nil)
+		photoRepo.On("UpdatePhotos", ctx, reordered).Return(nil)
+
+		err := uc.ReorderObjectPhotos(ctx, objectId, []string{photos[1].Id, photos[2].Id, photos[0].Id})
+
+		assert.NoError(t, err)
+		photoRepo.AssertExpectations(t)
+	})
+
+	t.Run("Incomplete order", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		photos := galleryPhotos(objectId)
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{objectId}).Return(photos, nil)
+
+		err := uc.ReorderObjectPhotos(ctx, objectId, []string{photos[1].Id, photos[1].Id, photos[0].Id})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		photoRepo.AssertNotCalled(t, "UpdatePhotos")
+	})
+}
+
+func TestDeleteObjectPhoto(t *testing.T) {
+	t.Run("Cover passes to the next photo", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		photos := galleryPhotos(objectId)
+
+		newCover := photos[1]
+		newCover.IsCover = true
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{objectId}).Return(photos, nil)
+		photoRepo.On("DeletePhoto", ctx, photos[0].Id).Return(nil)
+		photoRepo.On("UpdatePhotos", ctx, []domain.Photo{newCover}).Return(nil)
+		photoStore.On("Delete", ctx, "12312sadas123sad_thumb.jpg").Return(nil)
+		photoStore.On("Delete", ctx, "12312sadas123sad_medium.jpg").Return(nil)
+		photoStore.On("Delete", ctx, "12312sadas123sad.jpg").Return(nil)
+
+		err := uc.DeleteObjectPhoto(ctx, objectId, photos[0].Id)
+
+		assert.NoError(t, err)
+		photoRepo.AssertExpectations(t)
+		photoStore.AssertExpectations(t)
+	})
+
+	t.Run("Error keeps stored sizes", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		photos := galleryPhotos(objectId)
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{objectId}).Return(photos, nil)
+		photoRepo.On("DeletePhoto", ctx, photos[1].Id).Return(assert.AnError)
+
+		err := uc.DeleteObjectPhoto(ctx, objectId, photos[1].Id)
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, transactor.RolledBack)
+		photoStore.AssertNotCalled(t, "Delete")
+	})
+}
+
+func TestGetObjectPhotoContent(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		photo := galleryPhotos(objectId)[1]
+
+		content := mocks.NewPhotoContent("photo")
+		info := domain.PhotoInfo{
+			Key:         "65765fdgdf567fdg_thumb.jpg",
+			Size:        5,
+			ContentType: "image/jpeg",
+			ETag:        "0c4c3d06e0a0b9f9d8b6c1c7c3b1a6b2c1f3a8d6c1b7e9f6a1d9c8e1f2b3a4c5",
+			ModifiedAt:  time.Now(),
+		}
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		photoRepo.On("GetPhotoById", ctx, photo.Id).Return(photo, nil)
+		photoStore.On("Get", ctx, "65765fdgdf567fdg_thumb.jpg").Return(content, info, nil)
+
+		gotContent, gotInfo, err := uc.GetObjectPhotoContent(ctx, objectId, photo.Id, domain.PhotoSizeThumb)
+
+		assert.NoError(t, err)
+		assert.Equal(t, content, gotContent)
+		assert.Equal(t, info, gotInfo)
+	})
+
+	t.Run("Missing size falls back to original", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		photo := domain.Photo{Id: "12312sadas123sad", ObjectId: objectId, Key: "/app/photos/12312sadas123sad.jpg"}
+
+		content := mocks.NewPhotoContent("photo")
+		info := domain.PhotoInfo{Key: photo.Key, Size: 5, ContentType: "image/jpeg"}
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		photoRepo.On("GetPhotoById", ctx, photo.Id).Return(photo, nil)
+		photoStore.On("Get", ctx, "/app/photos/12312sadas123sad_medium.jpg").
+			Return(nil, domain.PhotoInfo{}, domain.ErrNotFound)
+		photoStore.On("Get", ctx, photo.Key).Return(content, info, nil)
+
+		gotContent, gotInfo, err := uc.GetObjectPhotoContent(ctx, objectId, photo.Id, domain.PhotoSizeMedium)
+
+		assert.NoError(t, err)
+		assert.Equal(t, content, gotContent)
+		assert.Equal(t, info, gotInfo)
+	})
+
+	t.Run("Photo of another object", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		photo := galleryPhotos("85434230werhuhi123912304")[1]
+
+		objRepo.On("GetObjectById", ctx, "231934sadas9123deqw").
+			Return(domain.Object{Id: "231934sadas9123deqw"}, nil)
+		photoRepo.On("GetPhotoById", ctx, photo.Id).Return(photo, nil)
+
+		_, _, err := uc.GetObjectPhotoContent(ctx, "231934sadas9123deqw", photo.Id, domain.PhotoSizeOriginal)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		photoStore.AssertNotCalled(t, "Get")
+	})
+
+	t.Run("Object in the trash", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		photo := galleryPhotos(objectId)[1]
+
+		// objects in the trash are not found by the repository
+		objRepo.On("GetObjectById", ctx, objectId).Return(nil, domain.ErrNotFound)
+
+		_, _, err := uc.GetObjectPhotoContent(ctx, objectId, photo.Id, domain.PhotoSizeOriginal)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		photoRepo.AssertNotCalled(t, "GetPhotoById")
+		photoStore.AssertNotCalled(t, "Get")
+	})
+}
+
+func TestGetObjectCoverContent(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+		photos := galleryPhotos(objectId)
+
+		content := mocks.NewPhotoContent("photo")
+		info := domain.PhotoInfo{Key: "12312sadas123sad.jpg", Size: 5, ContentType: "image/jpeg"}
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{objectId}).Return(photos, nil)
+		photoStore.On("Get", ctx, "12312sadas123sad.jpg").Return(content, info, nil)
+
+		gotContent, gotInfo, err := uc.GetObjectCoverContent(ctx, objectId, domain.PhotoSizeOriginal)
+
+		assert.NoError(t, err)
+		assert.Equal(t, content, gotContent)
+		assert.Equal(t, info, gotInfo)
+	})
+
+	t.Run("Object has no photos", func(t *testing.T) {
+		objRepo := mocks.NewObjectRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		processor := mocks.NewPhotoProcessorMock()
+		transactor := mocks.NewInMemoryTransactor()
+		generator := mocks.NewMockGenerator()
+		uc := NewPhotoUsecase(objRepo, photoRepo, photoStore, processor, transactor, generator)
+
+		ctx := context.Background()
+		objectId := "231934sadas9123deqw"
+
+		objRepo.On("GetObjectById", ctx, objectId).Return(domain.Object{Id: objectId}, nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, []string{objectId}).Return([]domain.Photo{}, nil)
+
+		_, _, err := uc.GetObjectCoverContent(ctx, objectId, domain.PhotoSizeThumb)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		photoStore.AssertNotCalled(t, "Get")
+	})
+}
@@ -0,0 +1,88 @@
+package photogc
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+)
+
+type PhotoGCUsecase struct {
+	photoRepo   PhotoRepository
+	photoStore  PhotoStore
+	gracePeriod time.Duration
+}
+
+type PhotoRepository interface {
+	GetPhotoKeys(ctx context.Context) ([]string, error)
+}
+
+type PhotoStore interface {
+	List(ctx context.Context) ([]domain.PhotoInfo, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewPhotoGCUsecase creates the collector that keeps photos younger than grace period,
+// so photos of uploads that are still in progress are not taken for orphaned ones.
+func NewPhotoGCUsecase(photoRepo PhotoRepository, photoStore PhotoStore, gracePeriod time.Duration) *PhotoGCUsecase {
+	return &PhotoGCUsecase{
+		photoRepo:   photoRepo,
+		photoStore:  photoStore,
+		gracePeriod: gracePeriod,
+	}
+}
+
+// CollectOrphanedPhotos deletes photos of the photo store that no photo of any object refers to.
+func (uc *PhotoGCUsecase) CollectOrphanedPhotos(
+	ctx context.Context,
+	dryRun bool,
+) (domain.OrphanedPhotosReport, error) {
+	report := domain.OrphanedPhotosReport{DryRun: dryRun}
+
+	// photos are listed before references are read, so a photo put after the listing is never
+	// considered, and a photo put before it is referenced already or is within grace period
+	stored, err := uc.photoStore.List(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to list stored photos - %w", err)
+	}
+
+	keys, err := uc.photoRepo.GetPhotoKeys(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to get photo keys - %w", err)
+	}
+
+	// stores keep photos by base names of keys, keys of photos migrated
+	// from earlier versions are full paths
+	referenced := make(map[string]bool, len(keys)*len(domain.ProvidedPhotoSizes))
+	for _, key := range keys {
+		for _, size := range domain.ProvidedPhotoSizes {
+			referenced[path.Base(domain.PhotoVariantKey(key, size))] = true
+		}
+	}
+
+	threshold := time.Now().Add(-uc.gracePeriod)
+
+	report.Scanned = len(stored)
+	for _, photo := range stored {
+		if referenced[path.Base(photo.Key)] || photo.ModifiedAt.After(threshold) {
+			continue
+		}
+
+		report.Orphaned = append(report.Orphaned, photo)
+
+		if dryRun {
+			continue
+		}
+
+		if err := uc.photoStore.Delete(ctx, photo.Key); err != nil {
+			return report, fmt.Errorf("failed to delete orphaned photo - %w", err)
+		}
+
+		report.Deleted++
+		report.ReclaimedBytes += photo.Size
+	}
+
+	return report, nil
+}
@@ -0,0 +1,85 @@
+package photogc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/Unlites/comparison_center/backend/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectOrphanedPhotos(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour)
+
+	stored := []domain.PhotoInfo{
+		{Key: "12312sadas123sad.jpg", Size: 300, ModifiedAt: old},
+		{Key: "12312sadas123sad_thumb.jpg", Size: 10, ModifiedAt: old},
+		{Key: "12312sadas123sad_medium.jpg", Size: 100, ModifiedAt: old},
+		{Key: "4324123sfnjsadn1239213.jpg", Size: 500, ModifiedAt: old},
+		{Key: "65765fdgdf567fdg.png", Size: 400, ModifiedAt: old},
+		{Key: "65765fdgdf567fdg_thumb.png", Size: 20, ModifiedAt: old},
+		{Key: "98789hjkhj789hjk.png", Size: 700, ModifiedAt: time.Now().Add(-time.Minute)},
+	}
+
+	keys := []string{"12312sadas123sad.jpg", "/app/photos/4324123sfnjsadn1239213.jpg"}
+
+	t.Run("Success", func(t *testing.T) {
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		uc := NewPhotoGCUsecase(photoRepo, photoStore, 24*time.Hour)
+
+		ctx := context.Background()
+
+		photoStore.On("List", ctx).Return(stored, nil)
+		photoRepo.On("GetPhotoKeys", ctx).Return(keys, nil)
+		photoStore.On("Delete", ctx, "65765fdgdf567fdg.png").Return(nil)
+		photoStore.On("Delete", ctx, "65765fdgdf567fdg_thumb.png").Return(nil)
+
+		report, err := uc.CollectOrphanedPhotos(ctx, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, report.Scanned)
+		assert.Equal(t, []domain.PhotoInfo{stored[4], stored[5]}, report.Orphaned)
+		assert.Equal(t, 2, report.Deleted)
+		assert.Equal(t, int64(420), report.ReclaimedBytes)
+		photoStore.AssertExpectations(t)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		uc := NewPhotoGCUsecase(photoRepo, photoStore, 24*time.Hour)
+
+		ctx := context.Background()
+
+		photoStore.On("List", ctx).Return(stored, nil)
+		photoRepo.On("GetPhotoKeys", ctx).Return(keys, nil)
+
+		report, err := uc.CollectOrphanedPhotos(ctx, true)
+
+		assert.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Len(t, report.Orphaned, 2)
+		assert.Zero(t, report.Deleted)
+		assert.Zero(t, report.ReclaimedBytes)
+		photoStore.AssertNotCalled(t, "Delete")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		uc := NewPhotoGCUsecase(photoRepo, photoStore, 24*time.Hour)
+
+		ctx := context.Background()
+
+		photoStore.On("List", ctx).Return(stored, nil)
+		photoRepo.On("GetPhotoKeys", ctx).Return(nil, assert.AnError)
+
+		_, err := uc.CollectOrphanedPhotos(ctx, false)
+
+		assert.Error(t, err)
+		photoStore.AssertNotCalled(t, "Delete")
+	})
+}
@@ -0,0 +1,278 @@
+package scoring
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+)
+
+type ScoringUsecase struct {
+	comparisonRepo ComparisonRepository
+	objRepo        ObjectRepository
+	custOptObjRepo ObjectCustomOptionRepository
+	custOptRepo    CustomOptionRepository
+}
+
+type ComparisonRepository interface {
+	GetComparisonById(ctx context.Context, id string) (domain.Comparison, error)
+}
+
+type ObjectRepository interface {
+	GetObjectsByComparisonId(ctx context.Context, comparisonId string) ([]domain.Object, error)
+}
+
+type ObjectCustomOptionRepository interface {
+	GetObjectCustomOptionsByObjectIds(ctx context.Context, objectIds []string) ([]domain.ObjectCustomOption, error)
+}
+
+type CustomOptionRepository interface {
+	GetCustomOptionsByIds(ctx context.Context, ids []string) ([]domain.CustomOption, error)
+}
+
+func NewScoringUsecase(
+	comparisonRepo ComparisonRepository,
+	objRepo ObjectRepository,
+	custOptObjRepo ObjectCustomOptionRepository,
+	custOptRepo CustomOptionRepository,
+) *ScoringUsecase {
+	return &ScoringUsecase{
+		comparisonRepo: comparisonRepo,
+		objRepo:        objRepo,
+		custOptObjRepo: custOptObjRepo,
+		custOptRepo:    custOptRepo,
+	}
+}
+
+// GetComparisonScores normalises custom option values of every object of the comparison
+// to [0, 1] range and combines them into weighted score from 0 to 100. Objects are
+// returned ordered by rank, objects with equal scores share the same rank.
+func (uc *ScoringUsecase) GetComparisonScores(
+	ctx context.Context,
+	comparisonId string,
+) ([]domain.ObjectScore, error) {
+	comparison, err := uc.comparisonRepo.GetComparisonById(ctx, comparisonId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comparison - %w", err)
+	}
+
+	objects, err := uc.objRepo.GetObjectsByComparisonId(ctx, comparison.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get objects - %w", err)
+	}
+
+	if len(objects) != 0 {
+		objectIds := make([]string, len(objects))
+		for i, obj := range objects {
+			objectIds[i] = obj.Id
+		}
+
+		options, err := uc.custOptObjRepo.GetObjectCustomOptionsByObjectIds(ctx, objectIds)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get object custom options - %w", err)
+		}
+
+		objectIdx := make(map[string]int, len(objects))
+		for i, obj := range objects {
+			objectIdx[obj.Id] = i
+		}
+
+		for _, option := range options {
+			if idx, ok := objectIdx[option.ObjectId]; ok {
+				objects[idx].ObjectCustomOptions = append(objects[idx].ObjectCustomOptions, option)
+			}
+		}
+	}
+
+	customOptions := make([]domain.CustomOption, 0)
+	if len(comparison.CustomOptionIds) != 0 {
+		customOptions, err = uc.custOptRepo.GetCustomOptionsByIds(ctx, comparison.CustomOptionIds)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get custom options - %w", err)
+		}
+	}
+
+	return calculateScores(comparison, customOptions, objects), nil
+}
+
+func calculateScores(
+	comparison domain.Comparison,
+	customOptions []domain.CustomOption,
+	objects []domain.Object,
+) []domain.ObjectScore {
+	scores := make([]domain.ObjectScore, len(objects))
+	for i, obj := range objects {
+		scores[i] = domain.ObjectScore{
+			ObjectId:     obj.Id,
+			Name:         obj.Name,
+			OptionScores: make([]domain.OptionScore, 0, len(comparison.CustomOptionIds)),
+		}
+	}
+
+	var totalWeight float64
+	weightedSums := make([]float64, len(objects))
+
+	for _, customOptionId := range comparison.CustomOptionIds {
+		idx := slices.IndexFunc(customOptions, func(co domain.CustomOption) bool {
+			return co.Id == customOptionId
+		})
+		if idx == -1 || !isScorable(customOptions[idx].Type) {
+			continue
+		}
+
+		weight := comparison.WeightOf(customOptionId)
+		if !isFinite(weight.Weight) {
+			continue
+		}
+
+		normalized := normalizeOption(customOptions[idx], weight.Direction, objects)
+		totalWeight += weight.Weight
+
+		for i, obj := range objects {
+			var value string
+			if oco, ok := findObjectCustomOption(obj, customOptionId); ok {
+				value = oco.Value
+			}
+
+			weightedSums[i] += weight.Weight * normalized[i]
+			scores[i].OptionScores = append(scores[i].OptionScores, domain.OptionScore{
+				CustomOptionId: customOptionId,
+				Value:          value,
+				Normalized:     normalized[i],
+				Weight:         weight.Weight,
+			})
+		}
+	}
+
+	if totalWeight > 0 {
+		for i := range scores {
+			scores[i].Score = math.Round(weightedSums[i]/totalWeight*10000) / 100
+		}
+	}
+
+	slices.SortStableFunc(scores, func(a, b domain.ObjectScore) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+
+			return 1
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for i := range scores {
+		scores[i].Rank = i + 1
+		if i > 0 && scores[i].Score == scores[i-1].Score {
+			scores[i].Rank = scores[i-1].Rank
+		}
+	}
+
+	return scores
+}
+
+func isScorable(optionType domain.CustomOptionType) bool {
+	return optionType != domain.CustomOptionTypeText && optionType != ""
+}
+
+// normalizeOption maps values of the option to [0, 1] where 1 is the best value
+// according to the direction. Objects without value or with a value that is not
+// a finite number get 0, so they neither shift the range nor break the scores.
+func normalizeOption(
+	customOption domain.CustomOption,
+	direction domain.ScoreDirection,
+	objects []domain.Object,
+) []float64 {
+	numbers := make([]float64, len(objects))
+	present := make([]bool, len(objects))
+
+	for i, obj := range objects {
+		oco, ok := findObjectCustomOption(obj, customOption.Id)
+		if !ok {
+			continue
+		}
+
+		numbers[i], present[i] = toNumber(customOption, oco.TypedValue)
+	}
+
+	lowest, highest := math.Inf(1), math.Inf(-1)
+	if customOption.Type == domain.CustomOptionTypeBoolean {
+		lowest, highest = 0, 1
+	} else if customOption.Type == domain.CustomOptionTypeEnum {
+		lowest, highest = 0, float64(len(customOption.EnumValues)-1)
+	} else {
+		for i := range numbers {
+			if present[i] {
+				lowest = math.Min(lowest, numbers[i])
+				highest = math.Max(highest, numbers[i])
+			}
+		}
+	}
+
+	normalized := make([]float64, len(objects))
+	for i := range numbers {
+		if !present[i] {
+			continue
+		}
+
+		normalized[i] = 1
+		if highest > lowest {
+			normalized[i] = (numbers[i] - lowest) / (highest - lowest)
+		}
+
+		if direction == domain.ScoreDirectionLowerIsBetter && highest > lowest {
+			normalized[i] = 1 - normalized[i]
+		}
+	}
+
+	return normalized
+}
+
+func toNumber(customOption domain.CustomOption, value any) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, isFinite(v)
+	case time.Time:
+		return float64(v.Unix()), true
+	case bool:
+		if v {
+			return 1, true
+		}
+
+		return 0, true
+	case string:
+		if customOption.Type != domain.CustomOptionTypeEnum {
+			return 0, false
+		}
+
+		idx := slices.Index(customOption.EnumValues, v)
+		if idx == -1 {
+			return 0, false
+		}
+
+		return float64(idx), true
+	}
+
+	return 0, false
+}
+
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+func findObjectCustomOption(object domain.Object, customOptionId string) (domain.ObjectCustomOption, bool) {
+	for _, oco := range object.ObjectCustomOptions {
+		if oco.CustomOptionId == customOptionId {
+			return oco, true
+		}
+	}
+
+	return domain.ObjectCustomOption{}, false
+}
@@ -0,0 +1,204 @@
+package scoring
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/Unlites/comparison_center/backend/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetComparisonScores(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		uc := NewScoringUsecase(comparisonRepo, objRepo, custOptObjRepo, custOptRepo)
+
+		ctx := context.Background()
+		id := "85434230werhuhi123912304"
+
+		returnedComparison := domain.Comparison{
+			Id:              id,
+			Name:            "Cars",
+			CustomOptionIds: []string{"power", "price", "note"},
+			OptionWeights: []domain.OptionWeight{
+				{
+					CustomOptionId: "price",
+					Weight:         3,
+					Direction:      domain.ScoreDirectionLowerIsBetter,
+				},
+			},
+		}
+
+		returnedCustomOptions := []domain.CustomOption{
+			{Id: "power", Name: "Power", Type: domain.CustomOptionTypeInteger},
+			{Id: "price", Name: "Price", Type: domain.CustomOptionTypeMoney, Currency: "USD"},
+			{Id: "note", Name: "Note", Type: domain.CustomOptionTypeText},
+		}
+
+		returnedObjects := []domain.Object{
+			{Id: "bmw", Name: "BMW X5"},
+			{Id: "audi", Name: "Audi Q7"},
+			{Id: "lada", Name: "Lada Niva"},
+		}
+
+		comparisonRepo.On("GetComparisonById", ctx, id).Return(returnedComparison, nil)
+		objRepo.On("GetObjectsByComparisonId", ctx, id).Return(returnedObjects, nil)
+		custOptRepo.On("GetCustomOptionsByIds", ctx, returnedComparison.CustomOptionIds).
+			Return(returnedCustomOptions, nil)
+		custOptObjRepo.On("GetObjectCustomOptionsByObjectIds", ctx, []string{"bmw", "audi", "lada"}).
+			Return([]domain.ObjectCustomOption{
+				{ObjectId: "bmw", CustomOptionId: "power", Value: "600", TypedValue: int64(600)},
+				{ObjectId: "bmw", CustomOptionId: "price", Value: "90000.00 USD", TypedValue: float64(90000)},
+				{ObjectId: "bmw", CustomOptionId: "note", Value: "fast", TypedValue: "fast"},
+				{ObjectId: "audi", CustomOptionId: "power", Value: "400", TypedValue: int64(400)},
+				{ObjectId: "audi", CustomOptionId: "price", Value: "50000.00 USD", TypedValue: float64(50000)},
+				{ObjectId: "lada", CustomOptionId: "power", Value: "80", TypedValue: int64(80)},
+			}, nil)
+
+		scores, err := uc.GetComparisonScores(ctx, id)
+
+		assert.NoError(t, err)
+		assert.Len(t, scores, 3)
+
+		assert.Equal(t, "audi", scores[0].ObjectId)
+		assert.Equal(t, 1, scores[0].Rank)
+		assert.Equal(t, 90.38, scores[0].Score)
+
+		assert.Equal(t, "bmw", scores[1].ObjectId)
+		assert.Equal(t, 2, scores[1].Rank)
+		assert.Equal(t, 25.0, scores[1].Score)
+
+		assert.Equal(t, "lada", scores[2].ObjectId)
+		assert.Equal(t, 3, scores[2].Rank)
+		assert.Equal(t, 0.0, scores[2].Score)
+
+		assert.Len(t, scores[0].OptionScores, 2)
+
+		comparisonRepo.AssertExpectations(t)
+		objRepo.AssertExpectations(t)
+		custOptObjRepo.AssertExpectations(t)
+		custOptRepo.AssertExpectations(t)
+	})
+
+	t.Run("Equal scores share rank", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		uc := NewScoringUsecase(comparisonRepo, objRepo, custOptObjRepo, custOptRepo)
+
+		ctx := context.Background()
+		id := "85434230werhuhi123912304"
+
+		returnedComparison := domain.Comparison{
+			Id:              id,
+			Name:            "Cars",
+			CustomOptionIds: []string{"awd"},
+		}
+
+		returnedObjects := []domain.Object{
+			{Id: "bmw", Name: "BMW X5"},
+			{Id: "audi", Name: "Audi Q7"},
+		}
+
+		comparisonRepo.On("GetComparisonById", ctx, id).Return(returnedComparison, nil)
+		objRepo.On("GetObjectsByComparisonId", ctx, id).Return(returnedObjects, nil)
+		custOptRepo.On("GetCustomOptionsByIds", ctx, returnedComparison.CustomOptionIds).
+			Return([]domain.CustomOption{{Id: "awd", Name: "AWD", Type: domain.CustomOptionTypeBoolean}}, nil)
+		custOptObjRepo.On("GetObjectCustomOptionsByObjectIds", ctx, []string{"bmw", "audi"}).
+			Return([]domain.ObjectCustomOption{
+				{ObjectId: "bmw", CustomOptionId: "awd", TypedValue: true},
+				{ObjectId: "audi", CustomOptionId: "awd", TypedValue: true},
+			}, nil)
+
+		scores, err := uc.GetComparisonScores(ctx, id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, scores[0].Rank)
+		assert.Equal(t, 1, scores[1].Rank)
+		assert.Equal(t, 100.0, scores[0].Score)
+	})
+
+	t.Run("Non-finite values are treated as missing", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		uc := NewScoringUsecase(comparisonRepo, objRepo, custOptObjRepo, custOptRepo)
+
+		ctx := context.Background()
+		id := "85434230werhuhi123912304"
+
+		returnedComparison := domain.Comparison{
+			Id:              id,
+			Name:            "Cars",
+			CustomOptionIds: []string{"consumption"},
+		}
+
+		returnedObjects := []domain.Object{
+			{Id: "bmw", Name: "BMW X5"},
+			{Id: "audi", Name: "Audi Q7"},
+			{Id: "lada", Name: "Lada Niva"},
+			{Id: "uaz", Name: "UAZ Patriot"},
+		}
+
+		comparisonRepo.On("GetComparisonById", ctx, id).Return(returnedComparison, nil)
+		objRepo.On("GetObjectsByComparisonId", ctx, id).Return(returnedObjects, nil)
+		custOptRepo.On("GetCustomOptionsByIds", ctx, returnedComparison.CustomOptionIds).
+			Return([]domain.CustomOption{
+				{Id: "consumption", Name: "Consumption", Type: domain.CustomOptionTypeDecimal},
+			}, nil)
+		custOptObjRepo.On("GetObjectCustomOptionsByObjectIds", ctx, []string{"bmw", "audi", "lada", "uaz"}).
+			Return([]domain.ObjectCustomOption{
+				{ObjectId: "bmw", CustomOptionId: "consumption", TypedValue: float64(12)},
+				{ObjectId: "audi", CustomOptionId: "consumption", TypedValue: float64(10)},
+				{ObjectId: "lada", CustomOptionId: "consumption", TypedValue: math.Inf(1)},
+				{ObjectId: "uaz", CustomOptionId: "consumption", TypedValue: math.Inf(-1)},
+			}, nil)
+
+		scores, err := uc.GetComparisonScores(ctx, id)
+
+		assert.NoError(t, err)
+		assert.Len(t, scores, 4)
+
+		assert.Equal(t, "bmw", scores[0].ObjectId)
+		assert.Equal(t, 100.0, scores[0].Score)
+		assert.Equal(t, "audi", scores[1].ObjectId)
+		assert.Equal(t, 0.0, scores[1].Score)
+
+		for _, score := range scores {
+			assert.False(t, math.IsNaN(score.Score))
+			for _, optionScore := range score.OptionScores {
+				assert.False(t, math.IsNaN(optionScore.Normalized))
+			}
+		}
+
+		_, err = json.Marshal(scores)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		uc := NewScoringUsecase(comparisonRepo, objRepo, custOptObjRepo, custOptRepo)
+
+		ctx := context.Background()
+		id := "85434230werhuhi123912304"
+
+		comparisonRepo.On("GetComparisonById", ctx, id).Return(nil, domain.ErrNotFound)
+
+		scores, err := uc.GetComparisonScores(ctx, id)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, scores)
+		objRepo.AssertNotCalled(t, "GetObjectsByComparisonId")
+	})
+}
@@ -0,0 +1,192 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"golang.org/x/sync/errgroup"
+)
+
+type SearchUsecase struct {
+	comparisonRepo ComparisonRepository
+	objRepo        ObjectRepository
+	custOptObjRepo ObjectCustomOptionRepository
+	custOptRepo    CustomOptionRepository
+	timeout        time.Duration
+}
+
+type ComparisonRepository interface {
+	GetComparisons(ctx context.Context, filter domain.ComparisonFilter) ([]domain.Comparison, domain.PageInfo, error)
+	GetComparisonsByIds(ctx context.Context, ids []string) ([]domain.Comparison, error)
+}
+
+type ObjectRepository interface {
+	GetObjects(ctx context.Context, filter domain.ObjectFilter) ([]domain.Object, domain.PageInfo, error)
+}
+
+type ObjectCustomOptionRepository interface {
+	GetObjectCustomOptionsByObjectIds(ctx context.Context, objectIds []string) ([]domain.ObjectCustomOption, error)
+}
+
+type CustomOptionRepository interface {
+	GetCustomOptions(ctx context.Context, filter domain.CustomOptionFilter) ([]domain.CustomOption, domain.PageInfo, error)
+}
+
+func NewSearchUsecase(
+	comparisonRepo ComparisonRepository,
+	objRepo ObjectRepository,
+	custOptObjRepo ObjectCustomOptionRepository,
+	custOptRepo CustomOptionRepository,
+	timeout time.Duration,
+) *SearchUsecase {
+	return &SearchUsecase{
+		comparisonRepo: comparisonRepo,
+		objRepo:        objRepo,
+		custOptObjRepo: custOptObjRepo,
+		custOptRepo:    custOptRepo,
+		timeout:        timeout,
+	}
+}
+
+// Search looks for the query in names of comparisons and custom options and in the text
+// of objects at the same time. The search stops with the context and fails with
+// domain.ErrTimeout when it takes longer than the timeout of the usecase.
+func (uc *SearchUsecase) Search(ctx context.Context, filter domain.SearchFilter) (domain.SearchResults, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	var results domain.SearchResults
+
+	// every group is written by its own goroutine only
+	g, groupCtx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		var err error
+		results.Comparisons, results.ComparisonsTotal, err = uc.searchComparisons(groupCtx, filter)
+		return err
+	})
+
+	g.Go(func() error {
+		var err error
+		results.Objects, results.ObjectsTotal, err = uc.searchObjects(groupCtx, filter)
+		return err
+	})
+
+	g.Go(func() error {
+		var err error
+		results.CustomOptions, results.CustomOptionsTotal, err = uc.searchCustomOptions(groupCtx, filter)
+		return err
+	})
+
+	if err := g.Wait(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return domain.SearchResults{}, fmt.Errorf("search took longer than %s - %w", uc.timeout, domain.ErrTimeout)
+		}
+
+		return domain.SearchResults{}, err
+	}
+
+	return results, nil
+}
+
+func (uc *SearchUsecase) searchComparisons(
+	ctx context.Context,
+	filter domain.SearchFilter,
+) ([]domain.Comparison, int64, error) {
+	comparisonFilter, err := domain.NewComparisonFilter(
+		filter.Limit, 0,
+		"", "name", filter.Query,
+		time.Time{}, time.Time{},
+		"", "", "",
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create comparison filter - %w", err)
+	}
+
+	comparisons, pageInfo, err := uc.comparisonRepo.GetComparisons(ctx, comparisonFilter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search comparisons - %w", err)
+	}
+
+	return comparisons, pageInfo.Total, nil
+}
+
+// searchObjects finds objects by the text index, most relevant first, with values of
+// their custom options to highlight the matching field and comparisons they belong to.
+func (uc *SearchUsecase) searchObjects(
+	ctx context.Context,
+	filter domain.SearchFilter,
+) ([]domain.ObjectSearchResult, int64, error) {
+	objectFilter, err := domain.NewObjectFilter(filter.Limit, 0, "", "", "", filter.Query, "", nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create object filter - %w", err)
+	}
+
+	objects, pageInfo, err := uc.objRepo.GetObjects(ctx, objectFilter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search objects - %w", err)
+	}
+
+	if len(objects) == 0 {
+		return []domain.ObjectSearchResult{}, pageInfo.Total, nil
+	}
+
+	objectIds := make([]string, len(objects))
+	comparisonIds := make([]string, 0, len(objects))
+	for i, obj := range objects {
+		objectIds[i] = obj.Id
+		comparisonIds = append(comparisonIds, obj.ComparisonId)
+	}
+
+	objCustomOptions, err := uc.custOptObjRepo.GetObjectCustomOptionsByObjectIds(ctx, objectIds)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get object custom options - %w", err)
+	}
+
+	comparisons, err := uc.comparisonRepo.GetComparisonsByIds(ctx, comparisonIds)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get comparisons of objects - %w", err)
+	}
+
+	optionsByObject := make(map[string][]domain.ObjectCustomOption, len(objects))
+	for _, oco := range objCustomOptions {
+		optionsByObject[oco.ObjectId] = append(optionsByObject[oco.ObjectId], oco)
+	}
+
+	comparisonsById := make(map[string]domain.Comparison, len(comparisons))
+	for _, c := range comparisons {
+		comparisonsById[c.Id] = c
+	}
+
+	terms := domain.SearchTerms(filter.Query)
+
+	results := make([]domain.ObjectSearchResult, len(objects))
+	for i, obj := range objects {
+		obj.ObjectCustomOptions = optionsByObject[obj.Id]
+		obj.Highlight, _ = obj.HighlightTerms(terms)
+
+		results[i] = domain.ObjectSearchResult{Object: obj, Comparison: comparisonsById[obj.ComparisonId]}
+	}
+
+	return results, pageInfo.Total, nil
+}
+
+func (uc *SearchUsecase) searchCustomOptions(
+	ctx context.Context,
+	filter domain.SearchFilter,
+) ([]domain.CustomOption, int64, error) {
+	customOptionFilter, err := domain.NewCustomOptionFilter(filter.Limit, 0, "", "name", filter.Query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create custom option filter - %w", err)
+	}
+
+	customOptions, pageInfo, err := uc.custOptRepo.GetCustomOptions(ctx, customOptionFilter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search custom options - %w", err)
+	}
+
+	return customOptions, pageInfo.Total, nil
+}
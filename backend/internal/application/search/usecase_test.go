@@ -0,0 +1,120 @@
+package search
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/Unlites/comparison_center/backend/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSearch(t *testing.T) {
+	comparisonFilter := domain.ComparisonFilter{
+		Limit:   5,
+		OrderBy: []domain.SortKey{{Field: "name"}},
+		Name:    "bmw",
+	}
+	objectFilter := domain.ObjectFilter{
+		Limit:   5,
+		Query:   "bmw",
+		OrderBy: []domain.SortKey{{Field: domain.ScoreOrdering, Desc: true}},
+	}
+	customOptionFilter := domain.CustomOptionFilter{
+		Limit:   5,
+		OrderBy: []domain.SortKey{{Field: "name"}},
+		Name:    "bmw",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		uc := NewSearchUsecase(comparisonRepo, objRepo, custOptObjRepo, custOptRepo, time.Second)
+
+		comparisonRepo.On("GetComparisons", mock.Anything, comparisonFilter).
+			Return([]domain.Comparison{{Id: "85434230werhuhi123912304", Name: "BMW cars"}}, domain.PageInfo{Total: 1}, nil)
+		objRepo.On("GetObjects", mock.Anything, objectFilter).
+			Return([]domain.Object{
+				{Id: "231934sadas9123deqw", Name: "BMW X5", ComparisonId: "95434230werhuhi123912304", Score: 1.1},
+			}, domain.PageInfo{Total: 7}, nil)
+		custOptObjRepo.On("GetObjectCustomOptionsByObjectIds", mock.Anything, []string{"231934sadas9123deqw"}).
+			Return([]domain.ObjectCustomOption{}, nil)
+		comparisonRepo.On("GetComparisonsByIds", mock.Anything, []string{"95434230werhuhi123912304"}).
+			Return([]domain.Comparison{{Id: "95434230werhuhi123912304", Name: "SUVs"}}, nil)
+		custOptRepo.On("GetCustomOptions", mock.Anything, customOptionFilter).
+			Return([]domain.CustomOption{}, domain.PageInfo{}, nil)
+
+		filter, err := domain.NewSearchFilter(" bmw ", 5)
+		assert.NoError(t, err)
+
+		results, err := uc.Search(context.Background(), filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Comparison{{Id: "85434230werhuhi123912304", Name: "BMW cars"}}, results.Comparisons)
+		assert.Equal(t, int64(1), results.ComparisonsTotal)
+		assert.Equal(t, []domain.ObjectSearchResult{{
+			Object: domain.Object{
+				Id:           "231934sadas9123deqw",
+				Name:         "BMW X5",
+				ComparisonId: "95434230werhuhi123912304",
+				Score:        1.1,
+				Highlight: domain.Highlight{
+					Field: "name",
+					Texts: []domain.HighlightText{{Value: "BMW", Hit: true}, {Value: " X5"}},
+				},
+			},
+			Comparison: domain.Comparison{Id: "95434230werhuhi123912304", Name: "SUVs"},
+		}}, results.Objects)
+		assert.Equal(t, int64(7), results.ObjectsTotal)
+		assert.Empty(t, results.CustomOptions)
+		comparisonRepo.AssertExpectations(t)
+		objRepo.AssertExpectations(t)
+		custOptRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error of one group", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		uc := NewSearchUsecase(comparisonRepo, objRepo, custOptObjRepo, custOptRepo, time.Second)
+
+		comparisonRepo.On("GetComparisons", mock.Anything, comparisonFilter).
+			Return([]domain.Comparison{}, domain.PageInfo{}, nil)
+		objRepo.On("GetObjects", mock.Anything, objectFilter).Return(nil, nil, assert.AnError)
+		custOptRepo.On("GetCustomOptions", mock.Anything, customOptionFilter).
+			Return([]domain.CustomOption{}, domain.PageInfo{}, nil)
+
+		_, err := uc.Search(context.Background(), domain.SearchFilter{Query: "bmw", Limit: 5})
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NotErrorIs(t, err, domain.ErrTimeout)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		custOptRepo := mocks.NewCustomOptionRepositoryMock()
+		uc := NewSearchUsecase(comparisonRepo, objRepo, custOptObjRepo, custOptRepo, 10*time.Millisecond)
+
+		comparisonRepo.On("GetComparisons", mock.Anything, comparisonFilter).
+			Return([]domain.Comparison{}, domain.PageInfo{}, nil)
+		objRepo.On("GetObjects", mock.Anything, objectFilter).
+			Run(func(args mock.Arguments) {
+				<-args.Get(0).(context.Context).Done()
+			}).
+			Return(nil, nil, context.DeadlineExceeded)
+		custOptRepo.On("GetCustomOptions", mock.Anything, customOptionFilter).
+			Return([]domain.CustomOption{}, domain.PageInfo{}, nil)
+
+		_, err := uc.Search(context.Background(), domain.SearchFilter{Query: "bmw", Limit: 5})
+
+		assert.ErrorIs(t, err, domain.ErrTimeout)
+		custOptObjRepo.AssertNotCalled(t, "GetObjectCustomOptionsByObjectIds", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,30 @@
+package tag
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+)
+
+type TagUsecase struct {
+	comparisonRepo ComparisonRepository
+}
+
+type ComparisonRepository interface {
+	GetTagUsages(ctx context.Context) ([]domain.TagUsage, error)
+}
+
+func NewTagUsecase(comparisonRepo ComparisonRepository) *TagUsecase {
+	return &TagUsecase{comparisonRepo: comparisonRepo}
+}
+
+// GetTags returns tags in use with the number of comparisons tagged with each of them.
+func (uc *TagUsecase) GetTags(ctx context.Context) ([]domain.TagUsage, error) {
+	tags, err := uc.comparisonRepo.GetTagUsages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags - %w", err)
+	}
+
+	return tags, nil
+}
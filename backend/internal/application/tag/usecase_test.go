@@ -0,0 +1,40 @@
+package tag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/Unlites/comparison_center/backend/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTags(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		uc := NewTagUsecase(comparisonRepo)
+
+		ctx := context.Background()
+
+		expected := []domain.TagUsage{{Name: "travel", Count: 3}, {Name: "family", Count: 1}}
+		comparisonRepo.On("GetTagUsages", ctx).Return(expected, nil)
+
+		tags, err := uc.GetTags(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, tags)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		uc := NewTagUsecase(comparisonRepo)
+
+		ctx := context.Background()
+
+		comparisonRepo.On("GetTagUsages", ctx).Return(nil, assert.AnError)
+
+		_, err := uc.GetTags(ctx)
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
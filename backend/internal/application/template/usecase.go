@@ -0,0 +1,162 @@
+package template
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+)
+
+type TemplateUsecase struct {
+	repo      TemplateRepository
+	generator IdGenerator
+}
+
+type TemplateRepository interface {
+	GetTemplates(ctx context.Context, filter domain.TemplateFilter) ([]domain.Template, domain.PageInfo, error)
+	GetTemplateById(ctx context.Context, id string) (domain.Template, error)
+	CreateTemplate(ctx context.Context, template domain.Template) error
+	UpdateTemplate(ctx context.Context, template domain.Template) error
+	SaveBuiltInTemplate(ctx context.Context, template domain.Template) error
+	DeleteTemplate(ctx context.Context, id string) error
+}
+
+type IdGenerator interface {
+	GenerateId() string
+}
+
+func NewTemplateUsecase(repo TemplateRepository, generator IdGenerator) *TemplateUsecase {
+	return &TemplateUsecase{
+		repo:      repo,
+		generator: generator,
+	}
+}
+
+func (uc *TemplateUsecase) GetTemplates(
+	ctx context.Context,
+	filter domain.TemplateFilter,
+) ([]domain.Template, domain.PageInfo, error) {
+	templates, pageInfo, err := uc.repo.GetTemplates(ctx, filter)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("failed to get templates - %w", err)
+	}
+
+	return templates, pageInfo, nil
+}
+
+func (uc *TemplateUsecase) GetTemplateById(ctx context.Context, id string) (domain.Template, error) {
+	template, err := uc.repo.GetTemplateById(ctx, id)
+	if err != nil {
+		return domain.Template{}, fmt.Errorf("failed to get template - %w", err)
+	}
+
+	return template, nil
+}
+
+func (uc *TemplateUsecase) CreateTemplate(ctx context.Context, template domain.Template) (string, error) {
+	template = withDefaults(template)
+
+	if err := template.Validate(); err != nil {
+		return "", fmt.Errorf("invalid template - %w", err)
+	}
+
+	template.Id = uc.generator.GenerateId()
+	template.BuiltIn = false
+	template.CreatedAt = time.Now()
+
+	if err := uc.repo.CreateTemplate(ctx, template); err != nil {
+		return "", fmt.Errorf("failed to create template - %w", err)
+	}
+
+	return template.Id, nil
+}
+
+// UpdateTemplate replaces name, description and options of the template.
+// Built-in templates can not be updated.
+func (uc *TemplateUsecase) UpdateTemplate(ctx context.Context, id string, template domain.Template) error {
+	existingTemplate, err := uc.repo.GetTemplateById(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get existing template - %w", err)
+	}
+
+	if existingTemplate.BuiltIn {
+		return fmt.Errorf("built-in template can not be updated - %w", domain.ErrConflict)
+	}
+
+	template = withDefaults(template)
+
+	if err := template.Validate(); err != nil {
+		return fmt.Errorf("invalid template - %w", err)
+	}
+
+	template.Id = existingTemplate.Id
+
+	if err := uc.repo.UpdateTemplate(ctx, template); err != nil {
+		return fmt.Errorf("failed to update template - %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTemplate deletes the template, comparisons created from it are kept as is.
+// Built-in templates can not be deleted.
+func (uc *TemplateUsecase) DeleteTemplate(ctx context.Context, id string) error {
+	template, err := uc.repo.GetTemplateById(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get template - %w", err)
+	}
+
+	if template.BuiltIn {
+		return fmt.Errorf("built-in template can not be deleted - %w", domain.ErrConflict)
+	}
+
+	if err := uc.repo.DeleteTemplate(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete template - %w", err)
+	}
+
+	return nil
+}
+
+// SeedBuiltInTemplates stores templates shipped with the application, replacing
+// the ones stored by previous releases. Custom options of the templates are not
+// created until a comparison is created from the template.
+func (uc *TemplateUsecase) SeedBuiltInTemplates(ctx context.Context, templates []domain.Template) error {
+	for _, template := range templates {
+		template = withDefaults(template)
+
+		if err := template.Validate(); err != nil {
+			return fmt.Errorf("invalid built-in template '%s' - %w", template.Name, err)
+		}
+
+		template.BuiltIn = true
+		template.CreatedAt = time.Now()
+
+		if err := uc.repo.SaveBuiltInTemplate(ctx, template); err != nil {
+			return fmt.Errorf("failed to save built-in template '%s' - %w", template.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// withDefaults gives the template with text type and higher is better direction
+// set to options without them.
+func withDefaults(template domain.Template) domain.Template {
+	options := make([]domain.TemplateOption, len(template.Options))
+	for i, o := range template.Options {
+		if o.Type == "" {
+			o.Type = domain.CustomOptionTypeText
+		}
+
+		if o.Direction == "" {
+			o.Direction = domain.ScoreDirectionHigherIsBetter
+		}
+
+		options[i] = o
+	}
+
+	template.Options = options
+
+	return template
+}
@@ -0,0 +1,272 @@
+package template
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/Unlites/comparison_center/backend/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetTemplates(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := mocks.NewTemplateRepositoryMock()
+		generator := mocks.NewMockGenerator()
+		uc := NewTemplateUsecase(repo, generator)
+
+		ctx := context.Background()
+
+		filter := domain.TemplateFilter{Limit: 2}
+
+		returnedTemplates := []domain.Template{
+			{Id: "190324fdsjfn123213", Name: "Phones", BuiltIn: true},
+			{Id: "303242ngpewrm40231", Name: "Laptops"},
+		}
+		returnedPageInfo := domain.PageInfo{Total: 2}
+
+		repo.On("GetTemplates", ctx, filter).Return(returnedTemplates, returnedPageInfo, nil)
+
+		templates, pageInfo, err := uc.GetTemplates(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, returnedTemplates, templates)
+		assert.Equal(t, returnedPageInfo, pageInfo)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		repo := mocks.NewTemplateRepositoryMock()
+		generator := mocks.NewMockGenerator()
+		uc := NewTemplateUsecase(repo, generator)
+
+		ctx := context.Background()
+
+		filter := domain.TemplateFilter{Limit: 2}
+
+		repo.On("GetTemplates", ctx, filter).Return(nil, nil, assert.AnError)
+
+		templates, _, err := uc.GetTemplates(ctx, filter)
+
+		assert.Error(t, err)
+		assert.Nil(t, templates)
+	})
+}
+
+func TestCreateTemplate(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := mocks.NewTemplateRepositoryMock()
+		generator := mocks.NewMockGenerator()
+		uc := NewTemplateUsecase(repo, generator)
+
+		ctx := context.Background()
+
+		generator.On("GenerateId").Return("190324fdsjfn123213")
+		repo.On("CreateTemplate", ctx, mock.MatchedBy(func(template domain.Template) bool {
+			return template.Id == "190324fdsjfn123213" &&
+				!template.BuiltIn &&
+				!template.CreatedAt.IsZero() &&
+				template.Options[0].Type == domain.CustomOptionTypeText &&
+				template.Options[0].Direction == domain.ScoreDirectionHigherIsBetter &&
+				template.Options[1].Direction == domain.ScoreDirectionLowerIsBetter
+		})).Return(nil)
+
+		id, err := uc.CreateTemplate(ctx, domain.Template{
+			Name: "Laptops",
+			Options: []domain.TemplateOption{
+				{Name: "Model", Weight: 0},
+				{
+					Name:      "Price",
+					Type:      domain.CustomOptionTypeMoney,
+					Currency:  "USD",
+					Weight:    2,
+					Direction: domain.ScoreDirectionLowerIsBetter,
+				},
+			},
+			BuiltIn: true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "190324fdsjfn123213", id)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Duplicate option", func(t *testing.T) {
+		repo := mocks.NewTemplateRepositoryMock()
+		generator := mocks.NewMockGenerator()
+		uc := NewTemplateUsecase(repo, generator)
+
+		ctx := context.Background()
+
+		_, err := uc.CreateTemplate(ctx, domain.Template{
+			Name: "Laptops",
+			Options: []domain.TemplateOption{
+				{Name: "Price", Weight: 1},
+				{Name: "Price", Weight: 2},
+			},
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		repo.AssertNotCalled(t, "CreateTemplate")
+	})
+
+	t.Run("Invalid option", func(t *testing.T) {
+		repo := mocks.NewTemplateRepositoryMock()
+		generator := mocks.NewMockGenerator()
+		uc := NewTemplateUsecase(repo, generator)
+
+		ctx := context.Background()
+
+		_, err := uc.CreateTemplate(ctx, domain.Template{
+			Name:    "Laptops",
+			Options: []domain.TemplateOption{{Name: "Price", Type: domain.CustomOptionTypeMoney}},
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		repo.AssertNotCalled(t, "CreateTemplate")
+	})
+}
+
+func TestUpdateTemplate(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := mocks.NewTemplateRepositoryMock()
+		generator := mocks.NewMockGenerator()
+		uc := NewTemplateUsecase(repo, generator)
+
+		ctx := context.Background()
+
+		repo.On("GetTemplateById", ctx, "190324fdsjfn123213").
+			Return(domain.Template{Id: "190324fdsjfn123213", Name: "Laptops"}, nil)
+		repo.On("UpdateTemplate", ctx, domain.Template{
+			Id:          "190324fdsjfn123213",
+			Name:        "Notebooks",
+			Description: "Portable computers",
+			Options: []domain.TemplateOption{
+				{Name: "Weight", Type: domain.CustomOptionTypeDecimal, Weight: 1, Direction: domain.ScoreDirectionLowerIsBetter},
+			},
+		}).Return(nil)
+
+		err := uc.UpdateTemplate(ctx, "190324fdsjfn123213", domain.Template{
+			Name:        "Notebooks",
+			Description: "Portable computers",
+			Options: []domain.TemplateOption{
+				{Name: "Weight", Type: domain.CustomOptionTypeDecimal, Weight: 1, Direction: domain.ScoreDirectionLowerIsBetter},
+			},
+		})
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Built-in", func(t *testing.T) {
+		repo := mocks.NewTemplateRepositoryMock()
+		generator := mocks.NewMockGenerator()
+		uc := NewTemplateUsecase(repo, generator)
+
+		ctx := context.Background()
+
+		repo.On("GetTemplateById", ctx, "190324fdsjfn123213").
+			Return(domain.Template{Id: "190324fdsjfn123213", Name: "Phones", BuiltIn: true}, nil)
+
+		err := uc.UpdateTemplate(ctx, "190324fdsjfn123213", domain.Template{Name: "My phones"})
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		repo.AssertNotCalled(t, "UpdateTemplate")
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		repo := mocks.NewTemplateRepositoryMock()
+		generator := mocks.NewMockGenerator()
+		uc := NewTemplateUsecase(repo, generator)
+
+		ctx := context.Background()
+
+		repo.On("GetTemplateById", ctx, "190324fdsjfn123213").Return(nil, domain.ErrNotFound)
+
+		err := uc.UpdateTemplate(ctx, "190324fdsjfn123213", domain.Template{Name: "Notebooks"})
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestDeleteTemplate(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := mocks.NewTemplateRepositoryMock()
+		generator := mocks.NewMockGenerator()
+		uc := NewTemplateUsecase(repo, generator)
+
+		ctx := context.Background()
+
+		repo.On("GetTemplateById", ctx, "190324fdsjfn123213").
+			Return(domain.Template{Id: "190324fdsjfn123213", Name: "Laptops"}, nil)
+		repo.On("DeleteTemplate", ctx, "190324fdsjfn123213").Return(nil)
+
+		err := uc.DeleteTemplate(ctx, "190324fdsjfn123213")
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Built-in", func(t *testing.T) {
+		repo := mocks.NewTemplateRepositoryMock()
+		generator := mocks.NewMockGenerator()
+		uc := NewTemplateUsecase(repo, generator)
+
+		ctx := context.Background()
+
+		repo.On("GetTemplateById", ctx, "190324fdsjfn123213").
+			Return(domain.Template{Id: "190324fdsjfn123213", Name: "Phones", BuiltIn: true}, nil)
+
+		err := uc.DeleteTemplate(ctx, "190324fdsjfn123213")
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		repo.AssertNotCalled(t, "DeleteTemplate")
+	})
+}
+
+func TestSeedBuiltInTemplates(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := mocks.NewTemplateRepositoryMock()
+		generator := mocks.NewMockGenerator()
+		uc := NewTemplateUsecase(repo, generator)
+
+		ctx := context.Background()
+
+		repo.On("SaveBuiltInTemplate", ctx, mock.MatchedBy(func(template domain.Template) bool {
+			return template.Id == "0f8d3c8e-4a8b-4c1e-9d3a-6b1f2e7c5a01" &&
+				template.BuiltIn &&
+				template.Options[0].Direction == domain.ScoreDirectionHigherIsBetter
+		})).Return(nil)
+
+		err := uc.SeedBuiltInTemplates(ctx, []domain.Template{
+			{
+				Id:      "0f8d3c8e-4a8b-4c1e-9d3a-6b1f2e7c5a01",
+				Name:    "Phones",
+				Options: []domain.TemplateOption{{Name: "Battery capacity", Type: domain.CustomOptionTypeInteger, Weight: 1}},
+			},
+		})
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid template", func(t *testing.T) {
+		repo := mocks.NewTemplateRepositoryMock()
+		generator := mocks.NewMockGenerator()
+		uc := NewTemplateUsecase(repo, generator)
+
+		ctx := context.Background()
+
+		err := uc.SeedBuiltInTemplates(ctx, []domain.Template{
+			{
+				Id:      "0f8d3c8e-4a8b-4c1e-9d3a-6b1f2e7c5a01",
+				Name:    "Phones",
+				Options: []domain.TemplateOption{{Name: "Price", Weight: -1}},
+			},
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidValue)
+		repo.AssertNotCalled(t, "SaveBuiltInTemplate")
+	})
+}
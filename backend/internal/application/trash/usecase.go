@@ -0,0 +1,213 @@
+package trash
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+)
+
+type TrashUsecase struct {
+	trashRepo      TrashRepository
+	comparisonRepo ComparisonRepository
+	objRepo        ObjectRepository
+	custOptObjRepo ObjectCustomOptionRepository
+	photoRepo      PhotoRepository
+	photoStore     PhotoStore
+	transactor     Transactor
+	retention      time.Duration
+}
+
+type TrashRepository interface {
+	GetTrashItems(ctx context.Context, filter domain.TrashFilter) ([]domain.TrashItem, domain.PageInfo, error)
+}
+
+type ComparisonRepository interface {
+	GetDeletedComparisons(ctx context.Context, deletedBefore time.Time) ([]domain.Comparison, error)
+	PurgeComparison(ctx context.Context, id string) error
+}
+
+type ObjectRepository interface {
+	GetDeletedObjects(ctx context.Context, deletedBefore time.Time) ([]domain.Object, error)
+	GetDeletedObjectsByComparisonId(ctx context.Context, comparisonId string) ([]domain.Object, error)
+	PurgeObjects(ctx context.Context, ids []string) error
+}
+
+type ObjectCustomOptionRepository interface {
+	DeleteObjectCustomOptionsByObjectIds(ctx context.Context, objectIds []string) error
+}
+
+type PhotoRepository interface {
+	GetPhotosByObjectIds(ctx context.Context, objectIds []string) ([]domain.Photo, error)
+	DeletePhotosByObjectIds(ctx context.Context, objectIds []string) error
+}
+
+type PhotoStore interface {
+	Delete(ctx context.Context, path string) error
+}
+
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// NewTrashUsecase creates the usecase that keeps deleted comparisons and objects
+// in the trash for the retention period.
+func NewTrashUsecase(
+	trashRepo TrashRepository,
+	comparisonRepo ComparisonRepository,
+	objRepo ObjectRepository,
+	custOptObjRepo ObjectCustomOptionRepository,
+	photoRepo PhotoRepository,
+	photoStore PhotoStore,
+	transactor Transactor,
+	retention time.Duration,
+) *TrashUsecase {
+	return &TrashUsecase{
+		trashRepo:      trashRepo,
+		comparisonRepo: comparisonRepo,
+		objRepo:        objRepo,
+		custOptObjRepo: custOptObjRepo,
+		photoRepo:      photoRepo,
+		photoStore:     photoStore,
+		transactor:     transactor,
+		retention:      retention,
+	}
+}
+
+// GetTrash returns a page of deleted comparisons and objects, recently deleted ones go first.
+func (uc *TrashUsecase) GetTrash(
+	ctx context.Context,
+	filter domain.TrashFilter,
+) ([]domain.TrashItem, domain.PageInfo, error) {
+	items, pageInfo, err := uc.trashRepo.GetTrashItems(ctx, filter)
+	if err != nil {
+		return nil, domain.PageInfo{}, fmt.Errorf("failed to get trash items - %w", err)
+	}
+
+	return items, pageInfo, nil
+}
+
+// PurgeExpired removes comparisons and objects that have been in the trash longer
+// than the retention period for good, along with custom option values and photos
+// of the objects. Each comparison is removed in its own transaction, so a failure
+// keeps the ones removed before it. Photo files that can not be removed do not fail
+// the run, they are reported as leftovers.
+func (uc *TrashUsecase) PurgeExpired(ctx context.Context) (domain.TrashPurgeReport, error) {
+	var report domain.TrashPurgeReport
+
+	deletedBefore := time.Now().Add(-uc.retention)
+
+	comparisons, err := uc.comparisonRepo.GetDeletedComparisons(ctx, deletedBefore)
+	if err != nil {
+		return report, fmt.Errorf("failed to get deleted comparisons - %w", err)
+	}
+
+	for _, c := range comparisons {
+		var objects []domain.Object
+
+		photos, leftoverKeys, err := uc.purge(ctx, func(ctx context.Context) ([]string, error) {
+			objects, err = uc.objRepo.GetDeletedObjectsByComparisonId(ctx, c.Id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get deleted objects - %w", err)
+			}
+
+			if err := uc.comparisonRepo.PurgeComparison(ctx, c.Id); err != nil {
+				return nil, fmt.Errorf("failed to purge comparison - %w", err)
+			}
+
+			return objectIds(objects), nil
+		})
+		if err != nil {
+			return report, err
+		}
+
+		report.Comparisons++
+		report.Objects += len(objects)
+		report.Photos += photos
+		report.LeftoverPhotoKeys = append(report.LeftoverPhotoKeys, leftoverKeys...)
+	}
+
+	objects, err := uc.objRepo.GetDeletedObjects(ctx, deletedBefore)
+	if err != nil {
+		return report, fmt.Errorf("failed to get deleted objects - %w", err)
+	}
+
+	if len(objects) == 0 {
+		return report, nil
+	}
+
+	photos, leftoverKeys, err := uc.purge(ctx, func(ctx context.Context) ([]string, error) {
+		return objectIds(objects), nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	report.Objects += len(objects)
+	report.Photos += photos
+	report.LeftoverPhotoKeys = append(report.LeftoverPhotoKeys, leftoverKeys...)
+
+	return report, nil
+}
+
+// purge removes objects given by fn within one transaction with it, and then
+// files of their photos, as files are not a part of the transaction. Returns
+// the number of removed photos and keys of files that could not be removed.
+func (uc *TrashUsecase) purge(
+	ctx context.Context,
+	fn func(ctx context.Context) ([]string, error),
+) (int, []string, error) {
+	var photos []domain.Photo
+
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		ids, err := fn(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := uc.objRepo.PurgeObjects(ctx, ids); err != nil {
+			return fmt.Errorf("failed to purge objects - %w", err)
+		}
+
+		if err := uc.custOptObjRepo.DeleteObjectCustomOptionsByObjectIds(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete object custom options - %w", err)
+		}
+
+		photos, err = uc.photoRepo.GetPhotosByObjectIds(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to get object photos - %w", err)
+		}
+
+		if err := uc.photoRepo.DeletePhotosByObjectIds(ctx, ids); err != nil {
+			return fmt.Errorf("failed to delete object photos - %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var leftoverKeys []string
+	for _, key := range domain.PhotosKeys(photos) {
+		if err := uc.photoStore.Delete(ctx, key); err != nil {
+			leftoverKeys = append(leftoverKeys, key)
+		}
+	}
+
+	return len(photos), leftoverKeys, nil
+}
+
+func objectIds(objects []domain.Object) []string {
+	ids := make([]string, len(objects))
+	for i, obj := range objects {
+		ids[i] = obj.Id
+	}
+
+	return ids
+}
@@ -0,0 +1,247 @@
+package trash
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/Unlites/comparison_center/backend/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetTrash(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		trashRepo := mocks.NewTrashRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		uc := NewTrashUsecase(trashRepo, comparisonRepo, objRepo, custOptObjRepo, photoRepo, photoStore, transactor, 24*time.Hour)
+
+		ctx := context.Background()
+		now := time.Now()
+		filter := domain.TrashFilter{Limit: 2, Cursor: "cursor"}
+
+		expectedItems := []domain.TrashItem{
+			{
+				Type:         domain.TrashItemTypeObject,
+				Id:           "231934sadas9123deqw",
+				Name:         "BMW X5",
+				ComparisonId: "32492349mkfdsmfks234",
+				DeletedAt:    now,
+			},
+			{
+				Type:      domain.TrashItemTypeComparison,
+				Id:        "85434230werhuhi123912304",
+				Name:      "Cars",
+				DeletedAt: now.Add(-time.Hour),
+			},
+		}
+		expectedPageInfo := domain.PageInfo{Total: 3, NextCursor: "next", PrevCursor: "prev"}
+
+		trashRepo.On("GetTrashItems", ctx, filter).Return(expectedItems, expectedPageInfo, nil)
+
+		items, pageInfo, err := uc.GetTrash(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedItems, items)
+		assert.Equal(t, expectedPageInfo, pageInfo)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		trashRepo := mocks.NewTrashRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		uc := NewTrashUsecase(trashRepo, comparisonRepo, objRepo, custOptObjRepo, photoRepo, photoStore, transactor, 24*time.Hour)
+
+		ctx := context.Background()
+		filter := domain.TrashFilter{Limit: 10}
+
+		trashRepo.On("GetTrashItems", ctx, filter).Return(nil, nil, assert.AnError)
+
+		items, pageInfo, err := uc.GetTrash(ctx, filter)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, items)
+		assert.Equal(t, domain.PageInfo{}, pageInfo)
+	})
+}
+
+func TestPurgeExpired(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		trashRepo := mocks.NewTrashRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		uc := NewTrashUsecase(trashRepo, comparisonRepo, objRepo, custOptObjRepo, photoRepo, photoStore, transactor, 24*time.Hour)
+
+		ctx := context.Background()
+		comparisonObjectIds := []string{"231934sadas9123deqw", "9123deqw231934sadas"}
+		objectIds := []string{"12312sadas123sad3243"}
+
+		deletedBefore := mock.MatchedBy(func(deletedBefore time.Time) bool {
+			return time.Since(deletedBefore) >= 24*time.Hour && time.Since(deletedBefore) < 25*time.Hour
+		})
+
+		comparisonRepo.On("GetDeletedComparisons", ctx, deletedBefore).Return([]domain.Comparison{
+			{Id: "85434230werhuhi123912304", Name: "Cars"},
+		}, nil)
+		objRepo.On("GetDeletedObjectsByComparisonId", ctx, "85434230werhuhi123912304").Return([]domain.Object{
+			{Id: "231934sadas9123deqw"},
+			{Id: "9123deqw231934sadas"},
+		}, nil)
+		comparisonRepo.On("PurgeComparison", ctx, "85434230werhuhi123912304").Return(nil)
+		objRepo.On("PurgeObjects", ctx, comparisonObjectIds).Return(nil)
+		custOptObjRepo.On("DeleteObjectCustomOptionsByObjectIds", ctx, comparisonObjectIds).Return(nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, comparisonObjectIds).Return([]domain.Photo{
+			{Id: "12312sadas123sad", ObjectId: "231934sadas9123deqw", Key: "12312sadas123sad.png"},
+		}, nil)
+		photoRepo.On("DeletePhotosByObjectIds", ctx, comparisonObjectIds).Return(nil)
+		photoStore.On("Delete", ctx, "12312sadas123sad_thumb.png").Return(nil)
+		photoStore.On("Delete", ctx, "12312sadas123sad_medium.png").Return(nil)
+		photoStore.On("Delete", ctx, "12312sadas123sad.png").Return(nil)
+
+		objRepo.On("GetDeletedObjects", ctx, deletedBefore).Return([]domain.Object{{Id: "12312sadas123sad3243"}}, nil)
+		objRepo.On("PurgeObjects", ctx, objectIds).Return(nil)
+		custOptObjRepo.On("DeleteObjectCustomOptionsByObjectIds", ctx, objectIds).Return(nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, objectIds).Return([]domain.Photo{}, nil)
+		photoRepo.On("DeletePhotosByObjectIds", ctx, objectIds).Return(nil)
+
+		report, err := uc.PurgeExpired(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.TrashPurgeReport{Comparisons: 1, Objects: 3, Photos: 1}, report)
+		assert.Equal(t, 2, transactor.Committed)
+		comparisonRepo.AssertExpectations(t)
+		objRepo.AssertExpectations(t)
+		custOptObjRepo.AssertExpectations(t)
+		photoRepo.AssertExpectations(t)
+		photoStore.AssertExpectations(t)
+	})
+
+	t.Run("Nothing expired", func(t *testing.T) {
+		trashRepo := mocks.NewTrashRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		uc := NewTrashUsecase(trashRepo, comparisonRepo, objRepo, custOptObjRepo, photoRepo, photoStore, transactor, 24*time.Hour)
+
+		ctx := context.Background()
+
+		comparisonRepo.On("GetDeletedComparisons", ctx, mock.AnythingOfType("time.Time")).Return([]domain.Comparison{}, nil)
+		objRepo.On("GetDeletedObjects", ctx, mock.AnythingOfType("time.Time")).Return([]domain.Object{}, nil)
+
+		report, err := uc.PurgeExpired(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.TrashPurgeReport{}, report)
+		assert.Equal(t, 0, transactor.Committed)
+		objRepo.AssertNotCalled(t, "PurgeObjects")
+	})
+
+	t.Run("Failed removal of photo files is reported", func(t *testing.T) {
+		trashRepo := mocks.NewTrashRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		uc := NewTrashUsecase(trashRepo, comparisonRepo, objRepo, custOptObjRepo, photoRepo, photoStore, transactor, 24*time.Hour)
+
+		ctx := context.Background()
+		comparisonObjectIds := []string{"231934sadas9123deqw"}
+		objectIds := []string{"12312sadas123sad3243"}
+
+		comparisonRepo.On("GetDeletedComparisons", ctx, mock.AnythingOfType("time.Time")).Return([]domain.Comparison{
+			{Id: "85434230werhuhi123912304", Name: "Cars"},
+		}, nil)
+		objRepo.On("GetDeletedObjectsByComparisonId", ctx, "85434230werhuhi123912304").
+			Return([]domain.Object{{Id: "231934sadas9123deqw"}}, nil)
+		comparisonRepo.On("PurgeComparison", ctx, "85434230werhuhi123912304").Return(nil)
+		objRepo.On("PurgeObjects", ctx, comparisonObjectIds).Return(nil)
+		custOptObjRepo.On("DeleteObjectCustomOptionsByObjectIds", ctx, comparisonObjectIds).Return(nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, comparisonObjectIds).Return([]domain.Photo{
+			{Id: "12312sadas123sad", ObjectId: "231934sadas9123deqw", Key: "12312sadas123sad.png"},
+		}, nil)
+		photoRepo.On("DeletePhotosByObjectIds", ctx, comparisonObjectIds).Return(nil)
+		photoStore.On("Delete", ctx, "12312sadas123sad_thumb.png").Return(nil)
+		photoStore.On("Delete", ctx, "12312sadas123sad_medium.png").Return(fmt.Errorf("storage is unavailable"))
+		photoStore.On("Delete", ctx, "12312sadas123sad.png").Return(fmt.Errorf("storage is unavailable"))
+
+		objRepo.On("GetDeletedObjects", ctx, mock.AnythingOfType("time.Time")).
+			Return([]domain.Object{{Id: "12312sadas123sad3243"}}, nil)
+		objRepo.On("PurgeObjects", ctx, objectIds).Return(nil)
+		custOptObjRepo.On("DeleteObjectCustomOptionsByObjectIds", ctx, objectIds).Return(nil)
+		photoRepo.On("GetPhotosByObjectIds", ctx, objectIds).Return([]domain.Photo{
+			{Id: "3243sad123sad1231", ObjectId: "12312sadas123sad3243", Key: "3243sad123sad1231.jpg"},
+		}, nil)
+		photoRepo.On("DeletePhotosByObjectIds", ctx, objectIds).Return(nil)
+		photoStore.On("Delete", ctx, "3243sad123sad1231_thumb.jpg").Return(fmt.Errorf("storage is unavailable"))
+		photoStore.On("Delete", ctx, "3243sad123sad1231_medium.jpg").Return(nil)
+		photoStore.On("Delete", ctx, "3243sad123sad1231.jpg").Return(nil)
+
+		report, err := uc.PurgeExpired(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.TrashPurgeReport{
+			Comparisons: 1,
+			Objects:     2,
+			Photos:      2,
+			LeftoverPhotoKeys: []string{
+				"12312sadas123sad_medium.png",
+				"12312sadas123sad.png",
+				"3243sad123sad1231_thumb.jpg",
+			},
+		}, report)
+		assert.Equal(t, 2, transactor.Committed)
+		// the rest of files are removed anyway
+		photoStore.AssertExpectations(t)
+	})
+
+	t.Run("Error rolls back", func(t *testing.T) {
+		trashRepo := mocks.NewTrashRepositoryMock()
+		comparisonRepo := mocks.NewComparisonRepositoryMock()
+		objRepo := mocks.NewObjectRepositoryMock()
+		custOptObjRepo := mocks.NewObjectCustomOptionRepositoryMock()
+		photoRepo := mocks.NewPhotoRepositoryMock()
+		photoStore := mocks.NewPhotoStoreMock()
+		transactor := mocks.NewInMemoryTransactor()
+		uc := NewTrashUsecase(trashRepo, comparisonRepo, objRepo, custOptObjRepo, photoRepo, photoStore, transactor, 24*time.Hour)
+
+		ctx := context.Background()
+		objectIds := []string{"231934sadas9123deqw"}
+
+		comparisonRepo.On("GetDeletedComparisons", ctx, mock.AnythingOfType("time.Time")).Return([]domain.Comparison{
+			{Id: "85434230werhuhi123912304", Name: "Cars"},
+		}, nil)
+		objRepo.On("GetDeletedObjectsByComparisonId", ctx, "85434230werhuhi123912304").
+			Return([]domain.Object{{Id: "231934sadas9123deqw"}}, nil)
+		comparisonRepo.On("PurgeComparison", ctx, "85434230werhuhi123912304").Return(nil)
+		objRepo.On("PurgeObjects", ctx, objectIds).Return(nil)
+		custOptObjRepo.On("DeleteObjectCustomOptionsByObjectIds", ctx, objectIds).Return(assert.AnError)
+
+		report, err := uc.PurgeExpired(ctx)
+
+		assert.Error(t, err)
+		assert.Equal(t, domain.TrashPurgeReport{}, report)
+		assert.Equal(t, 1, transactor.RolledBack)
+		objRepo.AssertNotCalled(t, "GetDeletedObjects")
+		photoStore.AssertNotCalled(t, "Delete")
+	})
+}
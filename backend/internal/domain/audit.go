@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"reflect"
+	"slices"
+	"time"
+)
+
+type AuditEntity string
+
+const (
+	AuditEntityComparison   AuditEntity = "comparison"
+	AuditEntityObject       AuditEntity = "object"
+	AuditEntityCustomOption AuditEntity = "custom_option"
+)
+
+type AuditAction string
+
+const (
+	AuditActionCreate  AuditAction = "create"
+	AuditActionUpdate  AuditAction = "update"
+	AuditActionDelete  AuditAction = "delete"
+	AuditActionRestore AuditAction = "restore"
+)
+
+// AuditField is a value of the entity field kept in the audit log. Values of custom
+// options and option weights are kept as separate fields, e.g. "custom_options.<id>".
+type AuditField struct {
+	Name  string
+	Value any
+}
+
+// FieldChange is a value of the field before and after the change,
+// the value is nil when the field did not exist on that side.
+type FieldChange struct {
+	Field  string
+	Before any
+	After  any
+}
+
+// AuditEvent records the change of the entity.
+type AuditEvent struct {
+	Id        string
+	Entity    AuditEntity
+	EntityId  string
+	Action    AuditAction
+	CreatedAt time.Time
+	Changes   []FieldChange
+}
+
+// NewAuditEvent records the change of the entity from before to after fields. Nil before
+// means the entity is created, nil after means it is deleted. Event of the update
+// that changed nothing has no changes and is not worth keeping.
+func NewAuditEvent(entity AuditEntity, entityId string, before, after []AuditField) AuditEvent {
+	action := AuditActionUpdate
+	if before == nil {
+		action = AuditActionCreate
+	}
+
+	if after == nil {
+		action = AuditActionDelete
+	}
+
+	return AuditEvent{
+		Entity:    entity,
+		EntityId:  entityId,
+		Action:    action,
+		CreatedAt: time.Now(),
+		Changes:   diffAuditFields(before, after),
+	}
+}
+
+// NewRestoreAuditEvent records the restoration of the entity from the trash,
+// fields it is restored with are recorded as created.
+func NewRestoreAuditEvent(entity AuditEntity, entityId string, fields []AuditField) AuditEvent {
+	event := NewAuditEvent(entity, entityId, nil, fields)
+	event.Action = AuditActionRestore
+
+	return event
+}
+
+// diffAuditFields gives changed fields in the order of before fields followed by new ones.
+// Fields that are empty on both sides, e.g. advantages not set on creation, are skipped.
+func diffAuditFields(before, after []AuditField) []FieldChange {
+	names := make([]string, 0, len(before)+len(after))
+	beforeValues := make(map[string]any, len(before))
+	afterValues := make(map[string]any, len(after))
+
+	for _, f := range before {
+		names = append(names, f.Name)
+		beforeValues[f.Name] = f.Value
+	}
+
+	for _, f := range after {
+		if _, ok := beforeValues[f.Name]; !ok {
+			names = append(names, f.Name)
+		}
+		afterValues[f.Name] = f.Value
+	}
+
+	changes := make([]FieldChange, 0)
+	for _, name := range names {
+		b, a := beforeValues[name], afterValues[name]
+
+		if isEmptyAuditValue(b) && isEmptyAuditValue(a) || reflect.DeepEqual(b, a) {
+			continue
+		}
+
+		changes = append(changes, FieldChange{Field: name, Before: b, After: a})
+	}
+
+	return changes
+}
+
+func isEmptyAuditValue(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Slice {
+		return value.Len() == 0
+	}
+
+	return value.IsZero()
+}
+
+// AuditFields gives fields of the comparison kept in the audit log.
+func (c Comparison) AuditFields() []AuditField {
+	fields := []AuditField{
+		{Name: "name", Value: c.Name},
+		{Name: "custom_option_ids", Value: slices.Clone(c.CustomOptionIds)},
+		{Name: "tags", Value: slices.Clone(c.Tags)},
+		{Name: "folder_id", Value: c.FolderId},
+	}
+
+	for _, ow := range c.OptionWeights {
+		fields = append(fields,
+			AuditField{Name: "option_weights." + ow.CustomOptionId + ".weight", Value: ow.Weight},
+			AuditField{Name: "option_weights." + ow.CustomOptionId + ".direction", Value: string(ow.Direction)},
+		)
+	}
+
+	return fields
+}
+
+// AuditFields gives fields of the object kept in the audit log, including values
+// of its custom options in their canonical representation.
+func (o Object) AuditFields() []AuditField {
+	fields := []AuditField{
+		{Name: "name", Value: o.Name},
+		{Name: "rating", Value: o.Rating},
+		{Name: "advs", Value: o.Advs},
+		{Name: "disadvs", Value: o.Disadvs},
+		{Name: "comparison_id", Value: o.ComparisonId},
+	}
+
+	for _, oco := range o.ObjectCustomOptions {
+		fields = append(fields, AuditField{Name: "custom_options." + oco.CustomOptionId, Value: oco.Value})
+	}
+
+	return fields
+}
+
+// AuditFields gives fields of the custom option kept in the audit log.
+func (co CustomOption) AuditFields() []AuditField {
+	return []AuditField{
+		{Name: "name", Value: co.Name},
+		{Name: "type", Value: string(co.Type)},
+		{Name: "enum_values", Value: slices.Clone(co.EnumValues)},
+		{Name: "currency", Value: co.Currency},
+	}
+}
+
+// AuditFilter selects a page of the history of the entity, the latest changes go first.
+type AuditFilter struct {
+	Limit    int
+	Offset   int
+	Cursor   string
+	Entity   AuditEntity
+	EntityId string
+}
+
+// NewAuditFilter creates filter for a page starting either at offset or at cursor
+// returned with one of previous pages.
+func NewAuditFilter(limit, offset int, cursor string, entity AuditEntity, entityId string) (AuditFilter, error) {
+	limit, err := validatePage(limit, offset, cursor)
+	if err != nil {
+		return AuditFilter{}, err
+	}
+
+	return AuditFilter{
+		Limit:    limit,
+		Offset:   offset,
+		Cursor:   cursor,
+		Entity:   entity,
+		EntityId: entityId,
+	}, nil
+}
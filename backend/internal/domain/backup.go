@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// BackupVersion is increased on incompatible changes of backup archives,
+// archives of other versions are not restored.
+const BackupVersion = 1
+
+// Backup holds all entities of the database. Photo files are dumped and restored separately,
+// one by one, since they do not fit in memory.
+type Backup struct {
+	Folders             []Folder
+	Comparisons         []Comparison
+	CustomOptions       []CustomOption
+	Objects             []Object
+	ObjectCustomOptions []ObjectCustomOption
+	Photos              []Photo
+}
+
+// BackupManifest describes the backup archive.
+type BackupManifest struct {
+	Version             int
+	CreatedAt           time.Time
+	Folders             int
+	Comparisons         int
+	CustomOptions       int
+	Objects             int
+	ObjectCustomOptions int
+	Photos              int
+}
+
+func NewBackupManifest(backup Backup) BackupManifest {
+	return BackupManifest{
+		Version:             BackupVersion,
+		CreatedAt:           time.Now().UTC(),
+		Folders:             len(backup.Folders),
+		Comparisons:         len(backup.Comparisons),
+		CustomOptions:       len(backup.CustomOptions),
+		Objects:             len(backup.Objects),
+		ObjectCustomOptions: len(backup.ObjectCustomOptions),
+		Photos:              len(backup.Photos),
+	}
+}
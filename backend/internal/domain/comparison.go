@@ -6,41 +6,162 @@ import (
 	"time"
 )
 
+type ScoreDirection string
+
+const (
+	ScoreDirectionHigherIsBetter ScoreDirection = "higher_is_better"
+	ScoreDirectionLowerIsBetter  ScoreDirection = "lower_is_better"
+)
+
+var ProvidedScoreDirections = []ScoreDirection{
+	ScoreDirectionHigherIsBetter,
+	ScoreDirectionLowerIsBetter,
+}
+
+type OptionWeight struct {
+	CustomOptionId string
+	Weight         float64
+	Direction      ScoreDirection
+}
+
+// Comparison is in the trash when DeletedAt is set. Comparison without FolderId
+// is kept at the root, out of any folder.
 type Comparison struct {
 	Id              string
 	Name            string
 	CreatedAt       time.Time
 	CustomOptionIds []string
+	OptionWeights   []OptionWeight
+	Tags            []string
+	FolderId        string
+	DeletedAt       time.Time
+}
+
+// Validate checks that every option weight refers to one of custom options of the comparison.
+func (c Comparison) Validate() error {
+	for _, ow := range c.OptionWeights {
+		if !slices.Contains(c.CustomOptionIds, ow.CustomOptionId) {
+			return fmt.Errorf(
+				"weighted custom option '%s' is not used by comparison - %w",
+				ow.CustomOptionId,
+				ErrInvalidValue,
+			)
+		}
+
+		if ow.Weight < 0 {
+			return fmt.Errorf("weight must not be less than zero - %w", ErrInvalidValue)
+		}
+
+		if !slices.Contains(ProvidedScoreDirections, ow.Direction) {
+			return fmt.Errorf("unknown score direction '%s' - %w", ow.Direction, ErrInvalidValue)
+		}
+	}
+
+	return nil
+}
+
+// WeightOf returns scoring settings of the custom option. Options without
+// explicit settings have weight 1 and prefer higher values.
+func (c Comparison) WeightOf(customOptionId string) OptionWeight {
+	for _, ow := range c.OptionWeights {
+		if ow.CustomOptionId == customOptionId {
+			return ow
+		}
+	}
+
+	return OptionWeight{
+		CustomOptionId: customOptionId,
+		Weight:         1,
+		Direction:      ScoreDirectionHigherIsBetter,
+	}
+}
+
+// CloneOptions selects what the clone of the comparison gets besides its custom options
+// and their weights. Photos can be copied only together with objects.
+type CloneOptions struct {
+	Name           string
+	IncludeObjects bool
+	IncludePhotos  bool
+}
+
+func (o CloneOptions) Validate() error {
+	if o.IncludePhotos && !o.IncludeObjects {
+		return fmt.Errorf("photos can not be cloned without objects - %w", ErrInvalidValue)
+	}
+
+	return nil
+}
+
+// Clone gives a copy of the comparison with the given name using the same
+// custom options with the same weights, tags and folder, without id and creation time.
+func (c Comparison) Clone(name string) Comparison {
+	return Comparison{
+		Name:            name,
+		CustomOptionIds: slices.Clone(c.CustomOptionIds),
+		OptionWeights:   slices.Clone(c.OptionWeights),
+		Tags:            slices.Clone(c.Tags),
+		FolderId:        c.FolderId,
+	}
 }
 
 type ComparisonFilter struct {
-	Limit   int
-	Offset  int
-	OrderBy string
+	Limit          int
+	Offset         int
+	Cursor         string
+	OrderBy        []SortKey
+	Name           string
+	CreatedFrom    time.Time
+	CreatedTo      time.Time
+	CustomOptionId string
+	Tag            string
+	FolderIds      []string
 }
 
-var providedComparisonOrderings = []string{"created_at"}
+var providedComparisonOrderings = []string{"created_at", "name"}
 
-func NewComparisonFilter(limit, offset int, orderBy string) (ComparisonFilter, error) {
-	if offset < 0 || limit < 0 {
-		return ComparisonFilter{}, fmt.Errorf("offset amd limit must not be less than zero")
+// NewComparisonFilter creates filter for a page starting either at offset or at cursor
+// returned with one of previous pages. Name matches case-insensitive substring of the name,
+// zero createdFrom and createdTo leave the creation date range open. FolderIds start with
+// the given folder and are expected to be completed with its subfolders.
+func NewComparisonFilter(
+	limit, offset int,
+	cursor, orderBy, name string,
+	createdFrom, createdTo time.Time,
+	customOptionId, tag, folderId string,
+) (ComparisonFilter, error) {
+	limit, err := validatePage(limit, offset, cursor)
+	if err != nil {
+		return ComparisonFilter{}, err
 	}
 
-	if limit == 0 {
-		limit = 10
+	if !createdFrom.IsZero() && !createdTo.IsZero() && createdFrom.After(createdTo) {
+		return ComparisonFilter{}, fmt.Errorf("created_from must not be after created_to")
 	}
 
-	if orderBy == "" {
-		orderBy = "created_at"
+	var folderIds []string
+	if folderId != "" {
+		folderIds = []string{folderId}
 	}
 
-	if !slices.Contains(providedComparisonOrderings, orderBy) {
-		return ComparisonFilter{}, fmt.Errorf("incorrect ordering value")
+	sortKeys, err := parseSortKeys(
+		orderBy,
+		[]SortKey{{Field: "created_at"}},
+		allowedFields(providedComparisonOrderings...),
+	)
+	if err != nil {
+		return ComparisonFilter{}, err
 	}
 
 	return ComparisonFilter{
-		Limit:   limit,
-		Offset:  offset,
-		OrderBy: orderBy,
+		Limit:          limit,
+		Offset:         offset,
+		Cursor:         cursor,
+		OrderBy:        sortKeys,
+		Name:           name,
+		CreatedFrom:    createdFrom,
+		CreatedTo:      createdTo,
+		CustomOptionId: customOptionId,
+		Tag:            NormalizeTag(tag),
+		FolderIds:      folderIds,
 	}, nil
 }
@@ -2,31 +2,228 @@ package domain
 
 import (
 	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
+type CustomOptionType string
+
+const (
+	CustomOptionTypeText    CustomOptionType = "text"
+	CustomOptionTypeInteger CustomOptionType = "integer"
+	CustomOptionTypeDecimal CustomOptionType = "decimal"
+	CustomOptionTypeBoolean CustomOptionType = "boolean"
+	CustomOptionTypeEnum    CustomOptionType = "enum"
+	CustomOptionTypeDate    CustomOptionType = "date"
+	CustomOptionTypeMoney   CustomOptionType = "money"
+)
+
+var ProvidedCustomOptionTypes = []CustomOptionType{
+	CustomOptionTypeText,
+	CustomOptionTypeInteger,
+	CustomOptionTypeDecimal,
+	CustomOptionTypeBoolean,
+	CustomOptionTypeEnum,
+	CustomOptionTypeDate,
+	CustomOptionTypeMoney,
+}
+
+const DateLayout = "2006-01-02"
+
 type CustomOption struct {
-	Id   string
-	Name string
+	Id         string
+	Name       string
+	Type       CustomOptionType
+	EnumValues []string
+	Currency   string
+}
+
+// Validate checks that type specific settings are consistent with the option type.
+func (co CustomOption) Validate() error {
+	if !slices.Contains(ProvidedCustomOptionTypes, co.Type) {
+		return fmt.Errorf("unknown custom option type '%s' - %w", co.Type, ErrInvalidValue)
+	}
+
+	if co.Type == CustomOptionTypeEnum && len(co.EnumValues) == 0 {
+		return fmt.Errorf("enum values are required for enum option - %w", ErrInvalidValue)
+	}
+
+	if co.Type != CustomOptionTypeEnum && len(co.EnumValues) != 0 {
+		return fmt.Errorf("enum values are allowed only for enum option - %w", ErrInvalidValue)
+	}
+
+	if co.Type == CustomOptionTypeMoney && co.Currency == "" {
+		return fmt.Errorf("currency is required for money option - %w", ErrInvalidValue)
+	}
+
+	if co.Type != CustomOptionTypeMoney && co.Currency != "" {
+		return fmt.Errorf("currency is allowed only for money option - %w", ErrInvalidValue)
+	}
+
+	return nil
+}
+
+// ParseValue converts raw user input to the typed value stored for this option:
+// string for text and enum, int64 for integer, float64 for decimal and money,
+// bool for boolean and time.Time for date.
+func (co CustomOption) ParseValue(raw string) (any, error) {
+	value := strings.TrimSpace(raw)
+
+	switch co.Type {
+	case CustomOptionTypeText, "":
+		return value, nil
+	case CustomOptionTypeInteger:
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not an integer - %w", raw, ErrInvalidValue)
+		}
+
+		return parsed, nil
+	case CustomOptionTypeDecimal:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || !isFinite(parsed) {
+			return nil, fmt.Errorf("'%s' is not a decimal - %w", raw, ErrInvalidValue)
+		}
+
+		return parsed, nil
+	case CustomOptionTypeBoolean:
+		switch strings.ToLower(value) {
+		case "true", "yes", "1":
+			return true, nil
+		case "false", "no", "0":
+			return false, nil
+		}
+
+		return nil, fmt.Errorf("'%s' is not a boolean - %w", raw, ErrInvalidValue)
+	case CustomOptionTypeEnum:
+		idx := slices.IndexFunc(co.EnumValues, func(allowed string) bool {
+			return strings.EqualFold(allowed, value)
+		})
+		if idx == -1 {
+			return nil, fmt.Errorf(
+				"'%s' is not one of [%s] - %w",
+				raw,
+				strings.Join(co.EnumValues, ", "),
+				ErrInvalidValue,
+			)
+		}
+
+		return co.EnumValues[idx], nil
+	case CustomOptionTypeDate:
+		parsed, err := time.Parse(DateLayout, value)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a date in YYYY-MM-DD format - %w", raw, ErrInvalidValue)
+		}
+
+		return parsed, nil
+	case CustomOptionTypeMoney:
+		return co.parseMoney(raw, value)
+	}
+
+	return nil, fmt.Errorf("unknown custom option type '%s' - %w", co.Type, ErrInvalidValue)
+}
+
+// parseMoney accepts an amount optionally preceded or followed by
+// the currency code of the option, e.g. "1299.99", "1299.99 USD" or "USD 1299.99".
+func (co CustomOption) parseMoney(raw, value string) (any, error) {
+	parts := strings.Fields(value)
+
+	var amount string
+
+	switch len(parts) {
+	case 1:
+		amount = parts[0]
+	case 2:
+		currency := parts[1]
+		amount = parts[0]
+		if _, err := strconv.ParseFloat(parts[0], 64); err != nil {
+			currency, amount = parts[0], parts[1]
+		}
+
+		if !strings.EqualFold(currency, co.Currency) {
+			return nil, fmt.Errorf(
+				"currency of '%s' must be %s - %w",
+				raw,
+				co.Currency,
+				ErrInvalidValue,
+			)
+		}
+	default:
+		return nil, fmt.Errorf("'%s' is not a money amount - %w", raw, ErrInvalidValue)
+	}
+
+	parsed, err := strconv.ParseFloat(amount, 64)
+	if err != nil || !isFinite(parsed) {
+		return nil, fmt.Errorf("'%s' is not a money amount - %w", raw, ErrInvalidValue)
+	}
+
+	return parsed, nil
+}
+
+// isFinite reports whether the number is neither NaN nor an infinity,
+// which ParseFloat accepts but which can be neither compared nor encoded.
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// FormatValue returns canonical textual representation of the value
+// previously returned by ParseValue.
+func (co CustomOption) FormatValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(DateLayout)
+	case float64:
+		if co.Type == CustomOptionTypeMoney {
+			return fmt.Sprintf("%s %s", strconv.FormatFloat(v, 'f', 2, 64), co.Currency)
+		}
+
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return fmt.Sprint(value)
 }
 
 type CustomOptionFilter struct {
-	Limit  int
-	Offset int
-	Name   string
+	Limit   int
+	Offset  int
+	Cursor  string
+	OrderBy []SortKey
+	Name    string
 }
 
-func NewCustomOptionFilter(limit, offset int, name string) (CustomOptionFilter, error) {
-	if offset < 0 || limit < 0 {
-		return CustomOptionFilter{}, fmt.Errorf("offset amd limit must not be less than zero")
+var providedCustomOptionOrderings = []string{"name", "type"}
+
+// NewCustomOptionFilter creates filter for a page starting either at offset or at cursor
+// returned with one of previous pages.
+func NewCustomOptionFilter(limit, offset int, cursor, orderBy, name string) (CustomOptionFilter, error) {
+	limit, err := validatePage(limit, offset, cursor)
+	if err != nil {
+		return CustomOptionFilter{}, err
 	}
 
-	if limit == 0 {
-		limit = 10
+	sortKeys, err := parseSortKeys(
+		orderBy,
+		[]SortKey{{Field: "name"}},
+		allowedFields(providedCustomOptionOrderings...),
+	)
+	if err != nil {
+		return CustomOptionFilter{}, err
 	}
 
 	return CustomOptionFilter{
-		Limit:  limit,
-		Offset: offset,
-		Name:   name,
+		Limit:   limit,
+		Offset:  offset,
+		Cursor:  cursor,
+		OrderBy: sortKeys,
+		Name:    name,
 	}, nil
 }
@@ -1,6 +1,39 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var ErrNotFound = errors.New("not found")
 var ErrAlreadyExists = errors.New("already exists")
+var ErrInvalidValue = errors.New("invalid value")
+var ErrConflict = errors.New("conflict")
+var ErrTimeout = errors.New("timeout")
+
+// Dependent is an entity that refers to another one and prevents its deletion.
+type Dependent struct {
+	Type string
+	Id   string
+	Name string
+}
+
+// DependentsError is returned when an entity can not be deleted while it is still referenced.
+type DependentsError struct {
+	Entity     string
+	Dependents []Dependent
+}
+
+func (e *DependentsError) Error() string {
+	names := make([]string, len(e.Dependents))
+	for i, d := range e.Dependents {
+		names[i] = fmt.Sprintf("%s '%s'", d.Type, d.Name)
+	}
+
+	return fmt.Sprintf("%s is used by %s - %s", e.Entity, strings.Join(names, ", "), ErrConflict)
+}
+
+func (e *DependentsError) Unwrap() error {
+	return ErrConflict
+}
@@ -0,0 +1,23 @@
+package domain
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"
+	ExportFormatXLSX ExportFormat = "xlsx"
+	ExportFormatJSON ExportFormat = "json"
+)
+
+var ProvidedExportFormats = []ExportFormat{
+	ExportFormatCSV,
+	ExportFormatXLSX,
+	ExportFormatJSON,
+}
+
+// ExportFile is an encoded comparison, Name is given without an extension.
+type ExportFile struct {
+	Name        string
+	Format      ExportFormat
+	ContentType string
+	Content     []byte
+}
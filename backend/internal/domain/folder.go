@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Folder groups comparisons, folders without ParentId are at the root.
+// Names of folders are unique within their parent.
+type Folder struct {
+	Id        string
+	Name      string
+	ParentId  string
+	CreatedAt time.Time
+}
+
+// ValidateParent checks that the folder is not moved into itself or one of its
+// descendants, which would detach the subtree from the root.
+func (f Folder) ValidateParent(descendantIds []string) error {
+	if f.ParentId == "" {
+		return nil
+	}
+
+	if f.ParentId == f.Id || slices.Contains(descendantIds, f.ParentId) {
+		return fmt.Errorf("folder can not be moved into itself or its subfolder - %w", ErrInvalidValue)
+	}
+
+	return nil
+}
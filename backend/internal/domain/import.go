@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MaxImportRows limits the number of objects imported at once.
+const MaxImportRows = 1000
+
+var importFields = []string{"name", "rating", "advs", "disadvs"}
+
+// importIgnoredFields are columns of exported files that are not imported, e.g. creation time
+// of an object is always the time of import.
+var importIgnoredFields = []string{"created_at"}
+
+// ImportTable is a table of objects read from an uploaded file. CustomOptions are
+// definitions of custom options given by the file, e.g. by a JSON export, they are
+// matched to columns by name and used to create missing options with their types.
+type ImportTable struct {
+	Header        []string
+	Rows          [][]string
+	CustomOptions []CustomOption
+}
+
+type ImportOptions struct {
+	DryRun              bool
+	CreateCustomOptions bool
+}
+
+// ImportColumns maps fields of objects and names of custom options to indexes of table columns.
+// Custom options are listed in the order of columns.
+type ImportColumns struct {
+	Fields        map[string]int
+	CustomOptions []string
+	OptionColumns map[string]int
+}
+
+// NewImportColumns maps the header of an imported table. Fields of objects are matched
+// case-insensitive, any other column is taken for a custom option with the same name.
+func NewImportColumns(header []string) (ImportColumns, error) {
+	columns := ImportColumns{
+		Fields:        make(map[string]int),
+		OptionColumns: make(map[string]int),
+	}
+
+	for i, title := range header {
+		title = strings.TrimSpace(title)
+		if title == "" {
+			continue
+		}
+
+		field := strings.ToLower(title)
+		if slices.Contains(importIgnoredFields, field) {
+			continue
+		}
+
+		if slices.Contains(importFields, field) {
+			if _, ok := columns.Fields[field]; ok {
+				return ImportColumns{}, fmt.Errorf("duplicate column '%s' - %w", title, ErrInvalidValue)
+			}
+
+			columns.Fields[field] = i
+			continue
+		}
+
+		if _, ok := columns.OptionColumns[title]; ok {
+			return ImportColumns{}, fmt.Errorf("duplicate column '%s' - %w", title, ErrInvalidValue)
+		}
+
+		columns.CustomOptions = append(columns.CustomOptions, title)
+		columns.OptionColumns[title] = i
+	}
+
+	for _, required := range []string{"name", "rating"} {
+		if _, ok := columns.Fields[required]; !ok {
+			return ImportColumns{}, fmt.Errorf("column '%s' is required - %w", required, ErrInvalidValue)
+		}
+	}
+
+	return columns, nil
+}
+
+// Field returns the trimmed value of the object field in the row, missing cells are empty.
+func (c ImportColumns) Field(row []string, field string) string {
+	idx, ok := c.Fields[field]
+	if !ok {
+		return ""
+	}
+
+	return importCell(row, idx)
+}
+
+// Option returns the trimmed value of the custom option in the row, missing cells are empty.
+func (c ImportColumns) Option(row []string, name string) string {
+	return importCell(row, c.OptionColumns[name])
+}
+
+func importCell(row []string, idx int) string {
+	if idx >= len(row) {
+		return ""
+	}
+
+	return strings.TrimSpace(row[idx])
+}
+
+// ImportRowResult describes an imported row. Row is the number of the row in the table
+// counting the header, so it matches row numbers shown by spreadsheet editors.
+type ImportRowResult struct {
+	Row      int
+	Name     string
+	ObjectId string
+	Errors   []string
+}
+
+// ImportReport describes an import of objects. Objects are imported only when all rows are
+// valid, so Imported is zero on dry run or when any row is invalid.
+type ImportReport struct {
+	DryRun               bool
+	Total                int
+	Invalid              int
+	Imported             int
+	CreatedCustomOptions []string
+	Rows                 []ImportRowResult
+}
@@ -0,0 +1,14 @@
+package domain
+
+// ComparisonMatrix is a table of comparison objects against its custom options.
+// Values of every row are placed in the order of CustomOptions, missing values are empty.
+type ComparisonMatrix struct {
+	Comparison    Comparison
+	CustomOptions []CustomOption
+	Rows          []ComparisonMatrixRow
+}
+
+type ComparisonMatrixRow struct {
+	Object Object
+	Values []string
+}
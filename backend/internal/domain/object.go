@@ -3,9 +3,13 @@ package domain
 import (
 	"fmt"
 	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+// Object is in the trash when DeletedAt is set. Score and Highlight are set
+// only for objects found by a full-text search.
 type Object struct {
 	Id                  string
 	Name                string
@@ -13,43 +17,174 @@ type Object struct {
 	CreatedAt           time.Time
 	Advs                string
 	Disadvs             string
-	PhotoPath           string
 	ComparisonId        string
 	ObjectCustomOptions []ObjectCustomOption
+	DeletedAt           time.Time
+	Score               float64
+	Highlight           Highlight
+}
+
+type OptionOperator string
+
+const (
+	OptionOperatorEq  OptionOperator = "eq"
+	OptionOperatorNe  OptionOperator = "ne"
+	OptionOperatorGt  OptionOperator = "gt"
+	OptionOperatorGte OptionOperator = "gte"
+	OptionOperatorLt  OptionOperator = "lt"
+	OptionOperatorLte OptionOperator = "lte"
+)
+
+var ProvidedOptionOperators = []OptionOperator{
+	OptionOperatorEq,
+	OptionOperatorNe,
+	OptionOperatorGt,
+	OptionOperatorGte,
+	OptionOperatorLt,
+	OptionOperatorLte,
+}
+
+// OptionPredicate matches objects having value of the custom option satisfying the operator.
+// TypedValue is parsed from Value according to the type of the custom option.
+type OptionPredicate struct {
+	CustomOptionId string
+	Operator       OptionOperator
+	Value          string
+	TypedValue     any
 }
 
 type ObjectFilter struct {
-	Limit        int
-	Offset       int
-	OrderBy      string
-	Name         string
-	ComparisonId string
+	Limit   int
+	Offset  int
+	Cursor  string
+	OrderBy []SortKey
+	Name    string
+	// Query is a full-text search over names, advantages, disadvantages and
+	// values of custom options of objects.
+	Query            string
+	ComparisonId     string
+	OptionPredicates []OptionPredicate
 }
 
+// OptionOrderingPrefix is used to order objects by value of the custom option, e.g. "option:<id>".
+const OptionOrderingPrefix = "option:"
+
 var providedObjectOrderings = []string{"created_at", "name", "rating"}
 
-func NewObjectFilter(limit, offset int, orderBy, name, comparisonId string) (ObjectFilter, error) {
-	if offset < 0 || limit < 0 {
-		return ObjectFilter{}, fmt.Errorf("offset amd limit must not be less than zero")
+// CustomOptionId returns id of the custom option objects are ordered by, if the key refers to one.
+func (k SortKey) CustomOptionId() (string, bool) {
+	id, found := strings.CutPrefix(k.Field, OptionOrderingPrefix)
+	if !found || id == "" {
+		return "", false
 	}
 
-	if limit == 0 {
-		limit = 10
+	return id, true
+}
+
+// OrdersByScore reports whether objects are ordered by relevance to the query.
+func (f ObjectFilter) OrdersByScore() bool {
+	return slices.ContainsFunc(f.OrderBy, func(k SortKey) bool {
+		return k.Field == ScoreOrdering
+	})
+}
+
+// OrdersByCustomOptions reports whether objects are ordered by value of any custom option.
+func (f ObjectFilter) OrdersByCustomOptions() bool {
+	return slices.ContainsFunc(f.OrderBy, func(k SortKey) bool {
+		_, ok := k.CustomOptionId()
+		return ok
+	})
+}
+
+// NewObjectFilter creates filter for a page starting either at offset or at cursor
+// returned with one of previous pages. Objects found by query are ordered by relevance
+// unless other ordering is given, ordering by "score" is allowed only with query.
+func NewObjectFilter(
+	limit, offset int,
+	cursor, orderBy, name, query, comparisonId string,
+	optionPredicates []OptionPredicate,
+) (ObjectFilter, error) {
+	limit, err := validatePage(limit, offset, cursor)
+	if err != nil {
+		return ObjectFilter{}, err
 	}
 
-	if orderBy == "" {
-		orderBy = "created_at"
+	query = strings.TrimSpace(query)
+
+	defaultKeys := []SortKey{{Field: "created_at"}}
+	if query != "" {
+		defaultKeys = []SortKey{{Field: ScoreOrdering, Desc: true}}
 	}
 
-	if !slices.Contains(providedObjectOrderings, orderBy) {
-		return ObjectFilter{}, fmt.Errorf("incorrect ordering value")
+	sortKeys, err := parseSortKeys(orderBy, defaultKeys, func(field string) bool {
+		_, optionOrdering := SortKey{Field: field}.CustomOptionId()
+		return optionOrdering ||
+			slices.Contains(providedObjectOrderings, field) ||
+			(field == ScoreOrdering && query != "")
+	})
+	if err != nil {
+		return ObjectFilter{}, err
+	}
+
+	for _, p := range optionPredicates {
+		if p.CustomOptionId == "" {
+			return ObjectFilter{}, fmt.Errorf("custom option id of predicate must not be empty")
+		}
+
+		if !slices.Contains(ProvidedOptionOperators, p.Operator) {
+			return ObjectFilter{}, fmt.Errorf("incorrect operator '%s' of custom option predicate", p.Operator)
+		}
 	}
 
 	return ObjectFilter{
-		Limit:        limit,
-		Offset:       offset,
-		Name:         name,
-		OrderBy:      orderBy,
-		ComparisonId: comparisonId,
+		Limit:            limit,
+		Offset:           offset,
+		Cursor:           cursor,
+		Name:             name,
+		Query:            query,
+		OrderBy:          sortKeys,
+		ComparisonId:     comparisonId,
+		OptionPredicates: optionPredicates,
 	}, nil
 }
+
+// Validate checks fields of the object. It is the only place these rules are kept,
+// objects given to the API and imported from tables are checked by it alike.
+func (o Object) Validate() error {
+	problems := make([]string, 0)
+
+	if o.Name == "" {
+		problems = append(problems, "name: cannot be blank")
+	} else if utf8.RuneCountInString(o.Name) > 50 {
+		problems = append(problems, "name: the length must be between 1 and 50")
+	}
+
+	if o.Rating == 0 {
+		problems = append(problems, "rating: cannot be blank")
+	} else if o.Rating < 1 || o.Rating > 10 {
+		problems = append(problems, "rating: must be between 1 and 10")
+	}
+
+	if utf8.RuneCountInString(o.Advs) > 3000 {
+		problems = append(problems, "advs: the length must be between 1 and 3000")
+	}
+
+	if utf8.RuneCountInString(o.Disadvs) > 3000 {
+		problems = append(problems, "disadvs: the length must be between 1 and 3000")
+	}
+
+	for _, oco := range o.ObjectCustomOptions {
+		if length := utf8.RuneCountInString(oco.Value); length < 1 || length > 100 {
+			problems = append(
+				problems,
+				fmt.Sprintf("value of '%s': the length must be between 1 and 100", oco.CustomOptionId),
+			)
+		}
+	}
+
+	if len(problems) != 0 {
+		return fmt.Errorf("%s - %w", strings.Join(problems, "; "), ErrInvalidValue)
+	}
+
+	return nil
+}
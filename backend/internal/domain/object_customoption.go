@@ -4,4 +4,5 @@ type ObjectCustomOption struct {
 	ObjectId       string
 	CustomOptionId string
 	Value          string
+	TypedValue     any
 }
@@ -0,0 +1,31 @@
+package domain
+
+import "fmt"
+
+const DefaultLimit = 10
+
+// PageInfo describes a page of a list. Cursors are opaque, empty cursor means
+// there is no page in that direction.
+type PageInfo struct {
+	Total      int64
+	NextCursor string
+	PrevCursor string
+}
+
+// validatePage checks pagination parameters shared by all list filters
+// and returns the limit to use.
+func validatePage(limit, offset int, cursor string) (int, error) {
+	if offset < 0 || limit < 0 {
+		return 0, fmt.Errorf("offset and limit must not be less than zero")
+	}
+
+	if offset != 0 && cursor != "" {
+		return 0, fmt.Errorf("offset and cursor must not be used together")
+	}
+
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+
+	return limit, nil
+}
@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// PhotoInfo describes a photo kept in a photo store under its key. ETag is
+// derived from the photo content, so it changes whenever the content does.
+type PhotoInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ETag        string
+	ModifiedAt  time.Time
+}
+
+type PhotoSize string
+
+const (
+	PhotoSizeThumb    PhotoSize = "thumb"
+	PhotoSizeMedium   PhotoSize = "medium"
+	PhotoSizeOriginal PhotoSize = "original"
+)
+
+var ProvidedPhotoSizes = []PhotoSize{
+	PhotoSizeThumb,
+	PhotoSizeMedium,
+	PhotoSizeOriginal,
+}
+
+// ParsePhotoSize parses size of the photo variant, empty size means the original.
+func ParsePhotoSize(size string) (PhotoSize, error) {
+	if size == "" {
+		return PhotoSizeOriginal, nil
+	}
+
+	for _, s := range ProvidedPhotoSizes {
+		if PhotoSize(size) == s {
+			return s, nil
+		}
+	}
+
+	return "", fmt.Errorf("photo size must be one of %v - %w", ProvidedPhotoSizes, ErrInvalidValue)
+}
+
+// PhotoVariant is the photo prepared for storing in one of provided sizes.
+type PhotoVariant struct {
+	Size        PhotoSize
+	Content     []byte
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+}
+
+// PhotoVariantKey gives key of the photo variant derived from the key of the original,
+// e.g. "1a2b.jpg" gives "1a2b_thumb.jpg" for thumbnail.
+func PhotoVariantKey(key string, size PhotoSize) string {
+	if size == PhotoSizeOriginal {
+		return key
+	}
+
+	ext := path.Ext(key)
+
+	return fmt.Sprintf("%s_%s%s", strings.TrimSuffix(key, ext), size, ext)
+}
+
+// MaxObjectPhotos limits the number of photos in the gallery of one object.
+const MaxObjectPhotos = 20
+
+// Photo is one of the photos of the object gallery. Key refers to the original
+// kept in the photo store, keys of other sizes are derived from it.
+type Photo struct {
+	Id          string
+	ObjectId    string
+	Key         string
+	ContentType string
+	Size        int64
+	Width       int
+	Height      int
+	Position    int
+	IsCover     bool
+	CreatedAt   time.Time
+}
+
+// Keys gives keys of all sizes of the photo kept in the photo store.
+func (p Photo) Keys() []string {
+	keys := make([]string, len(ProvidedPhotoSizes))
+	for i, size := range ProvidedPhotoSizes {
+		keys[i] = PhotoVariantKey(p.Key, size)
+	}
+
+	return keys
+}
+
+// PhotosKeys gives keys of all sizes of the photos kept in the photo store.
+func PhotosKeys(photos []Photo) []string {
+	keys := make([]string, 0, len(photos)*len(ProvidedPhotoSizes))
+	for _, photo := range photos {
+		keys = append(keys, photo.Keys()...)
+	}
+
+	return keys
+}
+
+// ValidatePhotoOrder checks that ids list every photo of the gallery exactly once.
+func ValidatePhotoOrder(photos []Photo, ids []string) error {
+	if len(ids) != len(photos) {
+		return fmt.Errorf("order must list all %d photos of the object - %w", len(photos), ErrInvalidValue)
+	}
+
+	positions := make(map[string]bool, len(photos))
+	for _, photo := range photos {
+		positions[photo.Id] = false
+	}
+
+	for _, id := range ids {
+		listed, ok := positions[id]
+		if !ok {
+			return fmt.Errorf("photo '%s' of the object %w", id, ErrNotFound)
+		}
+
+		if listed {
+			return fmt.Errorf("photo '%s' is listed more than once - %w", id, ErrInvalidValue)
+		}
+
+		positions[id] = true
+	}
+
+	return nil
+}
+
+// OrphanedPhotosReport describes a pass of collecting photos kept in the photo store
+// that no photo of any object refers to. Orphaned photos are not deleted on dry run.
+type OrphanedPhotosReport struct {
+	Scanned        int
+	Orphaned       []PhotoInfo
+	Deleted        int
+	ReclaimedBytes int64
+	DryRun         bool
+}
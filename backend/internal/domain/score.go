@@ -0,0 +1,16 @@
+package domain
+
+type OptionScore struct {
+	CustomOptionId string
+	Value          string
+	Normalized     float64
+	Weight         float64
+}
+
+type ObjectScore struct {
+	ObjectId     string
+	Name         string
+	Score        float64
+	Rank         int
+	OptionScores []OptionScore
+}
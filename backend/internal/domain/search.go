@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ScoreOrdering orders results of a full-text search by relevance.
+const ScoreOrdering = "score"
+
+// maxFragmentLength is the number of characters of a highlighted field kept around its first hit.
+const maxFragmentLength = 120
+
+// Highlight is the fragment of the field matching the search query. For values of custom
+// options Field is "option" and CustomOptionId tells which one matched.
+type Highlight struct {
+	Field          string
+	CustomOptionId string
+	Texts          []HighlightText
+}
+
+// HighlightText is a part of the fragment, Hit parts are words matching the query.
+type HighlightText struct {
+	Value string
+	Hit   bool
+}
+
+// SearchFilter is a query searched across comparisons, objects and custom options,
+// Limit applies to every group of results.
+type SearchFilter struct {
+	Query string
+	Limit int
+}
+
+func NewSearchFilter(query string, limit int) (SearchFilter, error) {
+	limit, err := validatePage(limit, 0, "")
+	if err != nil {
+		return SearchFilter{}, err
+	}
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return SearchFilter{}, fmt.Errorf("query must not be empty")
+	}
+
+	return SearchFilter{Query: query, Limit: limit}, nil
+}
+
+// SearchResults are groups of entities found by the query, totals tell how many
+// entities of every group match it.
+type SearchResults struct {
+	Comparisons        []Comparison
+	ComparisonsTotal   int64
+	Objects            []ObjectSearchResult
+	ObjectsTotal       int64
+	CustomOptions      []CustomOption
+	CustomOptionsTotal int64
+}
+
+// ObjectSearchResult is the object found by the query with the comparison it belongs to.
+type ObjectSearchResult struct {
+	Object     Object
+	Comparison Comparison
+}
+
+// SearchTerms splits the search query into lower cased words to be highlighted.
+// Words excluded with "-" are left out.
+func SearchTerms(query string) []string {
+	terms := make([]string, 0)
+
+	for _, word := range strings.Fields(query) {
+		if strings.HasPrefix(word, "-") {
+			continue
+		}
+
+		for _, term := range strings.FieldsFunc(word, isNotWordRune) {
+			terms = append(terms, stem(strings.ToLower(term)))
+		}
+	}
+
+	return terms
+}
+
+// HighlightTerms returns the first field of the object matching any of the terms:
+// name, advantages, disadvantages and values of custom options in this order.
+func (o Object) HighlightTerms(terms []string) (Highlight, bool) {
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"name", o.Name},
+		{"advs", o.Advs},
+		{"disadvs", o.Disadvs},
+	}
+
+	for _, field := range fields {
+		if texts, ok := HighlightTexts(field.value, terms); ok {
+			return Highlight{Field: field.name, Texts: texts}, true
+		}
+	}
+
+	for _, oco := range o.ObjectCustomOptions {
+		if texts, ok := HighlightTexts(oco.Value, terms); ok {
+			return Highlight{Field: "option", CustomOptionId: oco.CustomOptionId, Texts: texts}, true
+		}
+	}
+
+	return Highlight{}, false
+}
+
+// HighlightTexts splits the text into parts marking words matching any of the terms.
+// Words are matched with common English suffixes stripped, close to the way the text
+// index of mongo does it. Long text is cut to a fragment around its first hit.
+func HighlightTexts(text string, terms []string) ([]HighlightText, bool) {
+	runes := []rune(text)
+
+	type hit struct{ start, end int }
+	hits := make([]hit, 0)
+
+	for start := 0; start < len(runes); {
+		if isNotWordRune(runes[start]) {
+			start++
+			continue
+		}
+
+		end := start
+		for end < len(runes) && !isNotWordRune(runes[end]) {
+			end++
+		}
+
+		word := stem(strings.ToLower(string(runes[start:end])))
+		for _, term := range terms {
+			if word == term {
+				hits = append(hits, hit{start, end})
+				break
+			}
+		}
+
+		start = end
+	}
+
+	if len(hits) == 0 {
+		return nil, false
+	}
+
+	from, to := 0, len(runes)
+	if len(runes) > maxFragmentLength {
+		from = max(hits[0].start-maxFragmentLength/4, 0)
+		to = min(from+maxFragmentLength, len(runes))
+	}
+
+	texts := make([]HighlightText, 0, len(hits)*2+1)
+	if from > 0 {
+		texts = append(texts, HighlightText{Value: "..."})
+	}
+
+	pos := from
+	for _, h := range hits {
+		if h.start < from || h.end > to {
+			continue
+		}
+
+		if h.start > pos {
+			texts = append(texts, HighlightText{Value: string(runes[pos:h.start])})
+		}
+
+		texts = append(texts, HighlightText{Value: string(runes[h.start:h.end]), Hit: true})
+		pos = h.end
+	}
+
+	if pos < to {
+		texts = append(texts, HighlightText{Value: string(runes[pos:to])})
+	}
+
+	if to < len(runes) {
+		texts = append(texts, HighlightText{Value: "..."})
+	}
+
+	return mergeTexts(texts), true
+}
+
+// mergeTexts joins adjacent parts that are not hits.
+func mergeTexts(texts []HighlightText) []HighlightText {
+	merged := make([]HighlightText, 0, len(texts))
+	for _, t := range texts {
+		if last := len(merged) - 1; last >= 0 && !t.Hit && !merged[last].Hit {
+			merged[last].Value += t.Value
+			continue
+		}
+
+		merged = append(merged, t)
+	}
+
+	return merged
+}
+
+// stem strips common English suffixes, so that e.g. "batteries" matches "battery".
+func stem(word string) string {
+	for _, suffix := range []string{"ies", "es", "s", "ing", "ed", "y"} {
+		if base, ok := strings.CutSuffix(word, suffix); ok && len([]rune(base)) >= 3 {
+			return base
+		}
+	}
+
+	return word
+}
+
+func isNotWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SortKey is a field a list is ordered by.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// parseSortKeys parses comma separated fields like "-rating,name", where fields
+// prefixed with "-" are sorted in descending order. Each field must be accepted
+// by isAllowed and may be used only once. Empty orderBy results in defaultKeys.
+func parseSortKeys(orderBy string, defaultKeys []SortKey, isAllowed func(field string) bool) ([]SortKey, error) {
+	if orderBy == "" {
+		return defaultKeys, nil
+	}
+
+	parts := strings.Split(orderBy, ",")
+	keys := make([]SortKey, 0, len(parts))
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		field, desc := strings.CutPrefix(part, "-")
+
+		if field == "" || !isAllowed(field) {
+			return nil, fmt.Errorf("incorrect ordering value '%s'", part)
+		}
+
+		if slices.ContainsFunc(keys, func(k SortKey) bool { return k.Field == field }) {
+			return nil, fmt.Errorf("ordering by '%s' is duplicated", field)
+		}
+
+		keys = append(keys, SortKey{Field: field, Desc: desc})
+	}
+
+	return keys, nil
+}
+
+func allowedFields(fields ...string) func(field string) bool {
+	return func(field string) bool {
+		return slices.Contains(fields, field)
+	}
+}
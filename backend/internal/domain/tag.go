@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxTagLength      = 30
+	MaxComparisonTags = 20
+)
+
+// TagUsage tells how many comparisons out of the trash are tagged with the tag.
+type TagUsage struct {
+	Name  string
+	Count int
+}
+
+// NormalizeTag gives the tag trimmed and lower cased, so that tags differing
+// only in case are the same tag.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// NormalizeTags gives normalized tags without repeats, keeping the order they are given in.
+func NormalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+
+		if tag == "" {
+			return nil, fmt.Errorf("tag must not be empty - %w", ErrInvalidValue)
+		}
+
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, fmt.Errorf(
+				"tag '%s' is longer than %d characters - %w",
+				tag,
+				MaxTagLength,
+				ErrInvalidValue,
+			)
+		}
+
+		if !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	if len(normalized) > MaxComparisonTags {
+		return nil, fmt.Errorf("comparison can have up to %d tags - %w", MaxComparisonTags, ErrInvalidValue)
+	}
+
+	return normalized, nil
+}
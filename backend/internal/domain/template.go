@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// TemplateOption describes a custom option used by comparisons created from the template.
+// The option is looked up by name, Type, EnumValues and Currency are the defaults used
+// only to create it when there is no option with such name yet.
+type TemplateOption struct {
+	Name       string
+	Type       CustomOptionType
+	EnumValues []string
+	Currency   string
+	Weight     float64
+	Direction  ScoreDirection
+}
+
+// CustomOption gives the custom option created for the template option when it does not exist.
+func (o TemplateOption) CustomOption() CustomOption {
+	return CustomOption{
+		Name:       o.Name,
+		Type:       o.Type,
+		EnumValues: o.EnumValues,
+		Currency:   o.Currency,
+	}
+}
+
+// Matches reports whether the existing custom option of the same name can stand for the
+// template option, i.e. it has the same type and, for money, the same currency.
+func (o TemplateOption) Matches(co CustomOption) bool {
+	return co.Type == o.Type && co.Currency == o.Currency
+}
+
+// Template is a reusable set of custom options with default weights for new comparisons.
+// Built-in templates are shipped with the application and can not be changed.
+type Template struct {
+	Id          string
+	Name        string
+	Description string
+	Options     []TemplateOption
+	BuiltIn     bool
+	CreatedAt   time.Time
+}
+
+// Validate checks that options of the template are valid custom options with unique names
+// and correct scoring settings.
+func (t Template) Validate() error {
+	names := make([]string, 0, len(t.Options))
+
+	for _, o := range t.Options {
+		if slices.Contains(names, o.Name) {
+			return fmt.Errorf("custom option '%s' is used more than once - %w", o.Name, ErrInvalidValue)
+		}
+
+		names = append(names, o.Name)
+
+		if err := o.CustomOption().Validate(); err != nil {
+			return fmt.Errorf("invalid custom option '%s' - %w", o.Name, err)
+		}
+
+		if o.Weight < 0 {
+			return fmt.Errorf("weight must not be less than zero - %w", ErrInvalidValue)
+		}
+
+		if !slices.Contains(ProvidedScoreDirections, o.Direction) {
+			return fmt.Errorf("unknown score direction '%s' - %w", o.Direction, ErrInvalidValue)
+		}
+	}
+
+	return nil
+}
+
+// OptionNames returns names of custom options of the template in their order.
+func (t Template) OptionNames() []string {
+	names := make([]string, len(t.Options))
+	for i, o := range t.Options {
+		names[i] = o.Name
+	}
+
+	return names
+}
+
+// ApplyTo gives the comparison with custom options of the template placed before its own
+// ones. ids maps names of the template options to ids of the custom options. Weights set
+// for the comparison take precedence over default weights of the template.
+func (t Template) ApplyTo(c Comparison, ids map[string]string) Comparison {
+	customOptionIds := make([]string, 0, len(t.Options)+len(c.CustomOptionIds))
+	optionWeights := make([]OptionWeight, 0, len(t.Options)+len(c.OptionWeights))
+
+	for _, o := range t.Options {
+		id := ids[o.Name]
+		if slices.Contains(customOptionIds, id) {
+			continue
+		}
+
+		customOptionIds = append(customOptionIds, id)
+
+		weighted := slices.ContainsFunc(c.OptionWeights, func(ow OptionWeight) bool {
+			return ow.CustomOptionId == id
+		})
+		if !weighted {
+			optionWeights = append(optionWeights, OptionWeight{
+				CustomOptionId: id,
+				Weight:         o.Weight,
+				Direction:      o.Direction,
+			})
+		}
+	}
+
+	for _, id := range c.CustomOptionIds {
+		if !slices.Contains(customOptionIds, id) {
+			customOptionIds = append(customOptionIds, id)
+		}
+	}
+
+	c.CustomOptionIds = customOptionIds
+	c.OptionWeights = append(optionWeights, c.OptionWeights...)
+
+	return c
+}
+
+type TemplateFilter struct {
+	Limit   int
+	Offset  int
+	Cursor  string
+	OrderBy []SortKey
+	Name    string
+}
+
+var providedTemplateOrderings = []string{"name", "created_at"}
+
+// NewTemplateFilter creates filter for a page starting either at offset or at cursor
+// returned with one of previous pages.
+func NewTemplateFilter(limit, offset int, cursor, orderBy, name string) (TemplateFilter, error) {
+	limit, err := validatePage(limit, offset, cursor)
+	if err != nil {
+		return TemplateFilter{}, err
+	}
+
+	sortKeys, err := parseSortKeys(
+		orderBy,
+		[]SortKey{{Field: "name"}},
+		allowedFields(providedTemplateOrderings...),
+	)
+	if err != nil {
+		return TemplateFilter{}, err
+	}
+
+	return TemplateFilter{
+		Limit:   limit,
+		Offset:  offset,
+		Cursor:  cursor,
+		OrderBy: sortKeys,
+		Name:    name,
+	}, nil
+}
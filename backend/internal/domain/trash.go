@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+type TrashItemType string
+
+const (
+	TrashItemTypeComparison TrashItemType = "comparison"
+	TrashItemTypeObject     TrashItemType = "object"
+)
+
+// TrashItem is a deleted comparison or object that can be restored until it is purged.
+// Objects deleted together with their comparison are restored and purged with it,
+// so they are not listed as separate items.
+type TrashItem struct {
+	Type         TrashItemType
+	Id           string
+	Name         string
+	ComparisonId string
+	DeletedAt    time.Time
+}
+
+// TrashPurgeReport describes items removed from the trash for good.
+type TrashPurgeReport struct {
+	Comparisons int
+	Objects     int
+	Photos      int
+	// LeftoverPhotoKeys are files of purged photos that could not be removed,
+	// they are left for the photo garbage collector.
+	LeftoverPhotoKeys []string
+}
+
+// TrashFilter selects a page of the trash, recently deleted items go first.
+type TrashFilter struct {
+	Limit  int
+	Offset int
+	Cursor string
+}
+
+// NewTrashFilter creates filter for a page starting either at offset or at cursor
+// returned with one of previous pages.
+func NewTrashFilter(limit, offset int, cursor string) (TrashFilter, error) {
+	limit, err := validatePage(limit, offset, cursor)
+	if err != nil {
+		return TrashFilter{}, err
+	}
+
+	return TrashFilter{
+		Limit:  limit,
+		Offset: offset,
+		Cursor: cursor,
+	}, nil
+}
@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type AuditRepositoryMock struct {
+	mock.Mock
+}
+
+func NewAuditRepositoryMock() *AuditRepositoryMock {
+	return &AuditRepositoryMock{}
+}
+
+func (repo *AuditRepositoryMock) AddAuditEvent(ctx context.Context, event domain.AuditEvent) error {
+	args := repo.Called(ctx, event)
+
+	return args.Error(0)
+}
+
+func (repo *AuditRepositoryMock) GetAuditEvents(
+	ctx context.Context,
+	filter domain.AuditFilter,
+) ([]domain.AuditEvent, domain.PageInfo, error) {
+	args := repo.Called(ctx, filter)
+
+	ret, pageInfoRet, err := args.Get(0), args.Get(1), args.Error(2)
+
+	var events []domain.AuditEvent
+
+	if ret != nil {
+		events = ret.([]domain.AuditEvent)
+	}
+
+	var pageInfo domain.PageInfo
+
+	if pageInfoRet != nil {
+		pageInfo = pageInfoRet.(domain.PageInfo)
+	}
+
+	return events, pageInfo, err
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/Unlites/comparison_center/backend/internal/domain"
 	"github.com/stretchr/testify/mock"
@@ -18,9 +19,32 @@ func NewComparisonRepositoryMock() *ComparisonRepositoryMock {
 func (repo *ComparisonRepositoryMock) GetComparisons(
 	ctx context.Context,
 	filter domain.ComparisonFilter,
-) ([]domain.Comparison, error) {
+) ([]domain.Comparison, domain.PageInfo, error) {
 	args := repo.Called(ctx, filter)
 
+	ret, pageInfoRet, err := args.Get(0), args.Get(1), args.Error(2)
+
+	var comparisons []domain.Comparison
+
+	if ret != nil {
+		comparisons = ret.([]domain.Comparison)
+	}
+
+	var pageInfo domain.PageInfo
+
+	if pageInfoRet != nil {
+		pageInfo = pageInfoRet.(domain.PageInfo)
+	}
+
+	return comparisons, pageInfo, err
+}
+
+func (repo *ComparisonRepositoryMock) GetComparisonsByCustomOptionId(
+	ctx context.Context,
+	customOptionId string,
+) ([]domain.Comparison, error) {
+	args := repo.Called(ctx, customOptionId)
+
 	ret, err := args.Get(0), args.Error(1)
 
 	var comparisons []domain.Comparison
@@ -32,6 +56,54 @@ func (repo *ComparisonRepositoryMock) GetComparisons(
 	return comparisons, err
 }
 
+func (repo *ComparisonRepositoryMock) GetComparisonsByFolderId(
+	ctx context.Context,
+	folderId string,
+) ([]domain.Comparison, error) {
+	args := repo.Called(ctx, folderId)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var comparisons []domain.Comparison
+
+	if ret != nil {
+		comparisons = ret.([]domain.Comparison)
+	}
+
+	return comparisons, err
+}
+
+func (repo *ComparisonRepositoryMock) GetComparisonsByIds(
+	ctx context.Context,
+	ids []string,
+) ([]domain.Comparison, error) {
+	args := repo.Called(ctx, ids)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var comparisons []domain.Comparison
+
+	if ret != nil {
+		comparisons = ret.([]domain.Comparison)
+	}
+
+	return comparisons, err
+}
+
+func (repo *ComparisonRepositoryMock) GetTagUsages(ctx context.Context) ([]domain.TagUsage, error) {
+	args := repo.Called(ctx)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var tags []domain.TagUsage
+
+	if ret != nil {
+		tags = ret.([]domain.TagUsage)
+	}
+
+	return tags, err
+}
+
 func (repo *ComparisonRepositoryMock) GetComparisonById(
 	ctx context.Context,
 	id string,
@@ -70,8 +142,81 @@ func (repo *ComparisonRepositoryMock) CreateComparison(
 func (repo *ComparisonRepositoryMock) DeleteComparison(
 	ctx context.Context,
 	id string,
+	deletedAt time.Time,
+) error {
+	args := repo.Called(ctx, id, deletedAt)
+
+	return args.Error(0)
+}
+
+func (repo *ComparisonRepositoryMock) GetDeletedComparisons(
+	ctx context.Context,
+	deletedBefore time.Time,
+) ([]domain.Comparison, error) {
+	args := repo.Called(ctx, deletedBefore)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var comparisons []domain.Comparison
+
+	if ret != nil {
+		comparisons = ret.([]domain.Comparison)
+	}
+
+	return comparisons, err
+}
+
+func (repo *ComparisonRepositoryMock) GetDeletedComparisonById(
+	ctx context.Context,
+	id string,
+) (domain.Comparison, error) {
+	args := repo.Called(ctx, id)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var comparison domain.Comparison
+
+	if ret != nil {
+		comparison = ret.(domain.Comparison)
+	}
+
+	return comparison, err
+}
+
+func (repo *ComparisonRepositoryMock) RestoreComparison(
+	ctx context.Context,
+	comparison domain.Comparison,
+) error {
+	args := repo.Called(ctx, comparison)
+
+	return args.Error(0)
+}
+
+func (repo *ComparisonRepositoryMock) PurgeComparison(
+	ctx context.Context,
+	id string,
 ) error {
 	args := repo.Called(ctx, id)
 
 	return args.Error(0)
 }
+
+func (repo *ComparisonRepositoryMock) GetAllComparisons(ctx context.Context) ([]domain.Comparison, error) {
+	args := repo.Called(ctx)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var comparisons []domain.Comparison
+
+	if ret != nil {
+		comparisons = ret.([]domain.Comparison)
+	}
+
+	return comparisons, err
+}
+
+func (repo *ComparisonRepositoryMock) CreateComparisons(ctx context.Context, comparisons []domain.Comparison) error {
+	args := repo.Called(ctx, comparisons)
+
+	return args.Error(0)
+}
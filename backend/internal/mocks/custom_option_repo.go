@@ -18,9 +18,49 @@ func NewCustomOptionRepositoryMock() *CustomOptionRepositoryMock {
 func (repo *CustomOptionRepositoryMock) GetCustomOptions(
 	ctx context.Context,
 	filter domain.CustomOptionFilter,
-) ([]domain.CustomOption, error) {
+) ([]domain.CustomOption, domain.PageInfo, error) {
 	args := repo.Called(ctx, filter)
 
+	ret, pageInfoRet, err := args.Get(0), args.Get(1), args.Error(2)
+
+	var customOptions []domain.CustomOption
+
+	if ret != nil {
+		customOptions = ret.([]domain.CustomOption)
+	}
+
+	var pageInfo domain.PageInfo
+
+	if pageInfoRet != nil {
+		pageInfo = pageInfoRet.(domain.PageInfo)
+	}
+
+	return customOptions, pageInfo, err
+}
+
+func (repo *CustomOptionRepositoryMock) GetCustomOptionsByIds(
+	ctx context.Context,
+	ids []string,
+) ([]domain.CustomOption, error) {
+	args := repo.Called(ctx, ids)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var customOptions []domain.CustomOption
+
+	if ret != nil {
+		customOptions = ret.([]domain.CustomOption)
+	}
+
+	return customOptions, err
+}
+
+func (repo *CustomOptionRepositoryMock) GetCustomOptionsByNames(
+	ctx context.Context,
+	names []string,
+) ([]domain.CustomOption, error) {
+	args := repo.Called(ctx, names)
+
 	ret, err := args.Get(0), args.Error(1)
 
 	var customOptions []domain.CustomOption
@@ -75,3 +115,23 @@ func (repo *CustomOptionRepositoryMock) DeleteCustomOption(
 
 	return args.Error(0)
 }
+
+func (repo *CustomOptionRepositoryMock) GetAllCustomOptions(ctx context.Context) ([]domain.CustomOption, error) {
+	args := repo.Called(ctx)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var customOptions []domain.CustomOption
+
+	if ret != nil {
+		customOptions = ret.([]domain.CustomOption)
+	}
+
+	return customOptions, err
+}
+
+func (repo *CustomOptionRepositoryMock) CreateCustomOptions(ctx context.Context, customOptions []domain.CustomOption) error {
+	args := repo.Called(ctx, customOptions)
+
+	return args.Error(0)
+}
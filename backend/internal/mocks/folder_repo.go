@@ -0,0 +1,99 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type FolderRepositoryMock struct {
+	mock.Mock
+}
+
+func NewFolderRepositoryMock() *FolderRepositoryMock {
+	return &FolderRepositoryMock{}
+}
+
+func (repo *FolderRepositoryMock) GetFolders(ctx context.Context) ([]domain.Folder, error) {
+	args := repo.Called(ctx)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var folders []domain.Folder
+
+	if ret != nil {
+		folders = ret.([]domain.Folder)
+	}
+
+	return folders, err
+}
+
+func (repo *FolderRepositoryMock) GetFolderById(ctx context.Context, id string) (domain.Folder, error) {
+	args := repo.Called(ctx, id)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var folder domain.Folder
+
+	if ret != nil {
+		folder = ret.(domain.Folder)
+	}
+
+	return folder, err
+}
+
+func (repo *FolderRepositoryMock) GetFoldersByParentId(
+	ctx context.Context,
+	parentId string,
+) ([]domain.Folder, error) {
+	args := repo.Called(ctx, parentId)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var folders []domain.Folder
+
+	if ret != nil {
+		folders = ret.([]domain.Folder)
+	}
+
+	return folders, err
+}
+
+func (repo *FolderRepositoryMock) GetFolderDescendantIds(ctx context.Context, id string) ([]string, error) {
+	args := repo.Called(ctx, id)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var ids []string
+
+	if ret != nil {
+		ids = ret.([]string)
+	}
+
+	return ids, err
+}
+
+func (repo *FolderRepositoryMock) CreateFolder(ctx context.Context, folder domain.Folder) error {
+	args := repo.Called(ctx, folder)
+
+	return args.Error(0)
+}
+
+func (repo *FolderRepositoryMock) UpdateFolder(ctx context.Context, folder domain.Folder) error {
+	args := repo.Called(ctx, folder)
+
+	return args.Error(0)
+}
+
+func (repo *FolderRepositoryMock) DeleteFolder(ctx context.Context, id string) error {
+	args := repo.Called(ctx, id)
+
+	return args.Error(0)
+}
+
+func (repo *FolderRepositoryMock) CreateFolders(ctx context.Context, folders []domain.Folder) error {
+	args := repo.Called(ctx, folders)
+
+	return args.Error(0)
+}
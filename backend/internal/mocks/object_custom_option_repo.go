@@ -32,6 +32,23 @@ func (repo *ObjectCustomOptionRepositoryMock) GetObjectCustomOptionsByObjectId(
 	return objectCustomOptions, err
 }
 
+func (repo *ObjectCustomOptionRepositoryMock) GetObjectCustomOptionsByObjectIds(
+	ctx context.Context,
+	objectIds []string,
+) ([]domain.ObjectCustomOption, error) {
+	args := repo.Called(ctx, objectIds)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var objectCustomOptions []domain.ObjectCustomOption
+
+	if ret != nil {
+		objectCustomOptions = ret.([]domain.ObjectCustomOption)
+	}
+
+	return objectCustomOptions, err
+}
+
 func (repo *ObjectCustomOptionRepositoryMock) AddObjectCustomOption(
 	ctx context.Context,
 	objectCustomOption domain.ObjectCustomOption,
@@ -49,3 +66,58 @@ func (repo *ObjectCustomOptionRepositoryMock) UpdateObjectCustomOption(
 
 	return args.Error(0)
 }
+
+func (repo *ObjectCustomOptionRepositoryMock) DeleteObjectCustomOptionsByObjectIds(
+	ctx context.Context,
+	objectIds []string,
+) error {
+	args := repo.Called(ctx, objectIds)
+
+	return args.Error(0)
+}
+
+func (repo *ObjectCustomOptionRepositoryMock) DeleteObjectCustomOptionsByCustomOptionId(
+	ctx context.Context,
+	customOptionId string,
+) error {
+	args := repo.Called(ctx, customOptionId)
+
+	return args.Error(0)
+}
+
+func (repo *ObjectCustomOptionRepositoryMock) GetObjectCustomOptionsByCustomOptionId(
+	ctx context.Context,
+	customOptionId string,
+) ([]domain.ObjectCustomOption, error) {
+	args := repo.Called(ctx, customOptionId)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var objectCustomOptions []domain.ObjectCustomOption
+
+	if ret != nil {
+		objectCustomOptions = ret.([]domain.ObjectCustomOption)
+	}
+
+	return objectCustomOptions, err
+}
+
+func (repo *ObjectCustomOptionRepositoryMock) GetAllObjectCustomOptions(ctx context.Context) ([]domain.ObjectCustomOption, error) {
+	args := repo.Called(ctx)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var objCustomOptions []domain.ObjectCustomOption
+
+	if ret != nil {
+		objCustomOptions = ret.([]domain.ObjectCustomOption)
+	}
+
+	return objCustomOptions, err
+}
+
+func (repo *ObjectCustomOptionRepositoryMock) AddObjectCustomOptions(ctx context.Context, objCustomOptions []domain.ObjectCustomOption) error {
+	args := repo.Called(ctx, objCustomOptions)
+
+	return args.Error(0)
+}
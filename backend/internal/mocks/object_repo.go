@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/Unlites/comparison_center/backend/internal/domain"
 	"github.com/stretchr/testify/mock"
@@ -18,9 +19,49 @@ func NewObjectRepositoryMock() *ObjectRepositoryMock {
 func (repo *ObjectRepositoryMock) GetObjects(
 	ctx context.Context,
 	filter domain.ObjectFilter,
-) ([]domain.Object, error) {
+) ([]domain.Object, domain.PageInfo, error) {
 	args := repo.Called(ctx, filter)
 
+	ret, pageInfoRet, err := args.Get(0), args.Get(1), args.Error(2)
+
+	var objects []domain.Object
+
+	if ret != nil {
+		objects = ret.([]domain.Object)
+	}
+
+	var pageInfo domain.PageInfo
+
+	if pageInfoRet != nil {
+		pageInfo = pageInfoRet.(domain.PageInfo)
+	}
+
+	return objects, pageInfo, err
+}
+
+func (repo *ObjectRepositoryMock) GetObjectsByComparisonId(
+	ctx context.Context,
+	comparisonId string,
+) ([]domain.Object, error) {
+	args := repo.Called(ctx, comparisonId)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var objects []domain.Object
+
+	if ret != nil {
+		objects = ret.([]domain.Object)
+	}
+
+	return objects, err
+}
+
+func (repo *ObjectRepositoryMock) GetObjectsWithOptionsByComparisonId(
+	ctx context.Context,
+	comparisonId string,
+) ([]domain.Object, error) {
+	args := repo.Called(ctx, comparisonId)
+
 	ret, err := args.Get(0), args.Error(1)
 
 	var objects []domain.Object
@@ -58,8 +99,127 @@ func (repo *ObjectRepositoryMock) CreateObject(ctx context.Context, object domai
 	return args.Error(0)
 }
 
-func (repo *ObjectRepositoryMock) DeleteObject(ctx context.Context, id string) error {
+func (repo *ObjectRepositoryMock) DeleteObject(ctx context.Context, id string, deletedAt time.Time) error {
+	args := repo.Called(ctx, id, deletedAt)
+
+	return args.Error(0)
+}
+
+func (repo *ObjectRepositoryMock) DeleteObjectsByComparisonId(
+	ctx context.Context,
+	comparisonId string,
+	deletedAt time.Time,
+) error {
+	args := repo.Called(ctx, comparisonId, deletedAt)
+
+	return args.Error(0)
+}
+
+func (repo *ObjectRepositoryMock) GetDeletedObjects(
+	ctx context.Context,
+	deletedBefore time.Time,
+) ([]domain.Object, error) {
+	args := repo.Called(ctx, deletedBefore)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var objects []domain.Object
+
+	if ret != nil {
+		objects = ret.([]domain.Object)
+	}
+
+	return objects, err
+}
+
+func (repo *ObjectRepositoryMock) GetDeletedObjectById(ctx context.Context, id string) (domain.Object, error) {
+	args := repo.Called(ctx, id)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var object domain.Object
+
+	if ret != nil {
+		object = ret.(domain.Object)
+	}
+
+	return object, err
+}
+
+func (repo *ObjectRepositoryMock) GetDeletedObjectsByComparisonId(
+	ctx context.Context,
+	comparisonId string,
+) ([]domain.Object, error) {
+	args := repo.Called(ctx, comparisonId)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var objects []domain.Object
+
+	if ret != nil {
+		objects = ret.([]domain.Object)
+	}
+
+	return objects, err
+}
+
+func (repo *ObjectRepositoryMock) GetObjectsDeletedWithComparison(
+	ctx context.Context,
+	comparisonId string,
+) ([]domain.Object, error) {
+	args := repo.Called(ctx, comparisonId)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var objects []domain.Object
+
+	if ret != nil {
+		objects = ret.([]domain.Object)
+	}
+
+	return objects, err
+}
+
+func (repo *ObjectRepositoryMock) RestoreObject(ctx context.Context, id string) error {
 	args := repo.Called(ctx, id)
 
 	return args.Error(0)
 }
+
+func (repo *ObjectRepositoryMock) RestoreObjectsByComparisonId(ctx context.Context, comparisonId string) error {
+	args := repo.Called(ctx, comparisonId)
+
+	return args.Error(0)
+}
+
+func (repo *ObjectRepositoryMock) PurgeObjects(ctx context.Context, ids []string) error {
+	args := repo.Called(ctx, ids)
+
+	return args.Error(0)
+}
+
+func (repo *ObjectRepositoryMock) GetAllObjects(ctx context.Context) ([]domain.Object, error) {
+	args := repo.Called(ctx)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var objects []domain.Object
+
+	if ret != nil {
+		objects = ret.([]domain.Object)
+	}
+
+	return objects, err
+}
+
+func (repo *ObjectRepositoryMock) CreateObjects(ctx context.Context, objects []domain.Object) error {
+	args := repo.Called(ctx, objects)
+
+	return args.Error(0)
+}
+
+func (repo *ObjectRepositoryMock) UpdateObjectsOptionValues(ctx context.Context, objects []domain.Object) error {
+	args := repo.Called(ctx, objects)
+
+	return args.Error(0)
+}
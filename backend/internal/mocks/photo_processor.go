@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"context"
+	"io"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type PhotoProcessorMock struct {
+	mock.Mock
+}
+
+func NewPhotoProcessorMock() *PhotoProcessorMock {
+	return &PhotoProcessorMock{}
+}
+
+func (p *PhotoProcessorMock) Process(ctx context.Context, content io.Reader) ([]domain.PhotoVariant, error) {
+	args := p.Called(ctx, content)
+
+	variants, _ := args.Get(0).([]domain.PhotoVariant)
+
+	return variants, args.Error(1)
+}
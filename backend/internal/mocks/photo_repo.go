@@ -0,0 +1,105 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type PhotoRepositoryMock struct {
+	mock.Mock
+}
+
+func NewPhotoRepositoryMock() *PhotoRepositoryMock {
+	return &PhotoRepositoryMock{}
+}
+
+func (repo *PhotoRepositoryMock) GetPhotosByObjectIds(
+	ctx context.Context,
+	objectIds []string,
+) ([]domain.Photo, error) {
+	args := repo.Called(ctx, objectIds)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var photos []domain.Photo
+
+	if ret != nil {
+		photos = ret.([]domain.Photo)
+	}
+
+	return photos, err
+}
+
+func (repo *PhotoRepositoryMock) GetPhotoById(ctx context.Context, id string) (domain.Photo, error) {
+	args := repo.Called(ctx, id)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var photo domain.Photo
+
+	if ret != nil {
+		photo = ret.(domain.Photo)
+	}
+
+	return photo, err
+}
+
+func (repo *PhotoRepositoryMock) GetPhotoKeys(ctx context.Context) ([]string, error) {
+	args := repo.Called(ctx)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var keys []string
+
+	if ret != nil {
+		keys = ret.([]string)
+	}
+
+	return keys, err
+}
+
+func (repo *PhotoRepositoryMock) CreatePhoto(ctx context.Context, photo domain.Photo) error {
+	args := repo.Called(ctx, photo)
+
+	return args.Error(0)
+}
+
+func (repo *PhotoRepositoryMock) UpdatePhotos(ctx context.Context, photos []domain.Photo) error {
+	args := repo.Called(ctx, photos)
+
+	return args.Error(0)
+}
+
+func (repo *PhotoRepositoryMock) DeletePhoto(ctx context.Context, id string) error {
+	args := repo.Called(ctx, id)
+
+	return args.Error(0)
+}
+
+func (repo *PhotoRepositoryMock) DeletePhotosByObjectIds(ctx context.Context, objectIds []string) error {
+	args := repo.Called(ctx, objectIds)
+
+	return args.Error(0)
+}
+
+func (repo *PhotoRepositoryMock) GetAllPhotos(ctx context.Context) ([]domain.Photo, error) {
+	args := repo.Called(ctx)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var photos []domain.Photo
+
+	if ret != nil {
+		photos = ret.([]domain.Photo)
+	}
+
+	return photos, err
+}
+
+func (repo *PhotoRepositoryMock) CreatePhotos(ctx context.Context, photos []domain.Photo) error {
+	args := repo.Called(ctx, photos)
+
+	return args.Error(0)
+}
@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// PhotoContent is the photo opened by the store, returned by Get of the mock.
+type PhotoContent struct {
+	*strings.Reader
+}
+
+func NewPhotoContent(content string) PhotoContent {
+	return PhotoContent{strings.NewReader(content)}
+}
+
+func (PhotoContent) Close() error {
+	return nil
+}
+
+type PhotoStoreMock struct {
+	mock.Mock
+}
+
+func NewPhotoStoreMock() *PhotoStoreMock {
+	return &PhotoStoreMock{}
+}
+
+func (s *PhotoStoreMock) Put(
+	ctx context.Context,
+	key string,
+	content io.Reader,
+	size int64,
+	contentType string,
+) error {
+	args := s.Called(ctx, key, content, size, contentType)
+
+	return args.Error(0)
+}
+
+func (s *PhotoStoreMock) Get(ctx context.Context, key string) (io.ReadSeekCloser, domain.PhotoInfo, error) {
+	args := s.Called(ctx, key)
+
+	content, _ := args.Get(0).(io.ReadSeekCloser)
+
+	return content, args.Get(1).(domain.PhotoInfo), args.Error(2)
+}
+
+func (s *PhotoStoreMock) Stat(ctx context.Context, key string) (domain.PhotoInfo, error) {
+	args := s.Called(ctx, key)
+
+	return args.Get(0).(domain.PhotoInfo), args.Error(1)
+}
+
+func (s *PhotoStoreMock) List(ctx context.Context) ([]domain.PhotoInfo, error) {
+	args := s.Called(ctx)
+
+	photos, _ := args.Get(0).([]domain.PhotoInfo)
+
+	return photos, args.Error(1)
+}
+
+func (s *PhotoStoreMock) Delete(ctx context.Context, path string) error {
+	args := s.Called(ctx, path)
+
+	return args.Error(0)
+}
@@ -0,0 +1,92 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type TemplateRepositoryMock struct {
+	mock.Mock
+}
+
+func NewTemplateRepositoryMock() *TemplateRepositoryMock {
+	return &TemplateRepositoryMock{}
+}
+
+func (repo *TemplateRepositoryMock) GetTemplates(
+	ctx context.Context,
+	filter domain.TemplateFilter,
+) ([]domain.Template, domain.PageInfo, error) {
+	args := repo.Called(ctx, filter)
+
+	ret, pageInfoRet, err := args.Get(0), args.Get(1), args.Error(2)
+
+	var templates []domain.Template
+
+	if ret != nil {
+		templates = ret.([]domain.Template)
+	}
+
+	var pageInfo domain.PageInfo
+
+	if pageInfoRet != nil {
+		pageInfo = pageInfoRet.(domain.PageInfo)
+	}
+
+	return templates, pageInfo, err
+}
+
+func (repo *TemplateRepositoryMock) GetTemplateById(
+	ctx context.Context,
+	id string,
+) (domain.Template, error) {
+	args := repo.Called(ctx, id)
+
+	ret, err := args.Get(0), args.Error(1)
+
+	var template domain.Template
+
+	if ret != nil {
+		template = ret.(domain.Template)
+	}
+
+	return template, err
+}
+
+func (repo *TemplateRepositoryMock) CreateTemplate(
+	ctx context.Context,
+	template domain.Template,
+) error {
+	args := repo.Called(ctx, template)
+
+	return args.Error(0)
+}
+
+func (repo *TemplateRepositoryMock) UpdateTemplate(
+	ctx context.Context,
+	template domain.Template,
+) error {
+	args := repo.Called(ctx, template)
+
+	return args.Error(0)
+}
+
+func (repo *TemplateRepositoryMock) SaveBuiltInTemplate(
+	ctx context.Context,
+	template domain.Template,
+) error {
+	args := repo.Called(ctx, template)
+
+	return args.Error(0)
+}
+
+func (repo *TemplateRepositoryMock) DeleteTemplate(
+	ctx context.Context,
+	id string,
+) error {
+	args := repo.Called(ctx, id)
+
+	return args.Error(0)
+}
@@ -0,0 +1,25 @@
+package mocks
+
+import "context"
+
+// InMemoryTransactor runs functions in place and counts how transactions have finished,
+// so tests can check that multi-write usecases are executed as one unit of work.
+type InMemoryTransactor struct {
+	Committed  int
+	RolledBack int
+}
+
+func NewInMemoryTransactor() *InMemoryTransactor {
+	return &InMemoryTransactor{}
+}
+
+func (t *InMemoryTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		t.RolledBack++
+		return err
+	}
+
+	t.Committed++
+
+	return nil
+}
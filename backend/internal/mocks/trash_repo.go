@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Unlites/comparison_center/backend/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type TrashRepositoryMock struct {
+	mock.Mock
+}
+
+func NewTrashRepositoryMock() *TrashRepositoryMock {
+	return &TrashRepositoryMock{}
+}
+
+func (repo *TrashRepositoryMock) GetTrashItems(
+	ctx context.Context,
+	filter domain.TrashFilter,
+) ([]domain.TrashItem, domain.PageInfo, error) {
+	args := repo.Called(ctx, filter)
+
+	ret, pageInfoRet, err := args.Get(0), args.Get(1), args.Error(2)
+
+	var items []domain.TrashItem
+
+	if ret != nil {
+		items = ret.([]domain.TrashItem)
+	}
+
+	var pageInfo domain.PageInfo
+
+	if pageInfoRet != nil {
+		pageInfo = pageInfoRet.(domain.PageInfo)
+	}
+
+	return items, pageInfo, err
+}
@@ -18,4 +18,23 @@ export function updateComparison(comparison) {
 
 export function deleteComparison(id) {
     return http.delete(`/comparisons/${id}`)
-}
\ No newline at end of file
+}
+export function getComparisonMatrix(id) {
+    return http.get(`/comparisons/${id}/matrix`)
+}
+
+export const exportUrl = (id, format) => {
+    return `${http.defaults.baseURL}/comparisons/${id}/export?format=${format}`
+}
+
+export function importObjects(id, file, dryRun, createCustomOptions) {
+    let data = new FormData();
+    data.append('file', file, file.name);
+    data.append('dry_run', dryRun);
+    data.append('create_custom_options', createCustomOptions);
+    return http.post(`/comparisons/${id}/import`, data, {
+        headers: {
+            'Content-Type': `multipart/form-data;boundary=${data._boundary}`
+        }
+    })
+}
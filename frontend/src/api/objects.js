@@ -1,7 +1,7 @@
 import http from '@/api'
 
-export const photoUrl = (id) => {
-    return `${http.defaults.baseURL}/objects/${id}/photo`
+export const photoUrl = (id, size = 'original') => {
+    return `${http.defaults.baseURL}/objects/${id}/photo?size=${size}`
 }
 
 export function getAllObjects(comparison_id) {